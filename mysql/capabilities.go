@@ -0,0 +1,220 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// serverFlavor is the kind of MySQL compatible server, which decides the syntax and features
+// available besides the version.
+type serverFlavor string
+
+const (
+	flavorMySQL   serverFlavor = "mysql"
+	flavorPercona serverFlavor = "percona"
+	flavorMariaDB serverFlavor = "mariadb"
+	flavorTiDB    serverFlavor = "tidb"
+)
+
+var flavorNames = map[serverFlavor]string{
+	flavorMySQL:   "MySQL",
+	flavorPercona: "Percona Server",
+	flavorMariaDB: "MariaDB",
+	flavorTiDB:    "TiDB",
+}
+
+// serverFlavors are the values of the server_flavor provider option.
+var serverFlavors = []string{
+	string(flavorMySQL),
+	string(flavorPercona),
+	string(flavorMariaDB),
+	string(flavorTiDB),
+}
+
+func (f serverFlavor) String() string {
+	return flavorNames[f]
+}
+
+// capability is a feature whose support differs between flavors and versions.
+type capability string
+
+const (
+	capabilityRoles                  capability = "role support"
+	capabilityDefaultRoles           capability = "default role support"
+	capabilityAccountLock            capability = "account locking"
+	capabilityPasswordExpire         capability = "per-account password expiration"
+	capabilityRetainCurrentPassword  capability = "RETAIN CURRENT PASSWORD"
+	capabilityAuthFactors            capability = "multifactor authentication"
+	capabilityUserAttributes         capability = "user attribute support"
+	capabilityRandomPassword         capability = "random password generation"
+	capabilityPasswordReuse          capability = "password reuse policy"
+	capabilityPasswordRequireCurrent capability = "PASSWORD REQUIRE CURRENT"
+	capabilityFailedLoginTracking    capability = "failed login tracking"
+	// capabilityAlterUser also covers SHOW CREATE USER and the account options of CREATE USER.
+	capabilityAlterUser          capability = "ALTER USER"
+	capabilityNoAutoCreateUser   capability = "the NO_AUTO_CREATE_USER SQL mode"
+	capabilityPasswordFunction   capability = "the PASSWORD() function"
+	capabilityCachingSHA2Default capability = "caching_sha2_password as the default plugin"
+	capabilityDatabaseEncryption capability = "default database encryption"
+	capabilityReplicaStatus      capability = "SHOW REPLICA STATUS"
+	// capabilityUserTableGlobalGrants marks servers whose global grants are read from mysql.user,
+	// as SHOW GRANTS mixes the password hash and account options into them.
+	capabilityUserTableGlobalGrants capability = "global grants read from mysql.user"
+)
+
+// capabilityMinVersions are the first versions of each flavor with a capability. They're
+// versions of the flavor itself, e.g. 10.4.2 of MariaDB and 6.5.0 of TiDB rather than the
+// MySQL version TiDB reports compatibility with. Flavors without an entry lack the capability.
+var capabilityMinVersions = map[capability]map[serverFlavor]string{
+	capabilityRoles: {
+		flavorMySQL: "8.0.0", flavorPercona: "8.0.0", flavorMariaDB: "10.0.5", flavorTiDB: "3.0.0",
+	},
+	capabilityDefaultRoles: {
+		flavorMySQL: "8.0.0", flavorPercona: "8.0.0", flavorMariaDB: "10.1.1", flavorTiDB: "3.0.0",
+	},
+	capabilityAccountLock: {
+		flavorMySQL: "5.7.6", flavorPercona: "5.7.6", flavorMariaDB: "10.4.2", flavorTiDB: "2.1.0",
+	},
+	capabilityPasswordExpire: {
+		flavorMySQL: "5.7.4", flavorPercona: "5.7.4", flavorMariaDB: "10.4.3", flavorTiDB: "6.5.0",
+	},
+	capabilityRetainCurrentPassword: {
+		flavorMySQL: "8.0.14", flavorPercona: "8.0.14",
+	},
+	capabilityAuthFactors: {
+		flavorMySQL: "8.0.27", flavorPercona: "8.0.27",
+	},
+	capabilityUserAttributes: {
+		flavorMySQL: "8.0.21", flavorPercona: "8.0.21",
+	},
+	capabilityRandomPassword: {
+		flavorMySQL: "8.0.18", flavorPercona: "8.0.18",
+	},
+	capabilityPasswordReuse: {
+		flavorMySQL: "8.0.3", flavorPercona: "8.0.3", flavorTiDB: "6.5.0",
+	},
+	capabilityPasswordRequireCurrent: {
+		flavorMySQL: "8.0.13", flavorPercona: "8.0.13",
+	},
+	capabilityFailedLoginTracking: {
+		flavorMySQL: "8.0.19", flavorPercona: "8.0.19", flavorTiDB: "6.5.0",
+	},
+	capabilityAlterUser: {
+		flavorMySQL: "5.7.6", flavorPercona: "5.7.6", flavorMariaDB: "10.2.0", flavorTiDB: "2.1.0",
+	},
+	capabilityNoAutoCreateUser: {
+		flavorMySQL: "5.7.5", flavorPercona: "5.7.5",
+	},
+	capabilityPasswordFunction: {
+		flavorMySQL: "5.5.0", flavorPercona: "5.5.0",
+	},
+	capabilityCachingSHA2Default: {
+		flavorMySQL: "8.0.4", flavorPercona: "8.0.4",
+	},
+	capabilityDatabaseEncryption: {
+		flavorMySQL: databaseEncryptionMinVersion, flavorPercona: databaseEncryptionMinVersion,
+	},
+	capabilityReplicaStatus: {
+		flavorMySQL: ReplicaStatusMinVersion, flavorPercona: ReplicaStatusMinVersion, flavorMariaDB: "10.5.1",
+	},
+	capabilityUserTableGlobalGrants: {
+		flavorMySQL: "5.0.0", flavorPercona: "5.0.0",
+	},
+}
+
+// capabilityRemovedVersions are the first versions of each flavor without a capability it had
+// before, e.g. MySQL 8.0 dropped NO_AUTO_CREATE_USER along with creating users by GRANT.
+var capabilityRemovedVersions = map[capability]map[serverFlavor]string{
+	capabilityNoAutoCreateUser: {
+		flavorMySQL: "8.0.0", flavorPercona: "8.0.0",
+	},
+	capabilityPasswordFunction: {
+		flavorMySQL: "8.0.0", flavorPercona: "8.0.0",
+	},
+	capabilityUserTableGlobalGrants: {
+		flavorMySQL: "5.7.0", flavorPercona: "5.7.0",
+	},
+}
+
+// detectFlavor returns the flavor of the server and its version, from @@version and
+// @@version_comment. TiDB reports e.g. 8.0.11-TiDB-v7.5.0, and MariaDB 10.6.12-MariaDB-log.
+func detectFlavor(ctx context.Context, db *sql.DB) (serverFlavor, *version.Version, error) {
+	var versionString, versionComment string
+	if err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.version, @@GLOBAL.version_comment").Scan(&versionString, &versionComment); err != nil {
+		return "", nil, err
+	}
+	return parseFlavor(versionString, versionComment)
+}
+
+func parseFlavor(versionString, versionComment string) (serverFlavor, *version.Version, error) {
+	flavor := flavorMySQL
+	switch {
+	case strings.Contains(versionString, "TiDB"):
+		flavor = flavorTiDB
+		if parts := strings.SplitN(versionString, "-", 3); len(parts) == 3 {
+			versionString = parts[2]
+		}
+	case strings.Contains(versionString, "MariaDB"):
+		flavor = flavorMariaDB
+		// Old clients see the 5.5.5- prefix MariaDB adds for replication compatibility.
+		versionString = strings.TrimPrefix(versionString, "5.5.5-")
+	case strings.Contains(versionComment, "Percona"):
+		flavor = flavorPercona
+	}
+
+	versionString = strings.SplitN(versionString, ":", 2)[0]
+	v, err := version.NewVersion(versionString)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed parsing version %q: %w", versionString, err)
+	}
+	// Suffixes like -MariaDB-log and -25 of Percona aren't pre-releases.
+	return flavor, v.Core(), nil
+}
+
+// getFlavorFromMeta returns the flavor and its version as detected when connecting.
+func getFlavorFromMeta(ctx context.Context, meta interface{}) (serverFlavor, *version.Version, error) {
+	oneConnection, err := connectToMySQLInternal(ctx, meta.(*MySQLConfiguration))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to connect to MySQL: %v", err)
+	}
+	return oneConnection.Flavor, oneConnection.FlavorVersion, nil
+}
+
+// flavorSupports returns nil if the version of the flavor has the capability, or else an
+// error naming the capability and the version it requires.
+func flavorSupports(flavor serverFlavor, flavorVersion *version.Version, c capability) error {
+	minVersion, ok := capabilityMinVersions[c][flavor]
+	if !ok {
+		return fmt.Errorf("%s is not supported by %s", c, flavor)
+	}
+	requiredVersion, _ := version.NewVersion(minVersion)
+	if flavorVersion.LessThan(requiredVersion) {
+		return fmt.Errorf("%s requires %s %s or later, got %s", c, flavor, minVersion, flavorVersion)
+	}
+	if removedVersion, ok := capabilityRemovedVersions[c][flavor]; ok {
+		if v, _ := version.NewVersion(removedVersion); !flavorVersion.LessThan(v) {
+			return fmt.Errorf("%s was removed in %s %s, got %s", c, flavor, removedVersion, flavorVersion)
+		}
+	}
+	return nil
+}
+
+// checkCapability returns nil if the server has the capability, or else an error naming the
+// capability and the version it requires.
+func checkCapability(ctx context.Context, meta interface{}, c capability) error {
+	flavor, flavorVersion, err := getFlavorFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+	return flavorSupports(flavor, flavorVersion, c)
+}
+
+// serverSupports returns whether the server has the capability, for choosing between syntaxes
+// rather than refusing a feature.
+func serverSupports(ctx context.Context, meta interface{}, c capability) bool {
+	return checkCapability(ctx, meta, c) == nil
+}
@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestParseFlavor(t *testing.T) {
+	for _, tc := range []struct {
+		versionString  string
+		versionComment string
+		flavor         serverFlavor
+		version        string
+	}{
+		{"8.0.36", "MySQL Community Server - GPL", flavorMySQL, "8.0.36"},
+		{"5.7.44-log", "MySQL Community Server (GPL)", flavorMySQL, "5.7.44"},
+		{"8.0.35-27", "Percona Server (GPL), Release 27", flavorPercona, "8.0.35"},
+		{"10.6.12-MariaDB-1:10.6.12+maria~ubu2004", "mariadb.org binary distribution", flavorMariaDB, "10.6.12"},
+		{"5.5.5-10.3.39-MariaDB-log", "MariaDB Server", flavorMariaDB, "10.3.39"},
+		{"8.0.11-TiDB-v7.5.0", "", flavorTiDB, "7.5.0"},
+		{"5.7.25-TiDB-v6.1.0", "", flavorTiDB, "6.1.0"},
+	} {
+		flavor, v, err := parseFlavor(tc.versionString, tc.versionComment)
+		if err != nil {
+			t.Errorf("parseFlavor(%q): %v", tc.versionString, err)
+			continue
+		}
+		if flavor != tc.flavor || v.String() != tc.version {
+			t.Errorf("parseFlavor(%q) = %s %s, expected %s %s", tc.versionString, flavor, v, tc.flavor, tc.version)
+		}
+	}
+}
+
+func TestFlavorSupports(t *testing.T) {
+	for _, tc := range []struct {
+		flavor    serverFlavor
+		version   string
+		c         capability
+		supported bool
+	}{
+		{flavorMySQL, "8.0.36", capabilityRoles, true},
+		{flavorMySQL, "5.7.44", capabilityRoles, false},
+		{flavorMariaDB, "10.6.12", capabilityRoles, true},
+		{flavorMariaDB, "10.0.4", capabilityRoles, false},
+		{flavorMariaDB, "10.6.12", capabilityRetainCurrentPassword, false},
+		{flavorMariaDB, "10.6.12", capabilityAccountLock, true},
+		{flavorMariaDB, "10.3.39", capabilityAccountLock, false},
+		{flavorTiDB, "7.5.0", capabilityDefaultRoles, true},
+		{flavorTiDB, "6.1.0", capabilityFailedLoginTracking, false},
+		{flavorPercona, "8.0.35", capabilityAuthFactors, true},
+		{flavorMySQL, "5.7.44", capabilityNoAutoCreateUser, true},
+		{flavorMySQL, "8.0.36", capabilityNoAutoCreateUser, false},
+		{flavorMariaDB, "10.6.12", capabilityNoAutoCreateUser, false},
+		{flavorMySQL, "5.6.51", capabilityPasswordFunction, true},
+		{flavorMySQL, "8.0.36", capabilityPasswordFunction, false},
+		{flavorMySQL, "5.6.51", capabilityUserTableGlobalGrants, true},
+		{flavorPercona, "5.7.44", capabilityUserTableGlobalGrants, false},
+		{flavorMariaDB, "10.3.39", capabilityUserTableGlobalGrants, false},
+		{flavorMariaDB, "10.6.12", capabilityCachingSHA2Default, false},
+		{flavorMariaDB, "10.6.12", capabilityReplicaStatus, true},
+		{flavorMySQL, "8.0.21", capabilityReplicaStatus, false},
+		{flavorTiDB, "7.5.0", capabilityAlterUser, true},
+		{flavorMySQL, "5.6.51", capabilityAlterUser, false},
+	} {
+		v := version.Must(version.NewVersion(tc.version))
+		if err := flavorSupports(tc.flavor, v, tc.c); (err == nil) != tc.supported {
+			t.Errorf("flavorSupports(%s %s, %s) = %v, expected supported %t", tc.flavor, tc.version, tc.c, err, tc.supported)
+		}
+	}
+}
+
+func TestSessionSQLMode(t *testing.T) {
+	for _, tc := range []struct {
+		flavor  serverFlavor
+		version string
+		mode    string
+	}{
+		{flavorMySQL, "5.7.44", `SET SESSION sql_mode='NO_AUTO_CREATE_USER'`},
+		{flavorPercona, "5.7.44", `SET SESSION sql_mode='NO_AUTO_CREATE_USER'`},
+		{flavorMySQL, "8.0.36", `SET SESSION sql_mode=''`},
+		{flavorMySQL, "5.6.51", `SET SESSION sql_mode=''`},
+		{flavorTiDB, "7.5.0", `SET SESSION sql_mode=''`},
+	} {
+		if mode := sessionSQLMode(tc.flavor, version.Must(version.NewVersion(tc.version))); mode != tc.mode {
+			t.Errorf("sessionSQLMode(%s %s) = %s, expected %s", tc.flavor, tc.version, mode, tc.mode)
+		}
+	}
+}
+
+func TestCheckKeptSQLMode(t *testing.T) {
+	mysql57 := version.Must(version.NewVersion("5.7.44"))
+	if err := checkKeptSQLMode("ANSI_QUOTES,NO_AUTO_CREATE_USER", flavorMySQL, mysql57); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := checkKeptSQLMode("STRICT_TRANS_TABLES,NO_BACKSLASH_ESCAPES", flavorMySQL, mysql57); err == nil {
+		t.Error("expected NO_BACKSLASH_ESCAPES to be refused")
+	}
+	if err := checkKeptSQLMode("", flavorMySQL, mysql57); err != nil {
+		t.Errorf("expected only a warning without NO_AUTO_CREATE_USER, got %v", err)
+	}
+}
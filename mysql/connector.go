@@ -0,0 +1,229 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// providerConnector opens the connections of the provider, which drop the cached reads on any
+// statement that may write. With dryRun, which is set after the connection setup, statements
+// run with Exec are logged instead of executed. Queries and prepared statements still run when
+// they only read, so reads keep working, and are refused otherwise, as e.g. CREATE USER with
+// RANDOM PASSWORD returns rows which can't be made up.
+//
+// New connections run the session setup, e.g. the sql_mode of the provider, so reconnecting
+// doesn't need the server to be detected again. A connection whose session was changed by a
+// statement, e.g. USE of mysql_migration, is closed instead of reused, so the next resource gets
+// a new session like with mysql_reset_connection, which the driver doesn't offer.
+type providerConnector struct {
+	driver driver.Driver
+	dsn    string
+	// connector opens the connections instead of the dsn when set.
+	connector driver.Connector
+	cache     *readCache
+	dryRun    atomic.Bool
+
+	mu           sync.Mutex
+	sessionSetup []string
+}
+
+// setSessionSetup sets the statements which new connections run before they're used.
+func (c *providerConnector) setSessionSetup(statements []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionSetup = statements
+}
+
+func (c *providerConnector) getSessionSetup() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionSetup
+}
+
+func (c *providerConnector) isSessionSetup(query string) bool {
+	for _, stmt := range c.getSessionSetup() {
+		if stmt == query {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *providerConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var conn driver.Conn
+	var err error
+	if c.connector != nil {
+		conn, err = c.connector.Connect(ctx)
+	} else if driverCtx, ok := c.driver.(driver.DriverContext); ok {
+		var connector driver.Connector
+		connector, err = driverCtx.OpenConnector(c.dsn)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = connector.Connect(ctx)
+	} else {
+		conn, err = c.driver.Open(c.dsn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range c.getSessionSetup() {
+		log.Println("[DEBUG] Setting up session:", stmt)
+		execer, ok := conn.(driver.ExecerContext)
+		if !ok {
+			break
+		}
+		if _, err := execer.ExecContext(ctx, stmt, nil); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return &providerConn{Conn: conn, connector: c}, nil
+}
+
+func (c *providerConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// providerConn wraps a driver connection, passing everything but executed statements through.
+type providerConn struct {
+	driver.Conn
+	connector *providerConnector
+	// sessionChanged is whether a statement changed the session, so it mustn't be reused.
+	sessionChanged bool
+}
+
+// invalidate drops the cached reads unless the statement only reads.
+func (c *providerConn) invalidate(query string) {
+	if c.connector.cache != nil && !isReadStatement(query) {
+		c.connector.cache.invalidate()
+	}
+}
+
+func (c *providerConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.invalidate(query)
+	if c.connector.dryRun.Load() {
+		// Arguments aren't logged, as they may be secrets, and neither are secrets in the statement.
+		log.Printf("[WARN] Dry run, not executing statement (%d arguments): %s", len(args), redactSQL(query))
+		return driver.RowsAffected(0), nil
+	}
+	c.trackSession(query)
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// trackSession marks the connection as changed by statements which change the session.
+func (c *providerConn) trackSession(query string) {
+	if !c.sessionChanged && changesSession(query) && !c.connector.isSessionSetup(query) {
+		c.sessionChanged = true
+	}
+}
+
+// changesSession returns whether the statement changes the state of the session, e.g. its
+// default database, variables or temporary tables, which later resources mustn't see.
+func changesSession(query string) bool {
+	fields := strings.Fields(strings.ToUpper(query))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "USE", "LOCK", "PREPARE":
+		return true
+	case "CREATE":
+		return len(fields) > 1 && fields[1] == "TEMPORARY"
+	case "SET":
+		if len(fields) == 1 {
+			return false
+		}
+		switch fields[1] {
+		case "GLOBAL", "PERSIST", "PERSIST_ONLY", "PASSWORD", "DEFAULT":
+			// These change the server or accounts rather than the session.
+			return false
+		}
+		return !strings.HasPrefix(fields[1], "@@GLOBAL.") && !strings.HasPrefix(fields[1], "@@PERSIST")
+	}
+	return false
+}
+
+// errDryRun is returned in a dry run for queries and prepared statements which may write.
+var errDryRun = errors.New("dry run: refusing to run a statement which may write")
+
+// refuseInDryRun returns errDryRun in a dry run unless the statement only reads.
+func (c *providerConn) refuseInDryRun(query string, args int) error {
+	if !c.connector.dryRun.Load() || isReadStatement(query) {
+		return nil
+	}
+	log.Printf("[WARN] Dry run, refusing statement (%d arguments): %s", args, redactSQL(query))
+	return errDryRun
+}
+
+func (c *providerConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.refuseInDryRun(query, len(args)); err != nil {
+		return nil, err
+	}
+	c.invalidate(query)
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *providerConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.refuseInDryRun(query, 0); err != nil {
+		return nil, err
+	}
+	c.invalidate(query)
+	c.trackSession(query)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *providerConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *providerConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *providerConn) ResetSession(ctx context.Context) error {
+	if c.sessionChanged {
+		// The pool closes the connection and opens a new one, which runs the session setup.
+		log.Printf("[DEBUG] Not reusing a connection whose session was changed")
+		return driver.ErrBadConn
+	}
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *providerConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *providerConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
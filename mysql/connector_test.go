@@ -0,0 +1,223 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type recordingDriver struct {
+	executed []string
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *recordingConn) Close() error                              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.executed = append(c.driver.executed, query)
+	return driver.RowsAffected(1), nil
+}
+
+func TestDryRunConnector(t *testing.T) {
+	ctx := context.Background()
+	recorder := &recordingDriver{}
+	connector := &providerConnector{driver: recorder, dsn: "test"}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, "SET SESSION sql_mode=''"); err != nil {
+		t.Fatal(err)
+	}
+
+	connector.dryRun.Store(true)
+	result, err := db.ExecContext(ctx, "DROP DATABASE production", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := result.RowsAffected(); n != 0 {
+		t.Errorf("expected no affected rows in a dry run, got %d", n)
+	}
+
+	if len(recorder.executed) != 1 || recorder.executed[0] != "SET SESSION sql_mode=''" {
+		t.Errorf("expected only the statement before enabling to be executed, got %v", recorder.executed)
+	}
+}
+
+func TestDryRunConnectorRefusesWritingQueries(t *testing.T) {
+	ctx := context.Background()
+	connector := &providerConnector{connector: &variablesConnector{variables: map[string]string{"read_only": "0"}}}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	connector.dryRun.Store(true)
+
+	var readOnly string
+	if err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.read_only").Scan(&readOnly); err != nil || readOnly != "0" {
+		t.Errorf("expected reads to run in a dry run, got %q, %v", readOnly, err)
+	}
+
+	if _, err := db.QueryContext(ctx, "ALTER USER ?@? IDENTIFIED BY RANDOM PASSWORD", "jdoe", "%"); !errors.Is(err, errDryRun) {
+		t.Errorf("expected a writing query to be refused, got %v", err)
+	}
+	if _, err := db.QueryContext(ctx, "CREATE USER 'jdoe'@'%' IDENTIFIED BY 'secret'"); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected a refused statement not to show up in the error, got %v", err)
+	}
+	if _, err := db.PrepareContext(ctx, "INSERT INTO app.t VALUES (?)"); !errors.Is(err, errDryRun) {
+		t.Errorf("expected a writing prepared statement to be refused, got %v", err)
+	}
+}
+
+func TestProviderConnectorInvalidatesReadCache(t *testing.T) {
+	ctx := context.Background()
+	cache := newReadCache()
+	connector := &providerConnector{driver: &recordingDriver{}, dsn: "test", cache: cache}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	rows := [][]sql.NullString{{{String: "GRANT USAGE ON *.* TO `jdoe`@`%`", Valid: true}}}
+	_, _, generation := cache.get("SHOW GRANTS FOR 'jdoe'@'%'")
+	cache.put("SHOW GRANTS FOR 'jdoe'@'%'", rows, generation)
+
+	if _, err := db.ExecContext(ctx, "  select 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := cache.get("SHOW GRANTS FOR 'jdoe'@'%'"); !ok {
+		t.Errorf("expected a read to keep the cached rows")
+	}
+
+	if _, err := db.ExecContext(ctx, "GRANT SELECT ON app.* TO 'jdoe'@'%'"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := cache.get("SHOW GRANTS FOR 'jdoe'@'%'"); ok {
+		t.Errorf("expected a write to drop the cached rows")
+	}
+
+	// Rows read before a write mustn't be cached after it.
+	cache.put("SHOW GRANTS FOR 'jdoe'@'%'", rows, generation)
+	if _, ok, _ := cache.get("SHOW GRANTS FOR 'jdoe'@'%'"); ok {
+		t.Errorf("expected rows read before the write not to be cached")
+	}
+}
+
+func TestProviderConnectorResetsChangedSessions(t *testing.T) {
+	ctx := context.Background()
+	recorder := &recordingDriver{}
+	connector := &providerConnector{driver: recorder, dsn: "test"}
+	connector.setSessionSetup([]string{"SET SESSION sql_mode=''"})
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	for _, stmt := range []string{"SET SESSION sql_mode=''", "GRANT SELECT ON app.* TO 'jdoe'@'%'", "USE app", "DROP TABLE t"} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"SET SESSION sql_mode=''",
+		// Running the setup again doesn't change the session, so the connection is reused.
+		"SET SESSION sql_mode=''",
+		"GRANT SELECT ON app.* TO 'jdoe'@'%'",
+		"USE app",
+		// The session using app isn't reused, the new connection runs the setup.
+		"SET SESSION sql_mode=''",
+		"DROP TABLE t",
+	}
+	if !reflect.DeepEqual(recorder.executed, expected) {
+		t.Errorf("expected statements %q, got %q", expected, recorder.executed)
+	}
+}
+
+func TestChangesSession(t *testing.T) {
+	tests := map[string]bool{
+		"USE app":                            true,
+		"SET @x = 1":                         true,
+		"set session sql_mode='ANSI_QUOTES'": true,
+		"SET NAMES utf8mb4":                  true,
+		"CREATE TEMPORARY TABLE t (a int)":   true,
+		"LOCK TABLES t READ":                 true,
+		"SET GLOBAL max_connections = 10":    false,
+		"SET @@GLOBAL.max_connections = 10":  false,
+		"SET PERSIST max_connections = 10":   false,
+		"SET PASSWORD FOR 'jdoe'@'%' = 'x'":  false,
+		"SET DEFAULT ROLE ALL TO 'jdoe'@'%'": false,
+		"CREATE TABLE t (a int)":             false,
+		"SELECT 1":                           false,
+		"":                                   false,
+	}
+	for stmt, expected := range tests {
+		if got := changesSession(stmt); got != expected {
+			t.Errorf("changesSession(%q) = %t, expected %t", stmt, got, expected)
+		}
+	}
+}
+
+func TestIsReadStatement(t *testing.T) {
+	tests := map[string]bool{
+		"SELECT 1":                                       true,
+		"  show grants":                                  true,
+		"(SELECT 1) UNION (SELECT 2)":                    true,
+		"TABLE app.t":                                    true,
+		"VALUES ROW(1), ROW(2)":                          true,
+		"WITH c AS (SELECT 1) SELECT * FROM c":           true,
+		"-- the users\nSELECT user FROM mysql.user":      true,
+		"# the users\nSELECT user FROM mysql.user":       true,
+		"/* the users */ SELECT user FROM mysql.user":    true,
+		"call mysql.rds_show_configuration":              true,
+		"CALL `mysql`.`rds_show_configuration`()":        true,
+		"WITH c AS (SELECT 1) DELETE FROM t WHERE a = 1": false,
+		"CALL mysql.rds_set_configuration(?, ?)":         false,
+		"/*!80000 DROP TABLE t */":                       false,
+		"/* SELECT */ DROP TABLE t":                      false,
+		"-- SELECT":                                      false,
+		"INSERT INTO t SELECT 1":                         false,
+		"":                                               false,
+	}
+	for stmt, expected := range tests {
+		if got := isReadStatement(stmt); got != expected {
+			t.Errorf("isReadStatement(%q) = %t, expected %t", stmt, got, expected)
+		}
+	}
+}
+
+func TestMayReturnRows(t *testing.T) {
+	tests := map[string]bool{
+		"/* seed */ SELECT 1":                   true,
+		"WITH c AS (SELECT 1) SELECT * FROM c":  true,
+		"CALL app.create_partitions(2026)":      true,
+		"INSERT INTO t VALUES (1)":              false,
+		"-- seed\nINSERT INTO t VALUES (1)":     false,
+		"WITH c AS (SELECT 1) UPDATE t SET a=1": false,
+	}
+	for stmt, expected := range tests {
+		if got := mayReturnRows(stmt); got != expected {
+			t.Errorf("mayReturnRows(%q) = %t, expected %t", stmt, got, expected)
+		}
+	}
+}
+
+func TestServerDetectionHonorsCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	db := sql.OpenDB(&providerConnector{driver: &recordingDriver{}, dsn: "test"})
+	defer db.Close()
+
+	if _, err := serverVersion(ctx, db); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled context to stop the query, got %v", err)
+	}
+	if err := checkTiDBFeatureSupport(ctx, db, "sequences", SequenceTiDBMinVersion); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled context to stop the check, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
@@ -18,11 +19,28 @@ func dataSourceDatabases() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"include_details": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"databases": {
 				Type:     schema.TypeList,
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"details": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":                  {Type: schema.TypeString, Computed: true},
+						"default_character_set": {Type: schema.TypeString, Computed: true},
+						"default_collation":     {Type: schema.TypeString, Computed: true},
+						"size_bytes":            {Type: schema.TypeInt, Computed: true},
+					},
+				},
+			},
 		},
 	}
 }
@@ -35,15 +53,17 @@ func ShowDatabases(ctx context.Context, d *schema.ResourceData, meta interface{}
 
 	pattern := d.Get("pattern").(string)
 
-	sql := fmt.Sprint("SHOW DATABASES")
+	sql := "SHOW DATABASES"
+	var args []interface{}
 
 	if pattern != "" {
-		sql += fmt.Sprintf(" LIKE '%s'", pattern)
+		sql += " LIKE ?"
+		args = append(args, pattern)
 	}
 
 	log.Printf("[DEBUG] SQL: %s", sql)
 
-	rows, err := db.QueryContext(ctx, sql)
+	rows, err := db.QueryContext(ctx, sql, args...)
 	if err != nil {
 		return diag.Errorf("failed querying for databases: %v", err)
 	}
@@ -64,7 +84,68 @@ func ShowDatabases(ctx context.Context, d *schema.ResourceData, meta interface{}
 		return diag.Errorf("failed setting databases field: %v", err)
 	}
 
+	details := []interface{}{}
+	if d.Get("include_details").(bool) {
+		details, err = readDatabaseDetails(ctx, db, pattern, databases)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("details", details); err != nil {
+		return diag.Errorf("failed setting details field: %v", err)
+	}
+
 	d.SetId(id.UniqueId())
 
 	return nil
 }
+
+// readDatabaseDetails returns the default character set, collation and approximate size of
+// each of the databases. The size sums the data and index lengths of information_schema.tables,
+// which scans the tables of all matching databases.
+func readDatabaseDetails(ctx context.Context, db *sql.DB, pattern string, databases []string) ([]interface{}, error) {
+	query := `SELECT s.SCHEMA_NAME, s.DEFAULT_CHARACTER_SET_NAME, s.DEFAULT_COLLATION_NAME,
+		COALESCE(SUM(t.DATA_LENGTH + t.INDEX_LENGTH), 0)
+		FROM information_schema.SCHEMATA s
+		LEFT JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = s.SCHEMA_NAME`
+	var args []interface{}
+	if pattern != "" {
+		query += " WHERE s.SCHEMA_NAME LIKE ?"
+		args = append(args, pattern)
+	}
+	query += " GROUP BY s.SCHEMA_NAME, s.DEFAULT_CHARACTER_SET_NAME, s.DEFAULT_COLLATION_NAME"
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying for database details: %w", err)
+	}
+	defer rows.Close()
+
+	found := map[string]map[string]interface{}{}
+	for rows.Next() {
+		var name, charset, collation string
+		var size int64
+		if err := rows.Scan(&name, &charset, &collation, &size); err != nil {
+			return nil, fmt.Errorf("failed scanning database details: %w", err)
+		}
+		found[name] = map[string]interface{}{
+			"name":                  name,
+			"default_character_set": charset,
+			"default_collation":     collation,
+			"size_bytes":            int(size),
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading database details: %w", err)
+	}
+
+	// The details follow the order of databases, and skip any database dropped in between.
+	details := make([]interface{}, 0, len(databases))
+	for _, database := range databases {
+		if detail, ok := found[database]; ok {
+			details = append(details, detail)
+		}
+	}
+	return details, nil
+}
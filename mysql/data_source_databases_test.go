@@ -44,6 +44,33 @@ func TestAccDataSourceDatabases(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceDatabases_details(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy("tf-test-details"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabasesConfigDetails(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_databases.test", "databases.#", "1"),
+					resource.TestCheckResourceAttr("data.mysql_databases.test", "details.#", "0"),
+				),
+			},
+			{
+				Config: testAccDatabasesConfigDetails(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_databases.test", "details.#", "1"),
+					resource.TestCheckResourceAttr("data.mysql_databases.test", "details.0.name", "tf-test-details"),
+					resource.TestCheckResourceAttr("data.mysql_databases.test", "details.0.default_character_set", "utf8mb4"),
+					resource.TestCheckResourceAttr("data.mysql_databases.test", "details.0.default_collation", "utf8mb4_bin"),
+					resource.TestCheckResourceAttrSet("data.mysql_databases.test", "details.0.size_bytes"),
+				),
+			},
+		},
+	})
+}
+
 func testAccDatabasesCount(rn string, key string, check func(string, int) error) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -74,3 +101,17 @@ data "mysql_databases" "test" {
 		pattern = "%s"
 }`, pattern)
 }
+
+func testAccDatabasesConfigDetails(includeDetails bool) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+	name                  = "tf-test-details"
+	default_character_set = "utf8mb4"
+	default_collation     = "utf8mb4_bin"
+}
+
+data "mysql_databases" "test" {
+	pattern         = mysql_database.test.name
+	include_details = %t
+}`, includeDetails)
+}
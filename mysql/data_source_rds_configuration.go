@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceRDSConfiguration reads the configuration of mysql.rds_show_configuration without
+// managing it, unlike mysql_rds_config.
+func dataSourceRDSConfiguration() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ShowRDSConfiguration,
+		Schema: map[string]*schema.Schema{
+			"configurations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":        {Type: schema.TypeString, Computed: true},
+						"value":       {Type: schema.TypeString, Computed: true},
+						"is_null":     {Type: schema.TypeBool, Computed: true},
+						"description": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+			"values": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func ShowRDSConfiguration(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if isRds, _ := getRdsFromMeta(ctx, meta); !isRds {
+		return diag.Errorf("mysql_rds_configuration is only available on Amazon RDS")
+	}
+
+	rows, err := readRDSConfigurations(ctx, db)
+	if err != nil {
+		return sqlErrorDiag("failed reading RDS configuration", "call mysql.rds_show_configuration", err)
+	}
+
+	configurations := make([]interface{}, 0, len(rows))
+	values := map[string]interface{}{}
+	for _, row := range rows {
+		configurations = append(configurations, map[string]interface{}{
+			"name":        row.Name,
+			"value":       row.Value.String,
+			"is_null":     !row.Value.Valid,
+			"description": row.Description,
+		})
+		if row.Value.Valid {
+			values[row.Name] = row.Value.String
+		}
+	}
+
+	if err := d.Set("configurations", configurations); err != nil {
+		return diag.Errorf("failed setting configurations field: %v", err)
+	}
+	d.Set("values", values)
+	d.SetId("rds_configuration")
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRDSConfiguration(t *testing.T) {
+	dataSourceName := "data.mysql_rds_configuration.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_rds_config" "test" {
+	binlog_retention_hours = 24
+}
+
+data "mysql_rds_configuration" "test" {
+	depends_on = [mysql_rds_config.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "values.binlog retention hours", "24"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "configurations.0.description"),
+				),
+			},
+		},
+	})
+}
@@ -2,14 +2,20 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// tableTypes are the values of Table_type in SHOW FULL TABLES. MariaDB adds SEQUENCE.
+var tableTypes = []string{"BASE TABLE", "VIEW", "SYSTEM VIEW", "SEQUENCE"}
+
 func dataSourceTables() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: ShowTables,
@@ -22,11 +28,41 @@ func dataSourceTables() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"include_views": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       true,
+				ConflictsWith: []string{"table_type"},
+			},
+			"table_type": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"include_views"},
+				ValidateFunc:  validation.StringInSlice(tableTypes, true),
+			},
+			"include_details": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"tables": {
 				Type:     schema.TypeList,
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"details": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":       {Type: schema.TypeString, Computed: true},
+						"table_type": {Type: schema.TypeString, Computed: true},
+						"engine":     {Type: schema.TypeString, Computed: true},
+						"rows":       {Type: schema.TypeInt, Computed: true},
+						"size_bytes": {Type: schema.TypeInt, Computed: true},
+					},
+				},
+			},
 		},
 	}
 }
@@ -40,28 +76,37 @@ func ShowTables(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	database := d.Get("database").(string)
 	pattern := d.Get("pattern").(string)
 
-	sql := fmt.Sprintf("SHOW TABLES FROM %s", quoteIdentifier(database))
+	sql := fmt.Sprintf("SHOW FULL TABLES FROM %s", quoteIdentifier(database))
+
+	var args []interface{}
 
 	if pattern != "" {
-		sql += fmt.Sprintf(" LIKE '%s'", pattern)
+		sql += " LIKE ?"
+		args = append(args, pattern)
 	}
 
 	log.Printf("[DEBUG] SQL: %s", sql)
 
-	rows, err := db.QueryContext(ctx, sql)
+	rows, err := db.QueryContext(ctx, sql, args...)
 	if err != nil {
 		return diag.Errorf("failed querying for tables: %v", err)
 	}
 	defer rows.Close()
 
+	includeViews := d.Get("include_views").(bool)
+	tableType := d.Get("table_type").(string)
+
 	var tables []string
 	for rows.Next() {
-		var table string
+		var table, foundType string
 
-		if err := rows.Scan(&table); err != nil {
+		if err := rows.Scan(&table, &foundType); err != nil {
 			return diag.Errorf("failed scanning MySQL rows: %v", err)
 		}
 
+		if !tableTypeMatches(foundType, tableType, includeViews) {
+			continue
+		}
 		tables = append(tables, table)
 	}
 
@@ -69,7 +114,75 @@ func ShowTables(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.Errorf("failed setting tables field: %v", err)
 	}
 
+	details := []interface{}{}
+	if d.Get("include_details").(bool) {
+		details, err = readTableDetails(ctx, db, database, pattern, tables)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("details", details); err != nil {
+		return diag.Errorf("failed setting details field: %v", err)
+	}
+
 	d.SetId(id.UniqueId())
 
 	return nil
 }
+
+// tableTypeMatches returns whether a table of foundType is listed, given either the table_type
+// asked for or whether views are included.
+func tableTypeMatches(foundType, tableType string, includeViews bool) bool {
+	if tableType != "" {
+		return strings.EqualFold(foundType, tableType)
+	}
+	return includeViews || !strings.HasSuffix(strings.ToUpper(foundType), "VIEW")
+}
+
+// readTableDetails returns the type, engine, approximate row count and size of each of the
+// tables from information_schema.tables. Views have no engine, rows or size.
+func readTableDetails(ctx context.Context, db *sql.DB, database, pattern string, tables []string) ([]interface{}, error) {
+	query := `SELECT TABLE_NAME, TABLE_TYPE, COALESCE(ENGINE, ''), COALESCE(TABLE_ROWS, 0),
+		COALESCE(DATA_LENGTH, 0) + COALESCE(INDEX_LENGTH, 0)
+		FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?`
+	args := []interface{}{database}
+	if pattern != "" {
+		query += " AND TABLE_NAME LIKE ?"
+		args = append(args, pattern)
+	}
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying for table details: %w", err)
+	}
+	defer rows.Close()
+
+	found := map[string]map[string]interface{}{}
+	for rows.Next() {
+		var name, tableType, engine string
+		var tableRows, size int64
+		if err := rows.Scan(&name, &tableType, &engine, &tableRows, &size); err != nil {
+			return nil, fmt.Errorf("failed scanning table details: %w", err)
+		}
+		found[name] = map[string]interface{}{
+			"name":       name,
+			"table_type": tableType,
+			"engine":     engine,
+			"rows":       int(tableRows),
+			"size_bytes": int(size),
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading table details: %w", err)
+	}
+
+	// The details follow the order of tables, and skip any table dropped in between.
+	details := make([]interface{}, 0, len(tables))
+	for _, table := range tables {
+		if detail, ok := found[table]; ok {
+			details = append(details, detail)
+		}
+	}
+	return details, nil
+}
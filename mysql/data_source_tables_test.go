@@ -46,6 +46,65 @@ func TestAccDataSourceTables(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceTables_types(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy("tf-test-tables"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTablesConfigTypes(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "tables.#", "2"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "details.#", "2"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "details.0.name", "orders"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "details.0.table_type", "BASE TABLE"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "details.0.engine", "InnoDB"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "details.1.name", "orders_view"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "details.1.table_type", "VIEW"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "details.1.engine", ""),
+				),
+			},
+			{
+				Config: testAccTablesConfigTypes("include_views = false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "tables.#", "1"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "tables.0", "orders"),
+				),
+			},
+			{
+				Config: testAccTablesConfigTypes(`table_type = "VIEW"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "tables.#", "1"),
+					resource.TestCheckResourceAttr("data.mysql_tables.test", "tables.0", "orders_view"),
+				),
+			},
+		},
+	})
+}
+
+func TestTableTypeMatches(t *testing.T) {
+	tests := []struct {
+		foundType    string
+		tableType    string
+		includeViews bool
+		expected     bool
+	}{
+		{"BASE TABLE", "", true, true},
+		{"VIEW", "", true, true},
+		{"VIEW", "", false, false},
+		{"SYSTEM VIEW", "", false, false},
+		{"SEQUENCE", "", false, true},
+		{"VIEW", "view", true, true},
+		{"BASE TABLE", "VIEW", true, false},
+	}
+	for _, tt := range tests {
+		if got := tableTypeMatches(tt.foundType, tt.tableType, tt.includeViews); got != tt.expected {
+			t.Errorf("tableTypeMatches(%q, %q, %t) = %t, expected %t", tt.foundType, tt.tableType, tt.includeViews, got, tt.expected)
+		}
+	}
+}
+
 func testAccTablesCount(rn string, key string, check func(string, int) error) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -77,3 +136,34 @@ data "mysql_tables" "test" {
 		pattern = "%s"
 }`, database, pattern)
 }
+
+func testAccTablesConfigTypes(filter string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+	name = "tf-test-tables"
+}
+
+resource "mysql_sql" "table" {
+	name       = "tf-test-tables-orders"
+	create_sql = "CREATE TABLE %[2]s.orders (id INT PRIMARY KEY) ENGINE = InnoDB"
+	delete_sql = "DROP TABLE %[2]s.orders"
+
+	depends_on = [mysql_database.test]
+}
+
+resource "mysql_sql" "view" {
+	name       = "tf-test-tables-orders-view"
+	create_sql = "CREATE VIEW %[2]s.orders_view AS SELECT id FROM %[2]s.orders"
+	delete_sql = "DROP VIEW %[2]s.orders_view"
+
+	depends_on = [mysql_sql.table]
+}
+
+data "mysql_tables" "test" {
+	database        = mysql_database.test.name
+	include_details = true
+	%[1]s
+
+	depends_on = [mysql_sql.view]
+}`, filter, quoteIdentifier("tf-test-tables"))
+}
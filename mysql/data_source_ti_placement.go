@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTiPlacement() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ShowPlacement,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"table": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"database"},
+			},
+			"placements": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"target":           {Type: schema.TypeString, Computed: true},
+						"placement":        {Type: schema.TypeString, Computed: true},
+						"scheduling_state": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+			"scheduled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func ShowPlacement(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkPlacementPolicySupport(ctx, db); err != nil {
+		return diag.Errorf("cannot show placement: %v", err)
+	}
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	stmtSQL := "SHOW PLACEMENT"
+	id := "placement"
+	if table != "" {
+		stmtSQL += fmt.Sprintf(" FOR TABLE %s.%s", quoteIdentifier(database), quoteIdentifier(table))
+		id = fmt.Sprintf("%s.%s", database, table)
+	} else if database != "" {
+		stmtSQL += fmt.Sprintf(" FOR DATABASE %s", quoteIdentifier(database))
+		id = database
+	}
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return sqlErrorDiag("failed querying for placement", stmtSQL, err)
+	}
+	defer rows.Close()
+
+	placements := []interface{}{}
+	scheduled := true
+	for rows.Next() {
+		var target, placement, state string
+		if err := rows.Scan(&target, &placement, &state); err != nil {
+			return diag.Errorf("failed scanning placement: %v", err)
+		}
+
+		// Objects without a policy, which SHOW PLACEMENT FOR lists too, aren't scheduled.
+		if placement != "" && !strings.EqualFold(state, "SCHEDULED") {
+			scheduled = false
+		}
+
+		placements = append(placements, map[string]interface{}{
+			"target":           target,
+			"placement":        placement,
+			"scheduling_state": state,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed reading placement: %v", err)
+	}
+
+	if err := d.Set("placements", placements); err != nil {
+		return diag.Errorf("failed setting placements field: %v", err)
+	}
+	d.Set("scheduled", scheduled)
+	d.SetId(id)
+
+	return nil
+}
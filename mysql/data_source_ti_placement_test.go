@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTiPlacement(t *testing.T) {
+	dataSourceName := "data.mysql_ti_placement.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, PlacementPolicyTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTiPlacementConfig("tf_placement"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "tf_placement"),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "placements.*", map[string]string{
+						"target": "DATABASE tf_placement",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccTiPlacementConfig(dbName string) string {
+	return fmt.Sprintf(`
+resource "mysql_ti_placement_policy" "test" {
+	name           = "%[1]s"
+	primary_region = "r1"
+	regions        = "r1"
+}
+
+resource "mysql_database" "test" {
+	name             = "%[1]s"
+	placement_policy = mysql_ti_placement_policy.test.name
+}
+
+data "mysql_ti_placement" "test" {
+	database = mysql_database.test.name
+}
+`, dbName)
+}
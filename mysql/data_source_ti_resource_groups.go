@@ -0,0 +1,197 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// unlimitedResourceUnits is the resource_units of a group with RU_PER_SEC = UNLIMITED, like
+// the default group.
+const unlimitedResourceUnits = -1
+
+func dataSourceTiResourceGroups() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ShowResourceGroups,
+		Schema: map[string]*schema.Schema{
+			"usage_window": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "10m",
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationSuppressFunc,
+			},
+			"resource_groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":           {Type: schema.TypeString, Computed: true},
+						"resource_units": {Type: schema.TypeInt, Computed: true},
+						"priority":       {Type: schema.TypeString, Computed: true},
+						"burstable":      {Type: schema.TypeBool, Computed: true},
+						"consumed_request_units": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"total_resource_units": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"estimated_capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"remaining_resource_units": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"resource_units_per_sec": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func ShowResourceGroups(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "resource groups", ResourceGroupTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot show resource groups: %v", err)
+	}
+
+	groups, err := listResourceGroupsFromDB(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	consumed, err := readConsumedRequestUnits(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resourceGroups := make([]interface{}, 0, len(groups))
+	for _, rg := range groups {
+		resourceGroups = append(resourceGroups, map[string]interface{}{
+			"name":                   rg.Name,
+			"resource_units":         rg.ResourceUnits,
+			"priority":               rg.Priority,
+			"burstable":              rg.Burstable,
+			"consumed_request_units": consumed[rg.Name],
+		})
+	}
+	if err := d.Set("resource_groups", resourceGroups); err != nil {
+		return diag.Errorf("failed setting resource_groups field: %v", err)
+	}
+
+	total := totalResourceUnits(groups)
+	d.Set("total_resource_units", total)
+
+	capacity := calibrateResourceUnits(ctx, db)
+	d.Set("estimated_capacity", capacity)
+	if capacity > 0 {
+		d.Set("remaining_resource_units", capacity-total)
+	} else {
+		d.Set("remaining_resource_units", 0)
+	}
+
+	window, _ := time.ParseDuration(d.Get("usage_window").(string))
+	d.Set("resource_units_per_sec", readResourceUnitsPerSec(ctx, db, window))
+
+	d.SetId("resource_groups")
+
+	return nil
+}
+
+// parseResourceUnits parses the RU_PER_SEC of information_schema.resource_groups, which is
+// UNLIMITED for groups without a quota.
+func parseResourceUnits(ruPerSec string) int {
+	n, err := strconv.Atoi(ruPerSec)
+	if err != nil {
+		return unlimitedResourceUnits
+	}
+	return n
+}
+
+// totalResourceUnits sums the quotas of the groups, skipping unlimited groups.
+func totalResourceUnits(groups []ResourceGroup) int {
+	total := 0
+	for _, rg := range groups {
+		if rg.ResourceUnits > 0 {
+			total += rg.ResourceUnits
+		}
+	}
+	return total
+}
+
+// readConsumedRequestUnits returns the request units each group consumed on the last day
+// recorded in mysql.request_unit_by_group, which only exists since TiDB 7.6.
+func readConsumedRequestUnits(ctx context.Context, db *sql.DB) (map[string]int, error) {
+	query := `SELECT resource_group, total_ru FROM mysql.request_unit_by_group
+		WHERE end_time = (SELECT MAX(end_time) FROM mysql.request_unit_by_group)`
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	consumed := map[string]int{}
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		if mysqlErrorNumber(err) == unknownTableErrCode {
+			log.Printf("[DEBUG] RU consumption by group isn't recorded: %v", err)
+			return consumed, nil
+		}
+		return nil, fmt.Errorf("failed querying for RU consumption: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var totalRU int
+		if err := rows.Scan(&name, &totalRU); err != nil {
+			return nil, err
+		}
+		consumed[strings.ToLower(name)] = totalRU
+	}
+	return consumed, rows.Err()
+}
+
+// calibrateResourceUnits estimates the RU capacity of the cluster with CALIBRATE RESOURCE, or
+// returns 0 when TiDB can't estimate it, e.g. without enough TiKV stores.
+func calibrateResourceUnits(ctx context.Context, db *sql.DB) int {
+	query := "CALIBRATE RESOURCE"
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	var quota int
+	if err := db.QueryRowContext(ctx, query).Scan(&quota); err != nil {
+		log.Printf("[WARN] Could not estimate the RU capacity: %v", err)
+		return 0
+	}
+	return quota
+}
+
+// readResourceUnitsPerSec returns the average RU/s consumed by the cluster over the window,
+// from METRICS_SCHEMA, which needs Prometheus; it's 0 when the metrics aren't available.
+func readResourceUnitsPerSec(ctx context.Context, db *sql.DB, window time.Duration) float64 {
+	query := `SELECT IFNULL(AVG(value), 0) FROM METRICS_SCHEMA.resource_manager_resource_unit
+		WHERE time >= DATE_SUB(NOW(), INTERVAL ? SECOND) AND time <= NOW()`
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	var perSec float64
+	if err := db.QueryRowContext(ctx, query, int(window.Seconds())).Scan(&perSec); err != nil {
+		log.Printf("[WARN] Could not read the RU consumption from metrics: %v", err)
+		return 0
+	}
+	return perSec
+}
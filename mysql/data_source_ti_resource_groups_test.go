@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTiResourceGroups(t *testing.T) {
+	dataSourceName := "data.mysql_ti_resource_groups.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, ResourceGroupTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTiResourceGroupsConfig("tf-test-rg-usage", 1000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "resource_groups.*", map[string]string{
+						"name":           "tf-test-rg-usage",
+						"resource_units": "1000",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "resource_groups.*", map[string]string{
+						"name":           "default",
+						"resource_units": "-1",
+					}),
+					testAccTablesCount(dataSourceName, "total_resource_units", func(rn string, total int) error {
+						if total < 1000 {
+							return fmt.Errorf("%s: expected total_resource_units of at least 1000, got %d", rn, total)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestTotalResourceUnits(t *testing.T) {
+	groups := []ResourceGroup{
+		{Name: "default", ResourceUnits: parseResourceUnits("UNLIMITED")},
+		{Name: "a", ResourceUnits: parseResourceUnits("1000")},
+		{Name: "b", ResourceUnits: parseResourceUnits("500")},
+	}
+
+	if groups[0].ResourceUnits != unlimitedResourceUnits {
+		t.Errorf("expected UNLIMITED to parse as %d, got %d", unlimitedResourceUnits, groups[0].ResourceUnits)
+	}
+	if total := totalResourceUnits(groups); total != 1500 {
+		t.Errorf("expected a total of 1500, got %d", total)
+	}
+}
+
+func testAccTiResourceGroupsConfig(name string, ru int) string {
+	return fmt.Sprintf(`
+resource "mysql_ti_resource_group" "test" {
+	name           = "%s"
+	resource_units = %d
+}
+
+data "mysql_ti_resource_groups" "test" {
+	depends_on = [mysql_ti_resource_group.test]
+}
+`, name, ru)
+}
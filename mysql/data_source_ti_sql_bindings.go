@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTiSQLBindings() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ShowSQLBindings,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"bindings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sql_digest":   {Type: schema.TypeString, Computed: true},
+						"original_sql": {Type: schema.TypeString, Computed: true},
+						"bind_sql":     {Type: schema.TypeString, Computed: true},
+						"database":     {Type: schema.TypeString, Computed: true},
+						"status":       {Type: schema.TypeString, Computed: true},
+						"update_time":  {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ShowSQLBindings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "SQL bindings", SQLBindingTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot show SQL bindings: %v", err)
+	}
+
+	bindings, err := listSQLBindings(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	digests := make([]string, 0, len(bindings))
+	for digest, binding := range bindings {
+		if database == "" || binding.DefaultDB == database {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Strings(digests)
+
+	list := make([]interface{}, 0, len(digests))
+	for _, digest := range digests {
+		binding := bindings[digest]
+		list = append(list, map[string]interface{}{
+			"sql_digest":   binding.SQLDigest,
+			"original_sql": binding.OriginalSQL,
+			"bind_sql":     binding.BindSQL,
+			"database":     binding.DefaultDB,
+			"status":       binding.Status,
+			"update_time":  binding.UpdateTime,
+		})
+	}
+
+	if err := d.Set("bindings", list); err != nil {
+		return diag.Errorf("failed setting bindings field: %v", err)
+	}
+
+	if database != "" {
+		d.SetId(database)
+	} else {
+		d.SetId("bindings")
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTiSQLBindings(t *testing.T) {
+	dbName := "tf_sql_bindings"
+	dataSourceName := "data.mysql_ti_sql_bindings.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, SQLBindingTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSQLBindingConfig(dbName) + `
+data "mysql_ti_sql_bindings" "test" {
+	database = mysql_ti_sql_binding.test.database
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.#", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "bindings.0.sql_digest", "mysql_ti_sql_binding.test", "sql_digest"),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.database", dbName),
+					resource.TestCheckResourceAttr(dataSourceName, "bindings.0.status", "enabled"),
+				),
+			},
+		},
+	})
+}
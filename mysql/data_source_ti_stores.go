@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var StoreStatusTiDBMinVersion = "4.0.0"
+
+func dataSourceTiStores() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ShowStores,
+		Schema: map[string]*schema.Schema{
+			"stores": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"store_id":     {Type: schema.TypeInt, Computed: true},
+						"address":      {Type: schema.TypeString, Computed: true},
+						"state":        {Type: schema.TypeString, Computed: true},
+						"version":      {Type: schema.TypeString, Computed: true},
+						"capacity":     {Type: schema.TypeString, Computed: true},
+						"available":    {Type: schema.TypeString, Computed: true},
+						"leader_count": {Type: schema.TypeInt, Computed: true},
+						"region_count": {Type: schema.TypeInt, Computed: true},
+						"labels": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"label_values": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// storeLabel is an element of the LABEL column of information_schema.tikv_store_status.
+type storeLabel struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func ShowStores(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "stores", StoreStatusTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot show stores: %v", err)
+	}
+
+	query := `SELECT STORE_ID, ADDRESS, STORE_STATE_NAME, IFNULL(LABEL, ''), VERSION, CAPACITY, AVAILABLE, LEADER_COUNT, REGION_COUNT
+		FROM information_schema.tikv_store_status ORDER BY STORE_ID`
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return sqlErrorDiag("failed querying for stores", query, err)
+	}
+	defer rows.Close()
+
+	stores := []interface{}{}
+	labelValues := map[string]map[string]bool{}
+	for rows.Next() {
+		var storeID, leaderCount, regionCount int
+		var address, state, label, version, capacity, available string
+		if err := rows.Scan(&storeID, &address, &state, &label, &version, &capacity, &available, &leaderCount, &regionCount); err != nil {
+			return diag.Errorf("failed scanning stores: %v", err)
+		}
+
+		var parsed []storeLabel
+		if label != "" {
+			if err := json.Unmarshal([]byte(label), &parsed); err != nil {
+				return diag.Errorf("failed parsing labels of store %d: %v", storeID, err)
+			}
+		}
+
+		labels := map[string]interface{}{}
+		for _, l := range parsed {
+			labels[l.Key] = l.Value
+			if labelValues[l.Key] == nil {
+				labelValues[l.Key] = map[string]bool{}
+			}
+			labelValues[l.Key][l.Value] = true
+		}
+
+		stores = append(stores, map[string]interface{}{
+			"store_id":     storeID,
+			"address":      address,
+			"state":        state,
+			"version":      version,
+			"capacity":     capacity,
+			"available":    available,
+			"leader_count": leaderCount,
+			"region_count": regionCount,
+			"labels":       labels,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed reading stores: %v", err)
+	}
+
+	// The distinct values of each label, sorted and separated by commas like REGIONS of
+	// placement policies.
+	joined := map[string]interface{}{}
+	for key, values := range labelValues {
+		joined[key] = strings.Join(sortedKeys(values), ",")
+	}
+
+	if err := d.Set("stores", stores); err != nil {
+		return diag.Errorf("failed setting stores field: %v", err)
+	}
+	d.Set("label_values", joined)
+	d.SetId("stores")
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTiStores(t *testing.T) {
+	dataSourceName := "data.mysql_ti_stores.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, StoreStatusTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "mysql_ti_stores" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccTablesCount(dataSourceName, "stores.#", func(rn string, storeCount int) error {
+						if storeCount < 1 {
+							return fmt.Errorf("%s: stores not found", rn)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrSet(dataSourceName, "stores.0.address"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "stores.0.state"),
+				),
+			},
+		},
+	})
+}
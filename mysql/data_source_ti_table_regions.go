@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var TableRegionsTiDBMinVersion = "4.0.0"
+
+func dataSourceTiTableRegions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ShowTableRegions,
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"table": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"index": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"region_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"leader_counts": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"regions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"region_id":           {Type: schema.TypeInt, Computed: true},
+						"start_key":           {Type: schema.TypeString, Computed: true},
+						"end_key":             {Type: schema.TypeString, Computed: true},
+						"leader_store_id":     {Type: schema.TypeInt, Computed: true},
+						"peers":               {Type: schema.TypeString, Computed: true},
+						"written_bytes":       {Type: schema.TypeInt, Computed: true},
+						"read_bytes":          {Type: schema.TypeInt, Computed: true},
+						"approximate_size_mb": {Type: schema.TypeInt, Computed: true},
+						"approximate_keys":    {Type: schema.TypeInt, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ShowTableRegions(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "table regions", TableRegionsTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot show table regions: %v", err)
+	}
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	index := d.Get("index").(string)
+
+	stmtSQL := fmt.Sprintf("SHOW TABLE %s.%s", quoteIdentifier(database), quoteIdentifier(table))
+	if index != "" {
+		stmtSQL += " INDEX " + quoteIdentifier(index)
+	}
+	stmtSQL += " REGIONS"
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return sqlErrorDiag("failed querying for table regions", stmtSQL, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The columns differ between TiDB versions, so they're matched by name.
+	atoi := func(row map[string]string, column string) int {
+		n, _ := strconv.Atoi(row[column])
+		return n
+	}
+
+	regions := []interface{}{}
+	leaderCounts := map[string]interface{}{}
+	for rows.Next() {
+		row, err := scanRowMap(rows, columns)
+		if err != nil {
+			return diag.Errorf("failed scanning table regions: %v", err)
+		}
+
+		regions = append(regions, map[string]interface{}{
+			"region_id":           atoi(row, "region_id"),
+			"start_key":           row["start_key"],
+			"end_key":             row["end_key"],
+			"leader_store_id":     atoi(row, "leader_store_id"),
+			"peers":               row["peers"],
+			"written_bytes":       atoi(row, "written_bytes"),
+			"read_bytes":          atoi(row, "read_bytes"),
+			"approximate_size_mb": atoi(row, "approximate_size(mb)"),
+			"approximate_keys":    atoi(row, "approximate_keys"),
+		})
+
+		store := row["leader_store_id"]
+		count, _ := leaderCounts[store].(int)
+		leaderCounts[store] = count + 1
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed reading table regions: %v", err)
+	}
+
+	d.Set("region_count", len(regions))
+	d.Set("leader_counts", leaderCounts)
+	if err := d.Set("regions", regions); err != nil {
+		return diag.Errorf("failed setting regions field: %v", err)
+	}
+
+	id := fmt.Sprintf("%s.%s", database, table)
+	if index != "" {
+		id += "." + index
+	}
+	d.SetId(id)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTiTableRegions(t *testing.T) {
+	dataSourceName := "data.mysql_ti_table_regions.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, TableRegionsTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTiTableRegionsConfig("tf_table_regions"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "tf_table_regions.t"),
+					testAccTablesCount(dataSourceName, "region_count", func(rn string, regionCount int) error {
+						if regionCount < 1 {
+							return fmt.Errorf("%s: regions not found", rn)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrSet(dataSourceName, "regions.0.region_id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTiTableRegionsConfig(dbName string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+	name = "%s"
+}
+
+resource "mysql_sql" "table" {
+	name       = "table"
+	create_sql = "CREATE TABLE ${mysql_database.test.name}.t (a INT PRIMARY KEY)"
+	delete_sql = "DROP TABLE ${mysql_database.test.name}.t"
+}
+
+data "mysql_ti_table_regions" "test" {
+	database = mysql_database.test.name
+	table    = "t"
+
+	depends_on = [mysql_sql.table]
+}
+`, dbName)
+}
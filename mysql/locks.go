@@ -0,0 +1,19 @@
+package mysql
+
+// objectLocks serializes the changes of the same server object across resource types, e.g. of
+// a user, its grants and its default roles, as the checks before a change like the conflicting
+// grant check of mysql_grant are only correct while no other change of the object runs. Changes
+// of different objects don't wait for each other, so they can run in parallel once statements
+// run over several connections; with the single connection of the provider the statements
+// themselves are still run one at a time.
+var objectLocks = NewKeyedMutex()
+
+// accountLockKey is the key of a user or role in objectLocks.
+func accountLockKey(u UserOrRole) string {
+	return "account:" + u.IDString()
+}
+
+// databaseLockKey is the key of a database in objectLocks.
+func databaseLockKey(name string) string {
+	return "database:" + name
+}
@@ -0,0 +1,39 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObjectLocks(t *testing.T) {
+	jdoe := accountLockKey(UserOrRole{Name: "jdoe", Host: "%"})
+	objectLocks.Lock(jdoe)
+
+	// Another object doesn't wait for the lock of jdoe.
+	done := make(chan struct{})
+	go func() {
+		objectLocks.Lock(databaseLockKey("jdoe"))
+		objectLocks.Unlock(databaseLockKey("jdoe"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the lock of a database not to wait for the lock of an account")
+	}
+
+	// Another resource changing jdoe waits for it.
+	locked := make(chan struct{})
+	go func() {
+		objectLocks.Lock(accountLockKey(UserOrRole{Name: "jdoe", Host: "%"}))
+		objectLocks.Unlock(accountLockKey(UserOrRole{Name: "jdoe", Host: "%"}))
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("expected the lock of the same account to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	objectLocks.Unlock(jdoe)
+	<-locked
+}
@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	cachingSha2SaltLength    = 20
+	cachingSha2DigestLength  = 43
+	cachingSha2RoundsFactor  = 1000
+	sha256CryptB64Characters = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// nativePasswordHash returns the authentication string mysql_native_password stores
+// for password, i.e. what PASSWORD() returned before MySQL 8.0.
+func nativePasswordHash(password string) string {
+	first := sha1.Sum([]byte(password))
+	second := sha1.Sum(first[:])
+	return fmt.Sprintf("*%X", second)
+}
+
+// cachingSha2PasswordMatches checks password against an authentication string of
+// caching_sha2_password, which has the form $A$<rounds/1000 in hex>$<salt><digest>.
+func cachingSha2PasswordMatches(password string, authString []byte) (bool, error) {
+	parts := bytes.SplitN(authString, []byte("$"), 4)
+	if len(parts) != 4 || len(parts[0]) != 0 || string(parts[1]) != "A" {
+		return false, fmt.Errorf("unexpected caching_sha2_password authentication string format")
+	}
+
+	rounds, err := strconv.ParseInt(string(parts[2]), 16, 32)
+	if err != nil {
+		return false, fmt.Errorf("failed parsing caching_sha2_password rounds: %v", err)
+	}
+
+	saltAndDigest := parts[3]
+	if len(saltAndDigest) != cachingSha2SaltLength+cachingSha2DigestLength {
+		return false, fmt.Errorf("unexpected caching_sha2_password authentication string length")
+	}
+	salt := saltAndDigest[:cachingSha2SaltLength]
+	digest := saltAndDigest[cachingSha2SaltLength:]
+
+	computed := sha256Crypt([]byte(password), salt, int(rounds)*cachingSha2RoundsFactor)
+	return computed == string(digest), nil
+}
+
+// sha256Crypt implements the SHA-256 based crypt(3) scheme by Ulrich Drepper and
+// returns the encoded digest without the salt and rounds prefix.
+func sha256Crypt(password, salt []byte, rounds int) string {
+	b := sha256.New()
+	b.Write(password)
+	b.Write(salt)
+	b.Write(password)
+	digestB := b.Sum(nil)
+
+	a := sha256.New()
+	a.Write(password)
+	a.Write(salt)
+	n := len(password)
+	for ; n > sha256.Size; n -= sha256.Size {
+		a.Write(digestB)
+	}
+	a.Write(digestB[:n])
+	for n = len(password); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			a.Write(digestB)
+		} else {
+			a.Write(password)
+		}
+	}
+	digestA := a.Sum(nil)
+
+	dp := sha256.New()
+	for i := 0; i < len(password); i++ {
+		dp.Write(password)
+	}
+	pSeq := repeatToLength(dp.Sum(nil), len(password))
+
+	ds := sha256.New()
+	for i := 0; i < 16+int(digestA[0]); i++ {
+		ds.Write(salt)
+	}
+	sSeq := repeatToLength(ds.Sum(nil), len(salt))
+
+	c := digestA
+	for i := 0; i < rounds; i++ {
+		h := sha256.New()
+		if i&1 != 0 {
+			h.Write(pSeq)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(sSeq)
+		}
+		if i%7 != 0 {
+			h.Write(pSeq)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(pSeq)
+		}
+		c = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	for _, g := range [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+	} {
+		sha256CryptB64(&out, c[g[0]], c[g[1]], c[g[2]], 4)
+	}
+	sha256CryptB64(&out, 0, c[31], c[30], 3)
+	return out.String()
+}
+
+func sha256CryptB64(out *strings.Builder, b2, b1, b0 byte, n int) {
+	w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+	for ; n > 0; n-- {
+		out.WriteByte(sha256CryptB64Characters[w&0x3f])
+		w >>= 6
+	}
+}
+
+func repeatToLength(digest []byte, length int) []byte {
+	out := make([]byte, 0, length)
+	for len(out) < length {
+		n := length - len(out)
+		if n > len(digest) {
+			n = len(digest)
+		}
+		out = append(out, digest[:n]...)
+	}
+	return out
+}
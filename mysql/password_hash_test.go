@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestSha256Crypt(t *testing.T) {
+	// Expected values match glibc crypt(3) with the $5$ prefix.
+	cases := []struct {
+		password string
+		salt     string
+		rounds   int
+		expected string
+	}{
+		{"Hello world!", "saltstring", 5000, "5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+		{"Hello world!", "saltstringsaltst", 10000, "3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA"},
+		{"This is just a test", "toolongsaltstrin", 5000, "Un/5jzAHMgOGZ5.mWJpuVolil07guHPvOW8mGRcvxa5"},
+	}
+
+	for _, c := range cases {
+		if got := sha256Crypt([]byte(c.password), []byte(c.salt), c.rounds); got != c.expected {
+			t.Errorf("sha256Crypt(%q, %q, %d) = %q, expected %q", c.password, c.salt, c.rounds, got, c.expected)
+		}
+	}
+}
+
+func TestCachingSha2PasswordMatches(t *testing.T) {
+	salt := "0123456789abcdefghij"
+	authString := []byte("$A$005$" + salt + sha256Crypt([]byte("password"), []byte(salt), 5000))
+
+	matches, err := cachingSha2PasswordMatches("password", authString)
+	if err != nil || !matches {
+		t.Errorf("expected password to match, got %v, %v", matches, err)
+	}
+
+	matches, err = cachingSha2PasswordMatches("password2", authString)
+	if err != nil || matches {
+		t.Errorf("expected password2 not to match, got %v, %v", matches, err)
+	}
+
+	if _, err := cachingSha2PasswordMatches("password", []byte("*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19")); err == nil {
+		t.Errorf("expected an error for a mysql_native_password authentication string")
+	}
+}
+
+func TestNativePasswordHash(t *testing.T) {
+	if got := nativePasswordHash("password"); got != "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19" {
+		t.Errorf("unexpected hash %q", got)
+	}
+}
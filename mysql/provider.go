@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -27,6 +28,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"cloud.google.com/go/cloudsqlconn"
+	"cloud.google.com/go/cloudsqlconn/instance"
 	cloudsql "cloud.google.com/go/cloudsqlconn/mysql/mysql"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
@@ -47,6 +49,13 @@ const (
 type OneConnection struct {
 	Db      *sql.DB
 	Version *version.Version
+	// Rds is whether the server is Amazon RDS or Aurora, and Aurora whether it's Aurora.
+	Rds    bool
+	Aurora bool
+	// Flavor is the kind of server, and FlavorVersion its own version, e.g. of TiDB rather
+	// than the MySQL version it reports compatibility with.
+	Flavor        serverFlavor
+	FlavorVersion *version.Version
 }
 
 type MySQLConfiguration struct {
@@ -54,6 +63,17 @@ type MySQLConfiguration struct {
 	MaxConnLifetime        time.Duration
 	MaxOpenConns           int
 	ConnectRetryTimeoutSec time.Duration
+	DryRun                 bool
+	AllowReadOnly          bool
+	GrantHostMatching      grantHostMatching
+	// ServerFlavor and AssumeRds override the detection of the server when set.
+	ServerFlavor serverFlavor
+	AssumeRds    *bool
+	// CloudSQLIPType is the IP type Cloud SQL instances are dialed with.
+	CloudSQLIPType string
+	// PasswordFunc fetches the password for each new connection when set, e.g. an Azure AD
+	// token that would expire during a long apply.
+	PasswordFunc func(ctx context.Context) (string, error)
 }
 
 type CustomTLS struct {
@@ -87,6 +107,11 @@ func Provider() *schema.Provider {
 					if value == "" {
 						errors = append(errors, fmt.Errorf("endpoint must not be an empty string"))
 					}
+					if connName, ok := strings.CutPrefix(value, "cloudsql://"); ok {
+						if _, err := instance.ParseConnName(connName); err != nil {
+							errors = append(errors, fmt.Errorf("endpoint must be cloudsql://project:region:instance for Cloud SQL, got %q: %v", value, err))
+						}
+					}
 
 					return
 				},
@@ -181,16 +206,54 @@ func Provider() *schema.Provider {
 				Default:  300,
 			},
 
-			"iam_database_authentication": {
+			"dry_run": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_DRY_RUN", false),
+			},
+
+			"allow_read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_ALLOW_READ_ONLY", false),
+			},
+
+			"grant_host_matching": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("MYSQL_GRANT_HOST_MATCHING", string(grantHostMatchingNormalize)),
+				ValidateFunc: validation.StringInSlice(grantHostMatchings, false),
+			},
+
+			"server_flavor": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("MYSQL_SERVER_FLAVOR", ""),
+				ValidateFunc: validation.StringInSlice(serverFlavors, false),
+			},
+
+			"assume_rds": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  false,
 			},
-			"private_ip": {
+
+			"iam_database_authentication": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
 			},
+			"private_ip": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"cloudsql_ip_type"},
+			},
+			"cloudsql_ip_type": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringInSlice(cloudsqlIPTypes, false),
+				ConflictsWith: []string{"private_ip"},
+			},
 			"azure_config": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -241,8 +304,14 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"mysql_databases": dataSourceDatabases(),
-			"mysql_tables":    dataSourceTables(),
+			"mysql_databases":          dataSourceDatabases(),
+			"mysql_tables":             dataSourceTables(),
+			"mysql_ti_table_regions":   dataSourceTiTableRegions(),
+			"mysql_ti_stores":          dataSourceTiStores(),
+			"mysql_ti_resource_groups": dataSourceTiResourceGroups(),
+			"mysql_ti_placement":       dataSourceTiPlacement(),
+			"mysql_ti_sql_bindings":    dataSourceTiSQLBindings(),
+			"mysql_rds_configuration":  dataSourceRDSConfiguration(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -254,10 +323,22 @@ func Provider() *schema.Provider {
 			"mysql_user_password":     resourceUserPassword(),
 			"mysql_user":              resourceUser(),
 			"mysql_ti_config":         resourceTiConfigVariable(),
+			"mysql_ti_configs":        resourceTiConfigs(),
 			"mysql_ti_resource_group": resourceTiResourceGroup(),
 			"mysql_ti_resource_group_user_assignment": resourceTiResourceGroupUserAssignment(),
+			"mysql_ti_resource_group_users":           resourceTiResourceGroupUsers(),
+			"mysql_ti_placement_policy":               resourceTiPlacementPolicy(),
+			"mysql_ti_sql_binding":                    resourceTiSQLBinding(),
+			"mysql_ti_flash_replica":                  resourceTiFlashReplica(),
+			"mysql_ti_sequence":                       resourceTiSequence(),
+			"mysql_ti_instance_variable":              resourceTiInstanceVariable(),
+			"mysql_ti_gc_config":                      resourceTiGCConfig(),
+			"mysql_ti_log_backup":                     resourceTiLogBackup(),
 			"mysql_rds_config":                        resourceRDSConfig(),
+			"mysql_rds_replication":                   resourceRDSReplication(),
 			"mysql_default_roles":                     resourceDefaultRoles(),
+			"mysql_role_assignment":                   resourceRoleAssignment(),
+			"mysql_migration":                         resourceMigration(),
 		},
 
 		ConfigureContextFunc: providerConfigure,
@@ -273,6 +354,8 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	var password = d.Get("password").(string)
 	var iamAuth = d.Get("iam_database_authentication").(bool)
 	var privateIp = d.Get("private_ip").(bool)
+	var cloudsqlIPType = d.Get("cloudsql_ip_type").(string)
+	var passwordFunc func(ctx context.Context) (string, error)
 	var tlsConfig = d.Get("tls").(string)
 	var tlsConfigStruct *tls.Config
 
@@ -334,25 +417,21 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	} else if strings.HasPrefix(endpoint, "cloudsql://") {
 		proto = "cloudsql"
 		endpoint = strings.ReplaceAll(endpoint, "cloudsql://", "")
-		var err error
-		if iamAuth { // Access token will be in the password field
-
-			var opts []cloudsqlconn.Option
+		if cloudsqlIPType == "" && privateIp {
+			cloudsqlIPType = cloudsqlIPTypePrivate
+		}
 
+		opts := []cloudsqlconn.Option{
+			cloudsqlconn.WithDefaultDialOptions(cloudsqlDialOptions(cloudsqlIPType)...),
+		}
+		if iamAuth { // Access token will be in the password field
 			token := oauth2.StaticTokenSource(&oauth2.Token{
 				AccessToken: password,
 			})
 			opts = append(opts, cloudsqlconn.WithIAMAuthN())
 			opts = append(opts, cloudsqlconn.WithIAMAuthNTokenSources(token, token))
-			_, err = cloudsql.RegisterDriver("cloudsql", opts...)
-		} else {
-			var endpointParams []cloudsqlconn.DialOption
-			if privateIp {
-				endpointParams = append(endpointParams, cloudsqlconn.WithPrivateIP())
-			}
-
-			_, err = cloudsql.RegisterDriver("cloudsql", cloudsqlconn.WithDefaultDialOptions(endpointParams...))
 		}
+		_, err := cloudsql.RegisterDriver("cloudsql", opts...)
 		if err != nil {
 			return nil, diag.Errorf("failed to register driver %v", err)
 		}
@@ -414,16 +493,23 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 			return nil, diag.Errorf("failed to create Azure credential %v", err)
 		}
 
-		azToken, err := azCredential.GetToken(
-			ctx,
-			policy.TokenRequestOptions{Scopes: []string{azScope + "/.default"}},
-		)
+		// Tokens expire within hours, so each new connection gets a fresh one. The credential
+		// caches the token until it's about to expire.
+		passwordFunc = func(ctx context.Context) (string, error) {
+			azToken, err := azCredential.GetToken(
+				ctx,
+				policy.TokenRequestOptions{Scopes: []string{azScope + "/.default"}},
+			)
+			if err != nil {
+				return "", fmt.Errorf("failed to get token from Azure AD: %w", err)
+			}
+			return azToken.Token, nil
+		}
 
+		password, err = passwordFunc(ctx)
 		if err != nil {
-			return nil, diag.Errorf("failed to get token from Azure AD: %v", err)
+			return nil, diag.FromErr(err)
 		}
-
-		password = azToken.Token
 	}
 
 	for k, vint := range d.Get("conn_params").(map[string]interface{}) {
@@ -464,40 +550,109 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		MaxConnLifetime:        time.Duration(d.Get("max_conn_lifetime_sec").(int)) * time.Second,
 		MaxOpenConns:           d.Get("max_open_conns").(int),
 		ConnectRetryTimeoutSec: time.Duration(d.Get("connect_retry_timeout_sec").(int)) * time.Second,
+		DryRun:                 d.Get("dry_run").(bool),
+		AllowReadOnly:          d.Get("allow_read_only").(bool),
+		GrantHostMatching:      grantHostMatching(d.Get("grant_host_matching").(string)),
+		ServerFlavor:           serverFlavor(d.Get("server_flavor").(string)),
+		CloudSQLIPType:         cloudsqlIPType,
+		PasswordFunc:           passwordFunc,
+	}
+	if assumeRds := d.GetRawConfig().GetAttr("assume_rds"); !assumeRds.IsNull() {
+		rds := assumeRds.True()
+		mysqlConf.AssumeRds = &rds
 	}
 
 	return mysqlConf, nil
 }
 
-func afterConnectVersion(ctx context.Context, mysqlConf *MySQLConfiguration, db *sql.DB) (*version.Version, error) {
-	// Set up env so that we won't create users randomly.
-	currentVersion, err := serverVersion(db)
-	if err != nil {
-		return nil, fmt.Errorf("failed getting server version: %v", err)
+// connectedServerVersion returns the version the server reports, which is detected once per DSN.
+func connectedServerVersion(ctx context.Context, mysqlConf *MySQLConfiguration, db *sql.DB) (*version.Version, error) {
+	dsn := mysqlConf.Config.FormatDSN()
+	currentVersion := cachedServerVersion(dsn)
+	if currentVersion == nil {
+		var err error
+		currentVersion, err = serverVersion(ctx, db)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting server version: %v", err)
+		}
+		cacheServerVersion(dsn, currentVersion)
 	}
+	return currentVersion, nil
+}
 
-	versionMinInclusive, _ := version.NewVersion("5.7.5")
-	versionMaxExclusive, _ := version.NewVersion("8.0.0")
-	if currentVersion.GreaterThanOrEqual(versionMinInclusive) &&
-		currentVersion.LessThan(versionMaxExclusive) {
-		// We set NO_AUTO_CREATE_USER to prevent provider from creating user when creating grants. Newer MySQL has it automatically.
-		// We don't want any other modes, esp. not ANSI_QUOTES.
-		_, err = db.ExecContext(ctx, `SET SESSION sql_mode='NO_AUTO_CREATE_USER'`)
-		if err != nil {
-			return nil, fmt.Errorf("failed setting SQL mode: %v", err)
+// setupSession sets the sql_mode of the provider, so that we won't create users randomly.
+// Connections opened later, e.g. after max_conn_lifetime_sec, run the same setup.
+func setupSession(ctx context.Context, db *sql.DB, connector *providerConnector, connection *OneConnection) error {
+	setup := sessionSQLMode(connection.Flavor, connection.FlavorVersion)
+	connector.setSessionSetup([]string{setup})
+	if _, err := db.ExecContext(ctx, setup); err != nil {
+		if mysqlErrorNumber(err) == 0 {
+			return fmt.Errorf("failed setting SQL mode: %v", err)
 		}
-	} else {
-		// We don't want any modes, esp. not ANSI_QUOTES.
-		_, err = db.ExecContext(ctx, `SET SESSION sql_mode=''`)
-		if err != nil {
-			return nil, fmt.Errorf("failed setting SQL mode: %v", err)
+		// Some managed services don't allow changing sql_mode, so the provider works with the
+		// mode it keeps where it can, e.g. output quoted with ANSI_QUOTES is parsed too.
+		connector.setSessionSetup(nil)
+		var sqlMode string
+		if modeErr := db.QueryRowContext(ctx, "SELECT @@SESSION.sql_mode").Scan(&sqlMode); modeErr != nil {
+			return fmt.Errorf("could not set the SQL mode (%v) nor read the one kept: %v", err, modeErr)
 		}
+		log.Printf("[WARN] Could not set the SQL mode, keeping %q: %v", sqlMode, err)
+		return checkKeptSQLMode(sqlMode, connection.Flavor, connection.FlavorVersion)
 	}
+	return nil
+}
 
-	return currentVersion, nil
+// checkKeptSQLMode checks the sql_mode of the session when it couldn't be set. Literals are
+// escaped with backslashes, which NO_BACKSLASH_ESCAPES would keep as they are, and without
+// NO_AUTO_CREATE_USER, GRANT creates missing users on servers before MySQL 8.
+func checkKeptSQLMode(sqlMode string, flavor serverFlavor, flavorVersion *version.Version) error {
+	modes := strings.Split(strings.ToUpper(sqlMode), ",")
+	hasMode := func(mode string) bool {
+		for _, m := range modes {
+			if strings.TrimSpace(m) == mode {
+				return true
+			}
+		}
+		return false
+	}
+
+	if hasMode("NO_BACKSLASH_ESCAPES") {
+		return fmt.Errorf("the session keeps sql_mode %q, whose NO_BACKSLASH_ESCAPES breaks the escaping of literals; remove it from the default sql_mode of the server", sqlMode)
+	}
+	if flavorSupports(flavor, flavorVersion, capabilityNoAutoCreateUser) == nil && !hasMode("NO_AUTO_CREATE_USER") {
+		log.Printf("[WARN] The session keeps sql_mode %q without NO_AUTO_CREATE_USER, so mysql_grant creates users that don't exist instead of failing", sqlMode)
+	}
+	return nil
+}
+
+// sessionSQLMode returns the statement setting the sql_mode of the sessions of the provider.
+func sessionSQLMode(flavor serverFlavor, flavorVersion *version.Version) string {
+	if flavorSupports(flavor, flavorVersion, capabilityNoAutoCreateUser) == nil {
+		// We set NO_AUTO_CREATE_USER to prevent provider from creating user when creating grants. Newer MySQL has it automatically.
+		// We don't want any other modes, esp. not ANSI_QUOTES.
+		return `SET SESSION sql_mode='NO_AUTO_CREATE_USER'`
+	}
+	// We don't want any modes, esp. not ANSI_QUOTES.
+	return `SET SESSION sql_mode=''`
+}
+
+var (
+	serverVersionsMtx sync.Mutex
+	// serverVersions are the versions of the servers by DSN, detected once per provider process.
+	serverVersions = map[string]*version.Version{}
+)
+
+func cachedServerVersion(dsn string) *version.Version {
+	serverVersionsMtx.Lock()
+	defer serverVersionsMtx.Unlock()
+	return serverVersions[dsn]
 }
 
-var identQuoteReplacer = strings.NewReplacer("`", "``")
+func cacheServerVersion(dsn string, v *version.Version) {
+	serverVersionsMtx.Lock()
+	defer serverVersionsMtx.Unlock()
+	serverVersions[dsn] = v
+}
 
 func makeDialer(d *schema.ResourceData) (proxy.Dialer, error) {
 	proxyFromEnv := proxy.FromEnvironment()
@@ -519,13 +674,25 @@ func makeDialer(d *schema.ResourceData) (proxy.Dialer, error) {
 	return proxyFromEnv, nil
 }
 
-func quoteIdentifier(in string) string {
-	return fmt.Sprintf("`%s`", identQuoteReplacer.Replace(in))
+// defaultResourceTimeouts returns the default timeouts of the resource operations, which bound the
+// contexts of their statements and can be changed in a timeouts block. Resources which are replaced
+// on every change have no update timeout.
+func defaultResourceTimeouts(update bool) *schema.ResourceTimeout {
+	timeout := schema.DefaultTimeout(20 * time.Minute)
+	timeouts := &schema.ResourceTimeout{
+		Create: timeout,
+		Read:   timeout,
+		Delete: timeout,
+	}
+	if update {
+		timeouts.Update = timeout
+	}
+	return timeouts
 }
 
-func serverVersion(db *sql.DB) (*version.Version, error) {
+func serverVersion(ctx context.Context, db *sql.DB) (*version.Version, error) {
 	var versionString string
-	err := db.QueryRow("SELECT @@GLOBAL.version").Scan(&versionString)
+	err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.version").Scan(&versionString)
 	if err != nil {
 		return nil, err
 	}
@@ -534,9 +701,9 @@ func serverVersion(db *sql.DB) (*version.Version, error) {
 	return version.NewVersion(versionString)
 }
 
-func serverVersionString(db *sql.DB) (string, error) {
+func serverVersionString(ctx context.Context, db *sql.DB) (string, error) {
 	var versionString string
-	err := db.QueryRow("SELECT @@GLOBAL.version").Scan(&versionString)
+	err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.version").Scan(&versionString)
 	if err != nil {
 		return "", err
 	}
@@ -549,8 +716,8 @@ func serverVersionString(db *sql.DB) (string, error) {
 // - tidbVersion
 // - mysqlCompatibilityVersion
 // - err
-func serverTiDB(db *sql.DB) (bool, string, string, error) {
-	currentVersionString, err := serverVersionString(db)
+func serverTiDB(ctx context.Context, db *sql.DB) (bool, string, string, error) {
+	currentVersionString, err := serverVersionString(ctx, db)
 	if err != nil {
 		return false, "", "", err
 	}
@@ -563,9 +730,36 @@ func serverTiDB(db *sql.DB) (bool, string, string, error) {
 	return false, "", "", nil
 }
 
-func serverRds(db *sql.DB) (bool, error) {
+// checkTiDBFeatureSupport returns an error naming the feature unless the server is TiDB of
+// at least minVersion.
+func checkTiDBFeatureSupport(ctx context.Context, db *sql.DB, feature string, minVersion string) error {
+	isTiDB, tidbVersion, _, err := serverTiDB(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !isTiDB {
+		return fmt.Errorf("%s are only available on TiDB", feature)
+	}
+
+	requiredVersion, _ := version.NewVersion(minVersion)
+	if currentVersion, err := version.NewVersion(tidbVersion); err != nil || currentVersion.LessThan(requiredVersion) {
+		return fmt.Errorf("%s require TiDB version %s or later", feature, minVersion)
+	}
+	return nil
+}
+
+func serverMariaDB(ctx context.Context, db *sql.DB) (bool, error) {
+	currentVersionString, err := serverVersionString(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(currentVersionString, "MariaDB"), nil
+}
+
+func serverRds(ctx context.Context, db *sql.DB) (bool, error) {
 	var metadataVersionString string
-	err := db.QueryRow("SELECT @@GLOBAL.datadir").Scan(&metadataVersionString)
+	err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.datadir").Scan(&metadataVersionString)
 	if err != nil {
 		return false, err
 	}
@@ -577,6 +771,103 @@ func serverRds(db *sql.DB) (bool, error) {
 	return false, nil
 }
 
+// readOnlyVariables are the variables that make a server refuse writes. Servers without one
+// of them, like TiDB without innodb_read_only, skip it.
+var readOnlyVariables = []string{"read_only", "innodb_read_only"}
+
+// serverReadOnly returns the first of readOnlyVariables that is ON, or an empty string.
+func serverReadOnly(ctx context.Context, db *sql.DB) string {
+	for _, variable := range readOnlyVariables {
+		var value string
+		if err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT @@GLOBAL.%s", variable)).Scan(&value); err != nil {
+			log.Printf("[DEBUG] Could not read %s: %v", variable, err)
+			continue
+		}
+		if value == "1" || strings.EqualFold(value, "ON") {
+			return variable
+		}
+	}
+	return ""
+}
+
+const (
+	cloudsqlIPTypePublic  = "public"
+	cloudsqlIPTypePrivate = "private"
+	cloudsqlIPTypePSC     = "psc"
+	cloudsqlIPTypeAuto    = "auto"
+)
+
+var cloudsqlIPTypes = []string{cloudsqlIPTypePublic, cloudsqlIPTypePrivate, cloudsqlIPTypePSC, cloudsqlIPTypeAuto}
+
+// cloudsqlDialOptions selects the IP Cloud SQL instances are dialed on. Without an IP type
+// the connector dials the public IP.
+func cloudsqlDialOptions(ipType string) []cloudsqlconn.DialOption {
+	switch ipType {
+	case cloudsqlIPTypePublic:
+		return []cloudsqlconn.DialOption{cloudsqlconn.WithPublicIP()}
+	case cloudsqlIPTypePrivate:
+		return []cloudsqlconn.DialOption{cloudsqlconn.WithPrivateIP()}
+	case cloudsqlIPTypePSC:
+		return []cloudsqlconn.DialOption{cloudsqlconn.WithPSC()}
+	case cloudsqlIPTypeAuto:
+		return []cloudsqlconn.DialOption{cloudsqlconn.WithAutoIP()}
+	}
+	return nil
+}
+
+// cloudsqlConnectHint explains the usual reasons the connector can't reach an instance.
+func cloudsqlConnectHint(connName, ipType string) string {
+	if ipType == "" {
+		ipType = cloudsqlIPTypePublic
+	}
+	hint := fmt.Sprintf("The Cloud SQL instance %s was dialed on its %s IP. Check that the instance exists, "+
+		"that the credentials may use the Cloud SQL Admin API (roles/cloudsql.client)", connName, ipType)
+	switch ipType {
+	case cloudsqlIPTypePrivate:
+		hint += ", and that Terraform runs in a network peered with the VPC of the instance"
+	case cloudsqlIPTypePSC:
+		hint += ", and that Private Service Connect is enabled on the instance with an endpoint reachable from here"
+	default:
+		hint += ", and that the instance has an IP of this type; set cloudsql_ip_type otherwise"
+	}
+	return hint + "."
+}
+
+// detectRds returns whether the server is Amazon RDS and whether it's Aurora, which has
+// @@aurora_version. Failures are taken as neither.
+func detectRds(ctx context.Context, db *sql.DB) (bool, bool) {
+	var auroraVersion string
+	if err := db.QueryRowContext(ctx, "SELECT @@aurora_version").Scan(&auroraVersion); err == nil {
+		log.Printf("[DEBUG] Connected to Aurora %s", auroraVersion)
+		return true, true
+	}
+
+	rds, err := serverRds(ctx, db)
+	if err != nil {
+		log.Printf("[WARN] Could not detect RDS: %v", err)
+		return false, false
+	}
+	return rds, false
+}
+
+// passwordRefreshingConnector returns a connector which sets the password from PasswordFunc
+// before each new connection.
+func passwordRefreshingConnector(conf *MySQLConfiguration) (driver.Connector, error) {
+	config := conf.Config.Clone()
+	err := config.Apply(mysql.BeforeConnect(func(ctx context.Context, config *mysql.Config) error {
+		password, err := conf.PasswordFunc(ctx)
+		if err != nil {
+			return err
+		}
+		config.Passwd = password
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return mysql.NewConnector(config)
+}
+
 func connectToMySQL(ctx context.Context, conf *MySQLConfiguration) (*sql.DB, error) {
 	conn, err := connectToMySQLInternal(ctx, conf)
 	if err != nil {
@@ -593,6 +884,17 @@ func connectToMySQLInternal(ctx context.Context, conf *MySQLConfiguration) (*One
 
 	dsn := conf.Config.FormatDSN()
 	log.Printf("[DEBUG] Using dsn: %s", dsn)
+	if conf.DryRun {
+		// Dry run connections don't execute statements, so they can't be shared.
+		dsn += "#dry_run"
+	}
+	if conf.ServerFlavor != "" || conf.AssumeRds != nil {
+		// Neither can connections whose detection is overridden differently.
+		dsn += fmt.Sprintf("#flavor=%s", conf.ServerFlavor)
+		if conf.AssumeRds != nil {
+			dsn += fmt.Sprintf("#rds=%t", *conf.AssumeRds)
+		}
+	}
 	if connectionCache[dsn] != nil {
 		return connectionCache[dsn], nil
 	}
@@ -608,6 +910,7 @@ func connectToMySQLInternal(ctx context.Context, conf *MySQLConfiguration) (*One
 
 func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*OneConnection, error) {
 	var db *sql.DB
+	var providerConn *providerConnector
 	var err error
 
 	driverName := "mysql"
@@ -628,9 +931,21 @@ func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*OneCon
 			}
 			return retry.RetryableError(err)
 		}
+		var connector driver.Connector
+		if conf.PasswordFunc != nil {
+			connector, err = passwordRefreshingConnector(conf)
+			if err != nil {
+				return retry.NonRetryableError(err)
+			}
+		}
+		providerConn = &providerConnector{driver: db.Driver(), dsn: conf.Config.FormatDSN(), connector: connector, cache: newReadCache()}
+		db.Close()
+		db = sql.OpenDB(providerConn)
 
 		err = db.PingContext(ctx)
 		if err != nil {
+			// The next attempt opens a new pool.
+			db.Close()
 			if mysqlErrorNumber(err) != 0 || cloudsqlErrorNumber(err) != 0 || ctx.Err() != nil {
 				return retry.NonRetryableError(err)
 			}
@@ -642,6 +957,9 @@ func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*OneCon
 	})
 
 	if retryError != nil {
+		if driverName == "cloudsql" {
+			return nil, fmt.Errorf("could not connect to server: %s\n%s", retryError, cloudsqlConnectHint(conf.Config.Addr, conf.CloudSQLIPType))
+		}
 		return nil, fmt.Errorf("could not connect to server: %s", retryError)
 	}
 	db.SetConnMaxLifetime(conf.MaxConnLifetime)
@@ -651,13 +969,58 @@ func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*OneCon
 	// TODO: find a way to support more open connections while able to set custom settings for each of them.
 	db.SetMaxOpenConns(1)
 
-	currentVersion, err := afterConnectVersion(ctx, conf, db)
+	currentVersion, err := connectedServerVersion(ctx, conf, db)
 	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed running after connect command: %v", err)
 	}
+	if !conf.AllowReadOnly {
+		if variable := serverReadOnly(ctx, db); variable != "" {
+			db.Close()
+			return nil, fmt.Errorf("the server is read-only (%s is ON), e.g. an Aurora reader or a demoted primary; "+
+				"connect to the writer, or set allow_read_only = true to only read from it", variable)
+		}
+	}
 
-	return &OneConnection{
+	rds, aurora := detectRds(ctx, db)
+	flavor, flavorVersion, err := detectFlavor(ctx, db)
+	if err != nil {
+		log.Printf("[WARN] Could not detect the server flavor, assuming MySQL: %v", err)
+		flavor, flavorVersion = flavorMySQL, currentVersion
+	}
+	connection := &OneConnection{
 		Db:      db,
 		Version: currentVersion,
-	}, nil
+		Rds:     rds,
+		Aurora:  aurora,
+
+		Flavor:        flavor,
+		FlavorVersion: flavorVersion,
+	}
+	conf.overrideDetection(connection)
+	if err := setupSession(ctx, db, providerConn, connection); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed running after connect command: %v", err)
+	}
+	if conf.DryRun {
+		log.Printf("[WARN] Dry run enabled, statements are logged instead of executed")
+		providerConn.dryRun.Store(true)
+	}
+	registerReadCache(db, providerConn.cache)
+	return connection, nil
+}
+
+// overrideDetection applies server_flavor and assume_rds to what was detected on connecting.
+func (conf *MySQLConfiguration) overrideDetection(connection *OneConnection) {
+	if conf.AssumeRds != nil {
+		log.Printf("[DEBUG] Assuming the server is RDS: %t, detected %t", *conf.AssumeRds, connection.Rds)
+		connection.Rds = *conf.AssumeRds
+		connection.Aurora = connection.Aurora && *conf.AssumeRds
+	}
+	if conf.ServerFlavor != "" && conf.ServerFlavor != connection.Flavor {
+		// The version of another flavor isn't known, so the reported version is used instead.
+		log.Printf("[DEBUG] Assuming the server is %s %s, detected %s %s", conf.ServerFlavor, connection.Version, connection.Flavor, connection.FlavorVersion)
+		connection.Flavor = conf.ServerFlavor
+		connection.FlavorVersion = connection.Version.Core()
+	}
 }
@@ -2,11 +2,15 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/hashicorp/go-version"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -49,6 +53,18 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProvider_resourceTimeouts(t *testing.T) {
+	for name, r := range Provider().ResourcesMap {
+		if r.Timeouts == nil || r.Timeouts.Create == nil || r.Timeouts.Read == nil || r.Timeouts.Delete == nil {
+			t.Errorf("%s has no create, read and delete timeouts", name)
+			continue
+		}
+		if hasUpdate := r.UpdateContext != nil; hasUpdate != (r.Timeouts.Update != nil) {
+			t.Errorf("%s has an update timeout: %t, expected %t", name, !hasUpdate, hasUpdate)
+		}
+	}
+}
+
 func TestProvider_impl(t *testing.T) {
 	var _ = Provider()
 }
@@ -79,7 +95,7 @@ func testAccPreCheckSkipNotRds(t *testing.T) {
 		return
 	}
 
-	rdsEnabled, err := serverRds(db)
+	rdsEnabled, err := serverRds(ctx, db)
 	if err != nil {
 		return
 	}
@@ -101,7 +117,7 @@ func testAccPreCheckSkipRds(t *testing.T) {
 		return
 	}
 
-	rdsEnabled, err := serverRds(db)
+	rdsEnabled, err := serverRds(ctx, db)
 	if err != nil {
 		return
 	}
@@ -121,7 +137,7 @@ func testAccPreCheckSkipTiDB(t *testing.T) {
 		return
 	}
 
-	currentVersionString, err := serverVersionString(db)
+	currentVersionString, err := serverVersionString(ctx, db)
 	if err != nil {
 		t.Fatalf("Cannot get DB version string (SkipTiDB): %v", err)
 		return
@@ -142,7 +158,7 @@ func testAccPreCheckSkipMariaDB(t *testing.T) {
 		return
 	}
 
-	currentVersionString, err := serverVersionString(db)
+	currentVersionString, err := serverVersionString(ctx, db)
 	if err != nil {
 		t.Fatalf("Cannot get DB version string (SkipMariaDB): %v", err)
 		return
@@ -153,6 +169,27 @@ func testAccPreCheckSkipMariaDB(t *testing.T) {
 	}
 }
 
+func testAccPreCheckSkipNotMariaDB(t *testing.T) {
+	testAccPreCheck(t)
+
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		t.Fatalf("Cannot connect to DB (SkipNotMariaDB): %v", err)
+		return
+	}
+
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		t.Fatalf("Cannot get DB version string (SkipNotMariaDB): %v", err)
+		return
+	}
+
+	if !isMariaDB {
+		t.Skip("Skip on non-MariaDB")
+	}
+}
+
 func testAccPreCheckSkipNotMySQL8(t *testing.T) {
 	testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
 }
@@ -167,7 +204,7 @@ func testAccPreCheckSkipNotMySQLVersionMin(t *testing.T, minVersion string) {
 		return
 	}
 
-	currentVersion, err := serverVersion(db)
+	currentVersion, err := serverVersion(ctx, db)
 	if err != nil {
 		t.Fatalf("Cannot get DB version string (SkipNotMySQL8): %v", err)
 		return
@@ -176,7 +213,7 @@ func testAccPreCheckSkipNotMySQLVersionMin(t *testing.T, minVersion string) {
 	versionMin, _ := version.NewVersion(minVersion)
 	if currentVersion.LessThan(versionMin) {
 		// TiDB 7.x series advertises as 8.0 mysql so we batch its testing strategy with Mysql8
-		isTiDB, tidbVersion, mysqlCompatibilityVersion, err := serverTiDB(db)
+		isTiDB, tidbVersion, mysqlCompatibilityVersion, err := serverTiDB(ctx, db)
 		if err != nil {
 			t.Fatalf("Cannot get DB version string (SkipNotMySQL8): %v", err)
 			return
@@ -196,6 +233,16 @@ func testAccPreCheckSkipNotMySQLVersionMin(t *testing.T, minVersion string) {
 	}
 }
 
+// testAccPreCheckSkipNotCapable skips unless the server, whatever its flavor, has the capability.
+func testAccPreCheckSkipNotCapable(t *testing.T, c capability) {
+	testAccPreCheck(t)
+
+	ctx := context.Background()
+	if err := checkCapability(ctx, testAccProvider.Meta(), c); err != nil {
+		t.Skip(err.Error())
+	}
+}
+
 func testAccPreCheckSkipNotTiDB(t *testing.T) {
 	testAccPreCheck(t)
 
@@ -206,7 +253,7 @@ func testAccPreCheckSkipNotTiDB(t *testing.T) {
 		return
 	}
 
-	currentVersionString, err := serverVersionString(db)
+	currentVersionString, err := serverVersionString(ctx, db)
 	if err != nil {
 		t.Fatalf("Cannot get DB version string (SkipNotTiDB): %v", err)
 		return
@@ -228,7 +275,7 @@ func testAccPreCheckSkipNotTiDBVersionMin(t *testing.T, minVersion string) {
 		return
 	}
 
-	currentVersion, err := serverVersion(db)
+	currentVersion, err := serverVersion(ctx, db)
 	if err != nil {
 		t.Fatalf("Cannot get DB version string (SkipNotTiDBVersionMin): %v", err)
 		return
@@ -236,7 +283,7 @@ func testAccPreCheckSkipNotTiDBVersionMin(t *testing.T, minVersion string) {
 
 	versionMin, _ := version.NewVersion(minVersion)
 	if currentVersion.LessThan(versionMin) {
-		isTiDB, tidbVersion, _, err := serverTiDB(db)
+		isTiDB, tidbVersion, _, err := serverTiDB(ctx, db)
 		if err != nil {
 			t.Fatalf("Cannot get DB version string (SkipNotTiDBVersionMin): %v", err)
 			return
@@ -256,3 +303,129 @@ func testAccPreCheckSkipNotTiDBVersionMin(t *testing.T, minVersion string) {
 		t.Skip("Skip on MySQL")
 	}
 }
+
+// variablesConn answers SELECT @@GLOBAL.<name> with the value of the variable, failing for
+// unknown variables like a server would.
+type variablesConn struct {
+	recordingConn
+	variables map[string]string
+}
+
+func (c *variablesConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	value, ok := c.variables[strings.TrimPrefix(query, "SELECT @@GLOBAL.")]
+	if !ok {
+		return nil, fmt.Errorf("unknown system variable in %q", query)
+	}
+	return &valueRows{value: value}, nil
+}
+
+type valueRows struct {
+	value string
+	done  bool
+}
+
+func (r *valueRows) Columns() []string { return []string{"value"} }
+func (r *valueRows) Close() error      { return nil }
+
+func (r *valueRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+type variablesConnector struct {
+	variables map[string]string
+}
+
+func (c *variablesConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &variablesConn{variables: c.variables}, nil
+}
+func (c *variablesConnector) Driver() driver.Driver { return nil }
+
+func TestServerReadOnly(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		variables map[string]string
+		expected  string
+	}{
+		{map[string]string{"read_only": "0", "innodb_read_only": "0"}, ""},
+		{map[string]string{"read_only": "1", "innodb_read_only": "0"}, "read_only"},
+		{map[string]string{"read_only": "OFF", "innodb_read_only": "ON"}, "innodb_read_only"},
+		{map[string]string{"read_only": "0"}, ""},
+	} {
+		db := sql.OpenDB(&variablesConnector{variables: tc.variables})
+		if got := serverReadOnly(ctx, db); got != tc.expected {
+			t.Errorf("serverReadOnly with %v = %q, expected %q", tc.variables, got, tc.expected)
+		}
+		db.Close()
+	}
+}
+
+func TestOverrideDetection(t *testing.T) {
+	reported, _ := version.NewVersion("8.0.33")
+	detected := func() *OneConnection {
+		return &OneConnection{Version: reported, Rds: true, Aurora: true, Flavor: flavorMySQL, FlavorVersion: reported}
+	}
+
+	connection := detected()
+	(&MySQLConfiguration{}).overrideDetection(connection)
+	if !connection.Rds || !connection.Aurora || connection.Flavor != flavorMySQL {
+		t.Errorf("expected detection to be kept without overrides, got %+v", connection)
+	}
+
+	notRds := false
+	connection = detected()
+	(&MySQLConfiguration{ServerFlavor: flavorMariaDB, AssumeRds: &notRds}).overrideDetection(connection)
+	if connection.Rds || connection.Aurora {
+		t.Errorf("expected assume_rds = false to override RDS and Aurora, got %+v", connection)
+	}
+	if connection.Flavor != flavorMariaDB || !connection.FlavorVersion.Equal(reported) {
+		t.Errorf("expected MariaDB %s, got %s %s", reported, connection.Flavor, connection.FlavorVersion)
+	}
+}
+
+func TestProviderEndpointValidation(t *testing.T) {
+	validate := Provider().Schema["endpoint"].ValidateFunc
+	tests := map[string]int{
+		"localhost:3306":                          0,
+		"cloudsql://my-project:europe-west1:db":   0,
+		"cloudsql://example.com:my-project:us:db": 0,
+		"cloudsql://my-project/db":                1,
+		"":                                        1,
+	}
+	for endpoint, expectedErrs := range tests {
+		if _, errs := validate(endpoint, "endpoint"); len(errs) != expectedErrs {
+			t.Errorf("expected %d errors for endpoint %q, got %v", expectedErrs, endpoint, errs)
+		}
+	}
+}
+
+func TestPasswordRefreshingConnector(t *testing.T) {
+	calls := 0
+	conf := &MySQLConfiguration{
+		Config: &mysql.Config{Net: "tcp", Addr: "127.0.0.1:1", User: "aad_user", Passwd: "stale"},
+		PasswordFunc: func(ctx context.Context) (string, error) {
+			calls++
+			return "", fmt.Errorf("token expired")
+		},
+	}
+
+	connector, err := passwordRefreshingConnector(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 2; i++ {
+		if _, err := connector.Connect(context.Background()); err == nil || !strings.Contains(err.Error(), "token expired") {
+			t.Errorf("expected the error of PasswordFunc, got %v", err)
+		}
+		if calls != i {
+			t.Errorf("expected PasswordFunc to be called for each connection, got %d calls for %d connections", calls, i)
+		}
+	}
+	if conf.Config.Passwd != "stale" {
+		t.Errorf("expected the provider configuration to be left alone, got password %q", conf.Config.Passwd)
+	}
+}
@@ -0,0 +1,190 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// readCache holds the rows of reads shared between resources, e.g. SHOW GRANTS of a user
+// with many mysql_grant resources, for the rest of the operation. The connector drops all of
+// them on any statement which may write, so reads after a write see its effect.
+type readCache struct {
+	mu      sync.Mutex
+	entries map[string][][]sql.NullString
+	// generation counts the invalidations, so rows read before a concurrent write aren't cached.
+	generation uint64
+}
+
+func newReadCache() *readCache {
+	return &readCache{entries: map[string][][]sql.NullString{}}
+}
+
+// get returns the cached rows of key if any, and the generation to put them with otherwise.
+func (c *readCache) get(key string) ([][]sql.NullString, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rows, ok := c.entries[key]
+	return rows, ok, c.generation
+}
+
+func (c *readCache) put(key string, rows [][]sql.NullString, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation == c.generation {
+		c.entries[key] = rows
+	}
+}
+
+func (c *readCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if len(c.entries) > 0 {
+		log.Printf("[DEBUG] Invalidating %d cached reads", len(c.entries))
+		c.entries = map[string][][]sql.NullString{}
+	}
+}
+
+var (
+	readCachesMu sync.Mutex
+	readCaches   = map[*sql.DB]*readCache{}
+)
+
+// registerReadCache makes the cache of the connector of db available to cachedQuery.
+func registerReadCache(db *sql.DB, cache *readCache) {
+	readCachesMu.Lock()
+	defer readCachesMu.Unlock()
+	readCaches[db] = cache
+}
+
+func readCacheFor(db *sql.DB) *readCache {
+	readCachesMu.Lock()
+	defer readCachesMu.Unlock()
+	return readCaches[db]
+}
+
+// readOnlyProcedures are the procedures called by reads, which return rows without changing
+// anything.
+var readOnlyProcedures = map[string]bool{
+	"mysql.rds_show_configuration": true,
+}
+
+// isReadStatement returns whether the statement only reads, so it can't invalidate cached reads
+// and runs in a dry run.
+func isReadStatement(query string) bool {
+	keyword, rest := statementKeyword(query)
+	switch keyword {
+	case "SELECT", "SHOW", "TABLE", "VALUES":
+		return true
+	case "WITH":
+		// A common table expression may also precede an UPDATE or DELETE.
+		for _, field := range strings.Fields(strings.ToUpper(rest)) {
+			if field == "UPDATE" || field == "DELETE" {
+				return false
+			}
+		}
+		return true
+	case "CALL":
+		return readOnlyProcedures[procedureName(rest)]
+	}
+	return false
+}
+
+// mayReturnRows returns whether the statement may return a result set, which reads and
+// procedure calls do.
+func mayReturnRows(query string) bool {
+	keyword, _ := statementKeyword(query)
+	return keyword == "CALL" || isReadStatement(query)
+}
+
+// statementKeyword returns the first keyword of the statement in upper case and the rest of it,
+// skipping leading comments and parentheses. Executable comments such as /*!80000 ... */ aren't
+// skipped, as they may hold the statement itself.
+func statementKeyword(query string) (string, string) {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(query, "#"), strings.HasPrefix(query, "-- "), strings.HasPrefix(query, "--\t"), strings.HasPrefix(query, "--\n"):
+			end := strings.IndexByte(query, '\n')
+			if end < 0 {
+				return "", ""
+			}
+			query = query[end+1:]
+		case strings.HasPrefix(query, "/*") && !strings.HasPrefix(query, "/*!"):
+			end := strings.Index(query[2:], "*/")
+			if end < 0 {
+				return "", ""
+			}
+			query = query[end+4:]
+		default:
+			end := strings.IndexFunc(query, func(r rune) bool {
+				return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_')
+			})
+			if end < 0 {
+				end = len(query)
+			}
+			return strings.ToUpper(query[:end]), query[end:]
+		}
+	}
+}
+
+// procedureName returns the lower case name of the procedure called by the rest of a CALL
+// statement, without quotes.
+func procedureName(rest string) string {
+	rest = strings.TrimSpace(rest)
+	if end := strings.IndexAny(rest, "( \t\r\n;"); end >= 0 {
+		rest = rest[:end]
+	}
+	return strings.ToLower(strings.ReplaceAll(rest, "`", ""))
+}
+
+// cachedQuery runs a query returning the rows as strings, or returns the rows of an earlier
+// run of it since the last write. Errors aren't cached.
+func cachedQuery(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([][]sql.NullString, error) {
+	key := fmt.Sprintf("%s %q", query, args)
+	cache := readCacheFor(db)
+	var generation uint64
+	if cache != nil {
+		var rows [][]sql.NullString
+		var ok bool
+		if rows, ok, generation = cache.get(key); ok {
+			log.Printf("[DEBUG] Using cached rows of query: %s", query)
+			return rows, nil
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := [][]sql.NullString{}
+	for rows.Next() {
+		row := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if cache != nil {
+		cache.put(key, result, generation)
+	}
+	return result, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
@@ -16,6 +17,34 @@ import (
 const defaultCharacterSetKeyword = "CHARACTER SET "
 const defaultCollateKeyword = "COLLATE "
 const unknownDatabaseErrCode = 1049
+const unknownColumnErrCode = 1054
+const databaseEncryptionMinVersion = "8.0.16"
+const maxDatabaseNameLength = 64
+
+var PlacementPolicyTiDBMinVersion = "6.0.0"
+
+// validateDatabaseName checks the limits of database names: at most 64 characters, no trailing
+// space, and neither NUL nor characters outside the Basic Multilingual Plane.
+func validateDatabaseName(val any, key string) (warns []string, errs []error) {
+	value := val.(string)
+	if value == "" {
+		errs = append(errs, fmt.Errorf("%q must not be empty", key))
+		return
+	}
+	if n := utf8.RuneCountInString(value); n > maxDatabaseNameLength {
+		errs = append(errs, fmt.Errorf("%q must be at most %d characters long, got %d: %s", key, maxDatabaseNameLength, n, value))
+	}
+	if strings.HasSuffix(value, " ") {
+		errs = append(errs, fmt.Errorf("%q must not end with a space, got: %q", key, value))
+	}
+	for _, r := range value {
+		if r == 0 || r > 0xFFFF {
+			errs = append(errs, fmt.Errorf("%q must not contain NUL or characters outside the Basic Multilingual Plane, got %q in: %q", key, r, value))
+			break
+		}
+	}
+	return
+}
 
 func resourceDatabase() *schema.Resource {
 	return &schema.Resource{
@@ -23,26 +52,59 @@ func resourceDatabase() *schema.Resource {
 		UpdateContext: UpdateDatabase,
 		ReadContext:   ReadDatabase,
 		DeleteContext: DeleteDatabase,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportDatabase,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateDatabaseName,
 			},
 
 			"default_character_set": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "utf8mb4",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "utf8mb4",
+				DiffSuppressFunc: charsetDiffSuppressFunc,
 			},
 
 			"default_collation": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "utf8mb4_general_ci",
+				DiffSuppressFunc: collationDiffSuppressFunc,
+			},
+
+			"encryption": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+
+			"placement_policy": {
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "utf8mb4_general_ci",
+			},
+
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"force_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"skip_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
@@ -54,12 +116,31 @@ func CreateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.FromErr(err)
 	}
 
+	if databaseEncryptionConfigured(d) {
+		if err := checkDatabaseEncryptionSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use encryption: %v", err)
+		}
+	}
+
+	placementPolicy := d.Get("placement_policy").(string)
+	if placementPolicy != "" {
+		if err := checkPlacementPolicySupport(ctx, db); err != nil {
+			return diag.Errorf("cannot use placement_policy: %v", err)
+		}
+	}
+
+	objectLocks.Lock(databaseLockKey(d.Get("name").(string)))
+	defer objectLocks.Unlock(databaseLockKey(d.Get("name").(string)))
+
 	stmtSQL := databaseConfigSQL("CREATE", d)
+	if placementPolicy != "" {
+		stmtSQL += " PLACEMENT POLICY = " + quoteIdentifier(placementPolicy)
+	}
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
-		return diag.Errorf("failed running SQL to create DB: %v", err)
+		return sqlErrorDiag("failed running SQL to create DB", stmtSQL, err)
 	}
 
 	d.SetId(d.Get("name").(string))
@@ -73,40 +154,168 @@ func UpdateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 		return diag.FromErr(err)
 	}
 
-	stmtSQL := databaseConfigSQL("ALTER", d)
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if d.HasChange("encryption") {
+		if err := checkDatabaseEncryptionSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use encryption: %v", err)
+		}
+	}
 
-	_, err = db.ExecContext(ctx, stmtSQL)
-	if err != nil {
-		return diag.Errorf("failed updating DB: %v", err)
+	objectLocks.Lock(databaseLockKey(d.Id()))
+	defer objectLocks.Unlock(databaseLockKey(d.Id()))
+
+	if d.HasChanges("default_character_set", "default_collation", "encryption") {
+		stmtSQL := databaseConfigSQL("ALTER", d)
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return sqlErrorDiag("failed updating DB", stmtSQL, err)
+		}
+	}
+
+	if d.HasChange("placement_policy") {
+		if err := checkPlacementPolicySupport(ctx, db); err != nil {
+			return diag.Errorf("cannot use placement_policy: %v", err)
+		}
+
+		stmtSQL := fmt.Sprintf("ALTER DATABASE %s PLACEMENT POLICY = %s", quoteIdentifier(d.Id()), placementPolicySQL(d.Get("placement_policy").(string)))
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return sqlErrorDiag("failed updating placement policy of DB", stmtSQL, err)
+		}
 	}
 
 	return ReadDatabase(ctx, d, meta)
 }
 
+// placementPolicySQL returns the policy of ALTER DATABASE, or DEFAULT to clear it.
+func placementPolicySQL(policy string) string {
+	if policy == "" {
+		return "DEFAULT"
+	}
+	return quoteIdentifier(policy)
+}
+
 func ReadDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	name := d.Id()
+	defaultCharset, defaultCollation, err := readDatabaseSchemata(ctx, db, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		log.Printf("[WARN] Failed reading DB %s from INFORMATION_SCHEMA.SCHEMATA, falling back to SHOW CREATE DATABASE: %v", name, err)
+
+		defaultCharset, defaultCollation, err = readDatabaseShowCreate(ctx, db, name)
+		if mysqlErrorNumber(err) == unknownDatabaseErrCode {
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	catalog, err := readCollationCatalog(ctx, db)
+	if err != nil {
+		log.Printf("[WARN] Failed reading INFORMATION_SCHEMA.COLLATIONS, comparing charsets and collations by name only: %v", err)
+	}
+	defaultCharset, defaultCollation = catalog.reconcile(
+		d.Get("default_character_set").(string), d.Get("default_collation").(string), defaultCharset, defaultCollation)
+
+	d.Set("name", name)
+	d.Set("default_character_set", defaultCharset)
+	d.Set("default_collation", defaultCollation)
+
+	encryption, err := readDatabaseEncryption(ctx, db, name)
+	if err != nil {
+		return diag.Errorf("failed reading encryption of DB %s: %v", name, err)
+	}
+	d.Set("encryption", encryption)
+
+	placementPolicy, err := readDatabasePlacementPolicy(ctx, db, name)
+	if err != nil {
+		return diag.Errorf("failed reading placement policy of DB %s: %v", name, err)
+	}
+	d.Set("placement_policy", placementPolicy)
+
+	return nil
+}
+
+// readDatabasePlacementPolicy returns the TiDB placement policy of the database, which is
+// empty on other servers.
+func readDatabasePlacementPolicy(ctx context.Context, db *sql.DB, name string) (string, error) {
+	stmtSQL := "SELECT TIDB_PLACEMENT_POLICY_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var placementPolicy sql.NullString
+	err := db.QueryRowContext(ctx, stmtSQL, name).Scan(&placementPolicy)
+	if err != nil {
+		if mysqlErrorNumber(err) == unknownColumnErrCode || errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return placementPolicy.String, nil
+}
+
+func checkPlacementPolicySupport(ctx context.Context, db *sql.DB) error {
+	return checkTiDBFeatureSupport(ctx, db, "placement policies", PlacementPolicyTiDBMinVersion)
+}
+
+// readDatabaseSchemata returns the default character set and collation of the database,
+// or sql.ErrNoRows if it doesn't exist.
+func readDatabaseSchemata(ctx context.Context, db *sql.DB, name string) (string, string, error) {
+	// A snapshot of all databases is shared by the databases read before the next write.
+	snapshot, err := cachedQuery(ctx, db, "SELECT SCHEMA_NAME, DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM INFORMATION_SCHEMA.SCHEMATA")
+	if err != nil {
+		return "", "", err
+	}
+	for _, row := range snapshot {
+		if len(row) == 3 && row[0].String == name {
+			return row[1].String, row[2].String, nil
+		}
+	}
+
+	// The server may compare names case-insensitively, so a miss is checked with it.
+	stmtSQL := "SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var defaultCharset, defaultCollation string
+	err = db.QueryRowContext(ctx, stmtSQL, name).Scan(&defaultCharset, &defaultCollation)
+	if err != nil {
+		return "", "", err
+	}
+
+	return defaultCharset, defaultCollation, nil
+}
+
+// readDatabaseShowCreate is the fallback for servers where INFORMATION_SCHEMA.SCHEMATA
+// can't be read.
+func readDatabaseShowCreate(ctx context.Context, db *sql.DB, name string) (string, string, error) {
 	// This is kinda flimsy-feeling, since it depends on the formatting
 	// of the SHOW CREATE DATABASE output... but this data doesn't seem
 	// to be available any other way, so hopefully MySQL keeps this
 	// compatible in future releases.
 
-	name := d.Id()
 	stmtSQL := "SHOW CREATE DATABASE " + quoteIdentifier(name)
 
 	log.Println("[DEBUG] Executing query:", stmtSQL)
 	var createSQL, _database string
-	err = db.QueryRowContext(ctx, stmtSQL).Scan(&_database, &createSQL)
+	err := db.QueryRowContext(ctx, stmtSQL).Scan(&_database, &createSQL)
 	if err != nil {
 		if mysqlErrorNumber(err) == unknownDatabaseErrCode {
-			d.SetId("")
-			return nil
+			return "", "", err
 		}
-		return diag.Errorf("Error during show create database: %s", err)
+		return "", "", fmt.Errorf("Error during show create database: %w", err)
 	}
 
 	defaultCharset := extractIdentAfter(createSQL, defaultCharacterSetKeyword)
@@ -131,43 +340,179 @@ func ReadDatabase(ctx context.Context, d *schema.ResourceData, meta interface{})
 		*/
 		var empty interface{}
 
-		res := db.QueryRow(stmtSQL, defaultCharset).Scan(&defaultCollation, &empty)
+		res := db.QueryRowContext(ctx, stmtSQL, defaultCharset).Scan(&defaultCollation, &empty)
 
 		if res != nil {
 			if errors.Is(res, sql.ErrNoRows) {
-				return diag.Errorf("charset %s has no default collation", defaultCharset)
+				return "", "", fmt.Errorf("charset %s has no default collation", defaultCharset)
 			}
 
-			return diag.Errorf("error getting default charset: %s, %s", res, defaultCharset)
+			return "", "", fmt.Errorf("error getting default charset: %s, %s", res, defaultCharset)
 		}
 	}
 
-	d.Set("name", name)
-	d.Set("default_character_set", defaultCharset)
-	d.Set("default_collation", defaultCollation)
+	return defaultCharset, defaultCollation, nil
+}
 
-	return nil
+// readDatabaseEncryption returns whether the database is encrypted by default. Servers
+// without DEFAULT ENCRYPTION support (MySQL before 8.0.16, MariaDB, TiDB) report false.
+func readDatabaseEncryption(ctx context.Context, db *sql.DB, name string) (bool, error) {
+	stmtSQL := "SELECT DEFAULT_ENCRYPTION FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var encryption string
+	err := db.QueryRowContext(ctx, stmtSQL, name).Scan(&encryption)
+	if err != nil {
+		if mysqlErrorNumber(err) == unknownColumnErrCode || errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return strings.EqualFold(encryption, "YES"), nil
+}
+
+func checkDatabaseEncryptionSupport(ctx context.Context, meta interface{}) error {
+	return checkCapability(ctx, meta, capabilityDatabaseEncryption)
+}
+
+// databaseEncryptionConfigured reports whether encryption is set in the configuration,
+// as it's computed from the server otherwise.
+func databaseEncryptionConfigured(d *schema.ResourceData) bool {
+	return !d.GetRawConfig().GetAttr("encryption").IsNull()
 }
 
 func DeleteDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("skip_destroy").(bool) {
+		log.Printf("[WARN] skip_destroy is set, removing DB %s from state without dropping it", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("database %s has deletion_protection enabled; set it to false and apply before destroying it", d.Id())
+	}
+
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	name := d.Id()
+	objectLocks.Lock(databaseLockKey(name))
+	defer objectLocks.Unlock(databaseLockKey(name))
+
+	if !d.Get("force_destroy").(bool) {
+		tables, err := countDatabaseTables(ctx, db, name)
+		if err != nil {
+			return diag.Errorf("failed counting tables of DB %s: %v", name, err)
+		}
+		if tables > 0 {
+			return diag.Errorf("database %s still contains %d tables; set force_destroy to true and apply to destroy it with its data", name, tables)
+		}
+	}
+
 	stmtSQL := "DROP DATABASE " + quoteIdentifier(name)
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
-		return diag.Errorf("failed deleting DB: %v", err)
+		return sqlErrorDiag("failed deleting DB", stmtSQL, err)
 	}
 
 	d.SetId("")
 	return nil
 }
 
+func countDatabaseTables(ctx context.Context, db *sql.DB, name string) (int, error) {
+	stmtSQL := "SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var tables int
+	if err := db.QueryRowContext(ctx, stmtSQL, name).Scan(&tables); err != nil {
+		return 0, err
+	}
+	return tables, nil
+}
+
+// normalizeCharsetName lowercases a character set and resolves utf8, which MySQL 8.0.30 and
+// later report as utf8mb3.
+func normalizeCharsetName(name string) string {
+	name = strings.ToLower(name)
+	if name == "utf8" {
+		return "utf8mb3"
+	}
+	return name
+}
+
+// normalizeCollationName is normalizeCharsetName for collations, e.g. utf8_general_ci.
+func normalizeCollationName(name string) string {
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, "utf8_") {
+		return "utf8mb3_" + strings.TrimPrefix(name, "utf8_")
+	}
+	return name
+}
+
+func charsetDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeCharsetName(old) == normalizeCharsetName(new)
+}
+
+func collationDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeCollationName(old) == normalizeCollationName(new)
+}
+
+// collationCatalog is the catalog of collations of the server, by normalized name.
+type collationCatalog struct {
+	// charsets are the character sets of the collations.
+	charsets map[string]string
+	// defaults are the default collations of the character sets.
+	defaults map[string]string
+}
+
+func readCollationCatalog(ctx context.Context, db *sql.DB) (collationCatalog, error) {
+	catalog := collationCatalog{charsets: map[string]string{}, defaults: map[string]string{}}
+	rows, err := cachedQuery(ctx, db, "SELECT COLLATION_NAME, CHARACTER_SET_NAME, IS_DEFAULT FROM INFORMATION_SCHEMA.COLLATIONS")
+	if err != nil {
+		return catalog, err
+	}
+	for _, row := range rows {
+		if len(row) != 3 {
+			continue
+		}
+		collation, charset := normalizeCollationName(row[0].String), normalizeCharsetName(row[1].String)
+		catalog.charsets[collation] = charset
+		if strings.EqualFold(row[2].String, "Yes") {
+			catalog.defaults[charset] = collation
+		}
+	}
+	return catalog, nil
+}
+
+// reconcile returns the character set and collation to store for those read from the server,
+// keeping the ones of the state where the server means the same: the same names but for case
+// and the utf8 alias, an empty character set with a collation of the one read, and an empty
+// collation where the server uses the default collation of the character set.
+func (c collationCatalog) reconcile(stateCharset, stateCollation, charset, collation string) (string, string) {
+	resultCharset, resultCollation := charset, collation
+
+	switch {
+	case normalizeCharsetName(stateCharset) == normalizeCharsetName(charset):
+		resultCharset = stateCharset
+	case stateCharset == "" && stateCollation != "" && c.charsets[normalizeCollationName(stateCollation)] == normalizeCharsetName(charset):
+		resultCharset = ""
+	}
+
+	switch {
+	case normalizeCollationName(stateCollation) == normalizeCollationName(collation):
+		resultCollation = stateCollation
+	case stateCollation == "" && stateCharset != "" && c.defaults[normalizeCharsetName(stateCharset)] == normalizeCollationName(collation):
+		resultCollation = ""
+	}
+
+	return resultCharset, resultCollation
+}
+
 func databaseConfigSQL(verb string, d *schema.ResourceData) string {
 	name := d.Get("name").(string)
 	defaultCharset := d.Get("default_character_set").(string)
@@ -175,6 +520,7 @@ func databaseConfigSQL(verb string, d *schema.ResourceData) string {
 
 	var defaultCharsetClause string
 	var defaultCollationClause string
+	var encryptionClause string
 
 	if defaultCharset != "" {
 		defaultCharsetClause = defaultCharacterSetKeyword + quoteIdentifier(defaultCharset)
@@ -182,13 +528,21 @@ func databaseConfigSQL(verb string, d *schema.ResourceData) string {
 	if defaultCollation != "" {
 		defaultCollationClause = defaultCollateKeyword + quoteIdentifier(defaultCollation)
 	}
+	if databaseEncryptionConfigured(d) {
+		if d.Get("encryption").(bool) {
+			encryptionClause = "DEFAULT ENCRYPTION 'Y'"
+		} else {
+			encryptionClause = "DEFAULT ENCRYPTION 'N'"
+		}
+	}
 
 	return fmt.Sprintf(
-		"%s DATABASE %s %s %s",
+		"%s DATABASE %s %s %s %s",
 		verb,
 		quoteIdentifier(name),
 		defaultCharsetClause,
 		defaultCollationClause,
+		encryptionClause,
 	)
 }
 
@@ -197,14 +551,19 @@ func extractIdentAfter(sql string, keyword string) string {
 	if charsetIndex != -1 {
 		charsetIndex += len(keyword)
 		remain := sql[charsetIndex:]
-		spaceIndex := strings.IndexRune(remain, ' ')
-		return remain[:spaceIndex]
+		if spaceIndex := strings.IndexRune(remain, ' '); spaceIndex >= 0 {
+			remain = remain[:spaceIndex]
+		}
+		return unquoteName(remain)
 	}
 
 	return ""
 }
 
 func ImportDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("deletion_protection", false)
+	d.Set("force_destroy", false)
+	d.Set("skip_destroy", false)
 	err := ReadDatabase(ctx, d, meta)
 	if err != nil {
 		return nil, fmt.Errorf("error while importing: %v", err)
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -34,6 +35,190 @@ func TestAccDatabase(t *testing.T) {
 	})
 }
 
+func TestAccDatabase_deletionProtection(t *testing.T) {
+	dbName := "terraform_acceptance_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfigDeletionProtection(dbName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckBasic("mysql_database.test", dbName),
+					resource.TestCheckResourceAttr("mysql_database.test", "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testAccDatabaseConfigDeletionProtection(dbName, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("deletion_protection enabled"),
+			},
+			{
+				Config: testAccDatabaseConfigDeletionProtection(dbName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckBasic("mysql_database.test", dbName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatabase_encryption(t *testing.T) {
+	dbName := "terraform_acceptance_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, databaseEncryptionMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				// Encrypting requires a keyring on the server, so only the unencrypted
+				// default is exercised here.
+				Config: testAccDatabaseConfigEncryption(dbName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckBasic("mysql_database.test", dbName),
+					resource.TestCheckResourceAttr("mysql_database.test", "encryption", "false"),
+				),
+			},
+			{
+				ResourceName:            "mysql_database.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection"},
+			},
+		},
+	})
+}
+
+func TestAccDatabase_forceDestroy(t *testing.T) {
+	dbName := "terraform_acceptance_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfigForceDestroy(dbName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckBasic("mysql_database.test", dbName),
+					resource.TestCheckResourceAttr("mysql_database.test", "force_destroy", "false"),
+				),
+			},
+			{
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE `%s`.t (id INT PRIMARY KEY)", dbName)); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccDatabaseConfigForceDestroy(dbName, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("still contains 1 tables"),
+			},
+			{
+				Config: testAccDatabaseConfigForceDestroy(dbName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_database.test", "force_destroy", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatabase_placementPolicy(t *testing.T) {
+	dbName := "terraform_acceptance_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, PlacementPolicyTiDBMinVersion)
+
+			ctx := context.Background()
+			db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, policy := range []string{"tf_test_policy", "tf_test_policy2"} {
+				if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE PLACEMENT POLICY IF NOT EXISTS %s FOLLOWERS=1", policy)); err != nil {
+					t.Fatal(err)
+				}
+			}
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfigPlacementPolicy(dbName, "tf_test_policy"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckBasic("mysql_database.test", dbName),
+					resource.TestCheckResourceAttr("mysql_database.test", "placement_policy", "tf_test_policy"),
+				),
+			},
+			{
+				Config: testAccDatabaseConfigPlacementPolicy(dbName, "tf_test_policy2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_database.test", "placement_policy", "tf_test_policy2"),
+				),
+			},
+			{
+				Config: testAccDatabaseConfigPlacementPolicy(dbName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_database.test", "placement_policy", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestPlacementPolicySQL(t *testing.T) {
+	if got := placementPolicySQL("tf_test_policy"); got != "`tf_test_policy`" {
+		t.Errorf("unexpected policy %s", got)
+	}
+	if got := placementPolicySQL(""); got != "DEFAULT" {
+		t.Errorf("expected the DEFAULT keyword, got %s", got)
+	}
+}
+
+func TestAccDatabase_skipDestroy(t *testing.T) {
+	dbName := "terraform_acceptance_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			ctx := context.Background()
+			db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+			if err != nil {
+				return err
+			}
+
+			var _name, createSQL string
+			if err := db.QueryRow(fmt.Sprintf("SHOW CREATE DATABASE `%s`", dbName)).Scan(&_name, &createSQL); err != nil {
+				return fmt.Errorf("database %s should survive destroy: %v", dbName, err)
+			}
+
+			_, err = db.Exec(fmt.Sprintf("DROP DATABASE `%s`", dbName))
+			return err
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfigSkipDestroy(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDatabaseCheckBasic("mysql_database.test", dbName),
+					resource.TestCheckResourceAttr("mysql_database.test", "skip_destroy", "true"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDatabase_collationChange(t *testing.T) {
 	dbName := "terraform_acceptance_test"
 
@@ -153,6 +338,56 @@ func testAccDatabaseConfigBasic(name string) string {
 	return testAccDatabaseConfigFull(name, "utf8mb4", "utf8mb4_bin")
 }
 
+func testAccDatabaseConfigDeletionProtection(name string, deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+    name = "%s"
+    default_character_set = "utf8mb4"
+    default_collation = "utf8mb4_bin"
+    deletion_protection = %t
+}`, name, deletionProtection)
+}
+
+func testAccDatabaseConfigEncryption(name string, encryption bool) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+    name = "%s"
+    default_character_set = "utf8mb4"
+    default_collation = "utf8mb4_bin"
+    encryption = %t
+}`, name, encryption)
+}
+
+func testAccDatabaseConfigForceDestroy(name string, forceDestroy bool) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+    name = "%s"
+    default_character_set = "utf8mb4"
+    default_collation = "utf8mb4_bin"
+    force_destroy = %t
+}`, name, forceDestroy)
+}
+
+func testAccDatabaseConfigPlacementPolicy(name string, placementPolicy string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+    name = "%s"
+    default_character_set = "utf8mb4"
+    default_collation = "utf8mb4_bin"
+    placement_policy = "%s"
+}`, name, placementPolicy)
+}
+
+func testAccDatabaseConfigSkipDestroy(name string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+    name = "%s"
+    default_character_set = "utf8mb4"
+    default_collation = "utf8mb4_bin"
+    skip_destroy = true
+}`, name)
+}
+
 func testAccDatabaseConfigFull(name string, charset string, collation string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
@@ -161,3 +396,58 @@ resource "mysql_database" "test" {
     default_collation = "%s"
 }`, name, charset, collation)
 }
+
+func TestCollationCatalogReconcile(t *testing.T) {
+	catalog := collationCatalog{
+		charsets: map[string]string{"utf8mb3_general_ci": "utf8mb3", "utf8mb4_0900_ai_ci": "utf8mb4", "utf8mb4_bin": "utf8mb4", "latin1_swedish_ci": "latin1"},
+		defaults: map[string]string{"utf8mb3": "utf8mb3_general_ci", "utf8mb4": "utf8mb4_0900_ai_ci", "latin1": "latin1_swedish_ci"},
+	}
+	tests := []struct {
+		stateCharset, stateCollation string
+		charset, collation           string
+		expectedCharset              string
+		expectedCollation            string
+	}{
+		// The utf8 alias and case differences.
+		{"utf8", "utf8_general_ci", "utf8mb3", "utf8mb3_general_ci", "utf8", "utf8_general_ci"},
+		{"UTF8MB4", "utf8mb4_BIN", "utf8mb4", "utf8mb4_bin", "UTF8MB4", "utf8mb4_BIN"},
+		// The default collation of the character set.
+		{"latin1", "", "latin1", "latin1_swedish_ci", "latin1", ""},
+		// The character set of the collation.
+		{"", "utf8mb4_bin", "utf8mb4", "utf8mb4_bin", "", "utf8mb4_bin"},
+		// Actual differences, and imports without state.
+		{"utf8mb4", "utf8mb4_general_ci", "utf8mb4", "utf8mb4_0900_ai_ci", "utf8mb4", "utf8mb4_0900_ai_ci"},
+		{"latin1", "", "utf8mb4", "utf8mb4_0900_ai_ci", "utf8mb4", "utf8mb4_0900_ai_ci"},
+		{"", "", "utf8mb4", "utf8mb4_0900_ai_ci", "utf8mb4", "utf8mb4_0900_ai_ci"},
+	}
+	for _, test := range tests {
+		charset, collation := catalog.reconcile(test.stateCharset, test.stateCollation, test.charset, test.collation)
+		if charset != test.expectedCharset || collation != test.expectedCollation {
+			t.Errorf("reconcile(%q, %q, %q, %q) = %q, %q, want %q, %q", test.stateCharset, test.stateCollation, test.charset, test.collation,
+				charset, collation, test.expectedCharset, test.expectedCollation)
+		}
+	}
+
+	if !charsetDiffSuppressFunc("default_character_set", "utf8mb3", "UTF8", nil) || charsetDiffSuppressFunc("default_character_set", "utf8mb3", "utf8mb4", nil) {
+		t.Errorf("expected utf8 and utf8mb3 to be the same, unlike utf8mb4")
+	}
+	if !collationDiffSuppressFunc("default_collation", "utf8mb3_general_ci", "utf8_General_ci", nil) {
+		t.Errorf("expected utf8_general_ci and utf8mb3_general_ci to be the same")
+	}
+}
+
+func TestValidateDatabaseName(t *testing.T) {
+	valid := []string{"app", "tf-test-1", "my app", "app.v2", "ümlaut", strings.Repeat("d", 64)}
+	invalid := []string{"", strings.Repeat("d", 65), "trailing ", "nul\x00byte", "emoji😀"}
+
+	for _, v := range valid {
+		if _, errs := validateDatabaseName(v, "name"); len(errs) != 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range invalid {
+		if _, errs := validateDatabaseName(v, "name"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
@@ -6,11 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceDefaultRoles() *schema.Resource {
@@ -19,72 +20,341 @@ func resourceDefaultRoles() *schema.Resource {
 		UpdateContext: UpdateDefaultRoles,
 		ReadContext:   ReadDefaultRoles,
 		DeleteContext: DeleteDefaultRoles,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportDefaultRoles,
 		},
+		CustomizeDiff: customizeDiffDefaultRoles,
 
 		Schema: map[string]*schema.Schema{
 			"user": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateUserName,
 			},
 
 			"host": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				Default:  "localhost",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "localhost",
+				ValidateFunc: validateUserHost,
+			},
+
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "list",
+				ValidateFunc: validation.StringInSlice([]string{"list", "all", "none", "all_except"}, false),
 			},
 
 			"roles": {
 				Type:     schema.TypeSet,
-				Required: true,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateRoleReference,
+				},
+				Set: schema.HashString,
+			},
+
+			"except_roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateRoleReference,
+				},
+				Set: schema.HashString,
+			},
+
+			"effective_roles": {
+				Type:     schema.TypeSet,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 				Set: schema.HashString,
 			},
+
+			"validate_granted": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
 
 func checkDefaultRolesSupport(ctx context.Context, meta interface{}) error {
-	ver, _ := version.NewVersion("8.0.0")
-	if getVersionFromMeta(ctx, meta).LessThan(ver) {
-		return errors.New("MySQL version must be at least 8.0.0")
+	return checkCapability(ctx, meta, capabilityDefaultRoles)
+}
+
+const roleNotGrantedErrCode = 3530
+
+// customizeDiffDefaultRoles validates the mode and plans effective_roles, the default roles
+// the user ends up with. For all and all_except they depend on the roles granted to the user,
+// so granting a new role shows up as a change.
+func customizeDiffDefaultRoles(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	mode := d.Get("mode").(string)
+	if mode != "list" && d.Get("roles").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("roles can only be set with mode list, use except_roles with mode all_except")
 	}
-	return nil
+	if mode != "all_except" && d.Get("except_roles").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("except_roles can only be set with mode all_except")
+	}
+
+	for _, key := range []string{"user", "host", "mode", "roles", "except_roles"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("effective_roles")
+		}
+	}
+
+	if mode == "list" && !d.Get("validate_granted").(bool) {
+		return setNewEffectiveRoles(d, setToArray(d.Get("roles")))
+	}
+	if mode == "none" {
+		return setNewEffectiveRoles(d, []string{})
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	exists, err := userExists(ctx, db, user, host)
+	if err != nil {
+		return fmt.Errorf("failed checking whether user exists: %w", err)
+	}
+	if !exists {
+		// Users which don't exist yet are created in the same apply and can't have any grants before it.
+		if mode == "list" {
+			return setNewEffectiveRoles(d, setToArray(d.Get("roles")))
+		}
+		return d.SetNewComputed("effective_roles")
+	}
+
+	if mode == "list" {
+		ungranted, err := ungrantedRoles(ctx, db, user, host, setToArray(d.Get("roles")))
+		if err != nil {
+			return err
+		}
+		if len(ungranted) > 0 {
+			return fmt.Errorf("roles %s are not granted to %s@%s and can't be its default roles; grant them first, e.g. with mysql_grant", strings.Join(ungranted, ", "), user, host)
+		}
+		return setNewEffectiveRoles(d, setToArray(d.Get("roles")))
+	}
+
+	roles, err := grantedRolesExcept(ctx, db, user, host, setToArray(d.Get("except_roles")))
+	if err != nil {
+		return err
+	}
+	return setNewEffectiveRoles(d, roles)
+}
+
+func setNewEffectiveRoles(d *schema.ResourceDiff, roles []string) error {
+	planned := schema.NewSet(schema.HashString, nil)
+	for _, role := range roles {
+		planned.Add(role)
+	}
+	if d.Id() != "" && d.Get("effective_roles").(*schema.Set).Equal(planned) {
+		return nil
+	}
+	return d.SetNew("effective_roles", roles)
+}
+
+// grantedRoles returns the roles granted to the user according to mysql.role_edges, or
+// mysql.roles_mapping on MariaDB.
+func grantedRoles(ctx context.Context, db *sql.DB, user, host string) ([]string, error) {
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	stmtSQL := "SELECT FROM_USER, FROM_HOST FROM mysql.role_edges WHERE TO_USER = ? AND TO_HOST = ?"
+	if isMariaDB {
+		// MariaDB roles have no host part.
+		stmtSQL = "SELECT Role, '' FROM mysql.roles_mapping WHERE User = ? AND Host = ?"
+	}
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, user, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading granted roles: %w", err)
+	}
+	defer rows.Close()
+
+	granted := make([]string, 0)
+	for rows.Next() {
+		var role, roleHost string
+		if err := rows.Scan(&role, &roleHost); err != nil {
+			return nil, fmt.Errorf("failed scanning granted roles: %w", err)
+		}
+		granted = append(granted, roleReference(role, roleHost))
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("failed getting rows: %w", rows.Err())
+	}
+
+	sort.Strings(granted)
+	return granted, nil
+}
+
+// grantedRolesExcept returns the roles granted to the user, leaving out the excluded ones.
+func grantedRolesExcept(ctx context.Context, db *sql.DB, user, host string, except []string) ([]string, error) {
+	granted, err := grantedRoles(ctx, db, user, host)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]bool)
+	for _, role := range except {
+		parsed := parseRoleReference(role)
+		excluded[roleReference(parsed.Name, parsed.Host)] = true
+	}
+
+	roles := make([]string, 0)
+	for _, role := range granted {
+		if !excluded[role] {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// ungrantedRoles returns the roles which aren't granted to the user.
+func ungrantedRoles(ctx context.Context, db *sql.DB, user, host string, roles []string) ([]string, error) {
+	grantedList, err := grantedRoles(ctx, db, user, host)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool)
+	for _, role := range grantedList {
+		granted[role] = true
+	}
+
+	ungranted := make([]string, 0)
+	for _, role := range roles {
+		parsed := parseRoleReference(role)
+		if !granted[roleReference(parsed.Name, parsed.Host)] {
+			ungranted = append(ungranted, role)
+		}
+	}
+	sort.Strings(ungranted)
+
+	return ungranted, nil
 }
 
 func alterUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string, roles []string) error {
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		return err
+	}
+	if isMariaDB {
+		return setMariaDBDefaultRole(ctx, db, user, host, roles)
+	}
+
 	var stmtSQL string
 
-	stmtSQL = fmt.Sprintf("ALTER USER '%s'@'%s' DEFAULT ROLE ", user, host)
+	stmtSQL = fmt.Sprintf("ALTER USER %s DEFAULT ROLE ", UserOrRole{Name: user, Host: host}.SQLString())
 
 	if len(roles) > 0 {
-		stmtSQL += fmt.Sprintf("'%s'", strings.Join(roles, "', '"))
+		stmtSQL += strings.Join(roleSQLStrings(roles), ", ")
 	} else {
 		stmtSQL += "NONE"
 	}
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
-	_, err := db.ExecContext(ctx, stmtSQL)
+	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
+		if mysqlErrorNumber(err) == roleNotGrantedErrCode {
+			return fmt.Errorf("only roles granted to the user can be default roles, grant them first, e.g. with mysql_grant: %w", err)
+		}
 		return fmt.Errorf("failed executing SQL: %w", err)
 	}
 
 	return nil
 }
 
-func getRolesFromData(d *schema.ResourceData) []string {
-	defaultRoles := d.Get("roles").(*schema.Set).List()
-	roles := make([]string, len(defaultRoles))
-	for i, role := range defaultRoles {
-		roles[i] = role.(string)
+// applyDefaultRoles sets the default roles according to the mode and records the result
+// in effective_roles.
+func applyDefaultRoles(ctx context.Context, db *sql.DB, d *schema.ResourceData) error {
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	switch d.Get("mode").(string) {
+	case "all":
+		isMariaDB, err := serverMariaDB(ctx, db)
+		if err != nil {
+			return err
+		}
+		if isMariaDB {
+			return errors.New("mode all is not supported by MariaDB, which allows only a single default role")
+		}
+
+		stmtSQL := fmt.Sprintf("ALTER USER %s DEFAULT ROLE ALL", UserOrRole{Name: user, Host: host}.SQLString())
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed executing SQL: %w", err)
+		}
+	case "all_except":
+		roles, err := grantedRolesExcept(ctx, db, user, host, setToArray(d.Get("except_roles")))
+		if err != nil {
+			return err
+		}
+		if err := alterUserDefaultRoles(ctx, db, user, host, roles); err != nil {
+			return err
+		}
+	case "none":
+		if err := alterUserDefaultRoles(ctx, db, user, host, []string{}); err != nil {
+			return err
+		}
+	default:
+		if err := alterUserDefaultRoles(ctx, db, user, host, getRolesFromData(d)); err != nil {
+			return err
+		}
 	}
 
-	return roles
+	defaultRoles, err := readUserDefaultRoles(ctx, db, user, host)
+	if err != nil {
+		return err
+	}
+	d.Set("effective_roles", defaultRoles)
+
+	return nil
+}
+
+// setMariaDBDefaultRole uses SET DEFAULT ROLE ... FOR, as MariaDB has no ALTER USER ... DEFAULT ROLE
+// and a user can only have a single default role.
+func setMariaDBDefaultRole(ctx context.Context, db *sql.DB, user, host string, roles []string) error {
+	if len(roles) > 1 {
+		return fmt.Errorf("MariaDB supports only a single default role, got %s", strings.Join(roles, ", "))
+	}
+
+	role := "NONE"
+	if len(roles) == 1 {
+		parsed := parseRoleReference(roles[0])
+		if parsed.Host != "" {
+			return fmt.Errorf("MariaDB roles have no host part, got %s", roles[0])
+		}
+		role = parsed.SQLString()
+	}
+
+	stmtSQL := fmt.Sprintf("SET DEFAULT ROLE %s FOR %s", role, UserOrRole{Name: user, Host: host}.SQLString())
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return fmt.Errorf("failed executing SQL: %w", err)
+	}
+
+	return nil
+}
+
+func getRolesFromData(d *schema.ResourceData) []string {
+	return setToArray(d.Get("roles"))
 }
 
 func CreateDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -98,9 +368,10 @@ func CreateDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interf
 
 	user := d.Get("user").(string)
 	host := d.Get("host").(string)
-	roles := getRolesFromData(d)
+	objectLocks.Lock(accountLockKey(UserOrRole{Name: user, Host: host}))
+	defer objectLocks.Unlock(accountLockKey(UserOrRole{Name: user, Host: host}))
 
-	if err := alterUserDefaultRoles(ctx, db, user, host, roles); err != nil {
+	if err := applyDefaultRoles(ctx, db, d); err != nil {
 		return diag.Errorf("failed to create user default roles: %v", err)
 	}
 
@@ -118,12 +389,12 @@ func UpdateDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.Errorf("cannot use default roles: %v", err)
 	}
 
-	if d.HasChange("roles") {
-		user := d.Get("user").(string)
-		host := d.Get("host").(string)
-		roles := getRolesFromData(d)
+	account := UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}
+	objectLocks.Lock(accountLockKey(account))
+	defer objectLocks.Unlock(accountLockKey(account))
 
-		if err := alterUserDefaultRoles(ctx, db, user, host, roles); err != nil {
+	if d.HasChanges("mode", "roles", "except_roles", "effective_roles") {
+		if err := applyDefaultRoles(ctx, db, d); err != nil {
 			return diag.Errorf("failed to update user default roles: %v", err)
 		}
 	}
@@ -140,33 +411,65 @@ func ReadDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interfac
 		return diag.Errorf("cannot use default roles: %v", err)
 	}
 
-	stmtSQL := "SELECT default_role_user FROM mysql.default_roles WHERE user = ? AND host = ?"
+	exists, err := userExists(ctx, db, d.Get("user").(string), d.Get("host").(string))
+	if err != nil {
+		return diag.Errorf("failed reading user %s: %v", d.Id(), err)
+	}
+	if !exists {
+		log.Printf("[WARN] User (%s) not found; removing default roles from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	defaultRoles, err := readUserDefaultRoles(ctx, db, d.Get("user").(string), d.Get("host").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("mode").(string) == "list" {
+		d.Set("roles", defaultRoles)
+	}
+	d.Set("effective_roles", defaultRoles)
+
+	return nil
+}
+
+func readUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string) ([]string, error) {
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	stmtSQL := "SELECT default_role_user, default_role_host FROM mysql.default_roles WHERE user = ? AND host = ?"
+	if isMariaDB {
+		// MariaDB keeps the single default role in mysql.user.
+		stmtSQL = "SELECT default_role, '' FROM mysql.user WHERE User = ? AND Host = ? AND default_role != ''"
+	}
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
-	rows, err := db.QueryContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string))
+	rows, err := db.QueryContext(ctx, stmtSQL, user, host)
 	if err != nil {
-		return diag.Errorf("failed to read user default roles from DB: %v", err)
+		return nil, fmt.Errorf("failed to read user default roles from DB: %w", err)
 	}
 	defer rows.Close()
 
 	var defaultRoles = make([]string, 0)
 	for rows.Next() {
-		var role string
-		err := rows.Scan(&role)
+		var role, roleHost string
+		err := rows.Scan(&role, &roleHost)
 		if err != nil {
-			return diag.Errorf("failed scanning default roles: %v", err)
+			return nil, fmt.Errorf("failed scanning default roles: %w", err)
 		}
-		defaultRoles = append(defaultRoles, role)
+		defaultRoles = append(defaultRoles, roleReference(role, roleHost))
 	}
 
 	if rows.Err() != nil {
-		return diag.Errorf("failed getting rows: %v", rows.Err())
+		return nil, fmt.Errorf("failed getting rows: %w", rows.Err())
 	}
+	sort.Strings(defaultRoles)
 
-	d.Set("roles", defaultRoles)
-
-	return nil
+	return defaultRoles, nil
 }
 
 func DeleteDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -180,6 +483,8 @@ func DeleteDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interf
 
 	user := d.Get("user").(string)
 	host := d.Get("host").(string)
+	objectLocks.Lock(accountLockKey(UserOrRole{Name: user, Host: host}))
+	defer objectLocks.Unlock(accountLockKey(UserOrRole{Name: user, Host: host}))
 
 	if err := alterUserDefaultRoles(ctx, db, user, host, []string{}); err != nil {
 		return diag.Errorf("failed to remove user default roles: %v", err)
@@ -199,6 +504,8 @@ func ImportDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interf
 
 	d.Set("user", userHost[0])
 	d.Set("host", userHost[1])
+	d.Set("validate_granted", false)
+	d.Set("mode", "list")
 
 	readDiags := ReadDefaultRoles(ctx, d, meta)
 	for _, readDiag := range readDiags {
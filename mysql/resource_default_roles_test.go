@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -29,6 +30,15 @@ func TestAccDefaultRoles_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("mysql_default_roles.test", "roles.0", "role1"),
 				),
 			},
+			{
+				// Dropping the user out of band drops its grants and default roles too, so all of
+				// them are recreated.
+				PreConfig: func() {
+					testAccSqlExec(t, "DROP USER 'jdoe'@'%'")
+				},
+				Config: testAccDefaultRolesBasic,
+				Check:  testAccDefaultRoles("mysql_default_roles.test", "role1"),
+			},
 			{
 				Config: testAccDefaultRolesMultiple,
 				Check: resource.ComposeTestCheckFunc(
@@ -63,6 +73,103 @@ func TestAccDefaultRoles_basic(t *testing.T) {
 	})
 }
 
+func TestAccDefaultRoles_validateGranted(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDefaultRolesCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDefaultRolesValidateGranted(false),
+			},
+			{
+				Config:      testAccDefaultRolesValidateGranted(true),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("roles role2 are not granted to jdoe@%"),
+			},
+		},
+	})
+}
+
+func TestAccDefaultRoles_mode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDefaultRolesCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDefaultRolesMode(`mode = "all"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDefaultRoles("mysql_default_roles.test", "role1", "role2"),
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "effective_roles.#", "2"),
+				),
+			},
+			{
+				Config: testAccDefaultRolesMode(`
+	mode         = "all_except"
+	except_roles = [mysql_role.role2.name]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDefaultRoles("mysql_default_roles.test", "role1"),
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "effective_roles.#", "1"),
+				),
+			},
+			{
+				Config: testAccDefaultRolesMode(`mode = "none"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDefaultRoles("mysql_default_roles.test"),
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "effective_roles.#", "0"),
+				),
+			},
+			{
+				Config: testAccDefaultRolesMode(`
+	mode  = "none"
+	roles = [mysql_role.role1.name]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("roles can only be set with mode list"),
+			},
+		},
+	})
+}
+
+func TestAccDefaultRoles_mariaDB(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotMariaDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDefaultRolesBasic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "roles.#", "1"),
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "effective_roles.#", "1"),
+				),
+			},
+			{
+				Config: testAccDefaultRolesMultiple,
+				// MariaDB users have a single default role.
+				ExpectError: regexp.MustCompile("MariaDB supports only a single default role"),
+			},
+			{
+				Config: testAccDefaultRolesNone,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_default_roles.test", "roles.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func testAccDefaultRoles(rn string, roles ...string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -143,6 +250,73 @@ func testAccDefaultRolesCheckDestroy(s *terraform.State) error {
 	return nil
 }
 
+func testAccDefaultRolesMode(modeConfig string) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "role1" {
+	name = "role1"
+}
+
+resource "mysql_role" "role2" {
+	name = "role2"
+}
+
+resource "mysql_user" "test" {
+	user = "jdoe"
+	host = "%%"
+}
+
+resource "mysql_grant" "test" {
+	user     = mysql_user.test.user
+	host     = mysql_user.test.host
+	database = ""
+	roles    = [mysql_role.role1.name, mysql_role.role2.name]
+}
+
+resource "mysql_default_roles" "test" {
+	user = mysql_user.test.user
+	host = mysql_user.test.host
+	%s
+
+	depends_on = [mysql_grant.test]
+}
+`, modeConfig)
+}
+
+func testAccDefaultRolesValidateGranted(withDefaultRoles bool) string {
+	config := `
+resource "mysql_role" "role1" {
+	name = "role1"
+}
+
+resource "mysql_role" "role2" {
+	name = "role2"
+}
+
+resource "mysql_user" "test" {
+	user = "jdoe"
+	host = "%"
+}
+
+resource "mysql_grant" "test" {
+	user     = mysql_user.test.user
+	host     = mysql_user.test.host
+	database = ""
+	roles    = [mysql_role.role1.name]
+}
+`
+	if withDefaultRoles {
+		config += `
+resource "mysql_default_roles" "test" {
+	user             = mysql_user.test.user
+	host             = mysql_user.test.host
+	roles            = [mysql_role.role1.name, mysql_role.role2.name]
+	validate_granted = true
+}
+`
+	}
+	return config
+}
+
 const testAccDefaultRolesBasic = `
 resource "mysql_role" "role1" {
 	name = "role1"
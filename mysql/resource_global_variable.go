@@ -9,6 +9,7 @@ import (
 	"log"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -19,6 +20,7 @@ func resourceGlobalVariable() *schema.Resource {
 		ReadContext:   ReadGlobalVariable,
 		UpdateContext: CreateOrUpdateGlobalVariable,
 		DeleteContext: DeleteGlobalVariable,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -54,20 +56,15 @@ func CreateOrUpdateGlobalVariable(ctx context.Context, d *schema.ResourceData, m
 	name := d.Get("name").(string)
 	value := d.Get("value").(string)
 
-	sqlBaseQuery := fmt.Sprintf("SET GLOBAL %s = ", quoteIdentifier(name))
-
-	// Detect number or string
-	if _, err := strconv.ParseFloat(value, 64); err == nil {
-		sqlCommand = fmt.Sprintf("%s%s", sqlBaseQuery, value)
-	} else {
-		sqlCommand = fmt.Sprintf("%s'%s'", sqlBaseQuery, value)
-	}
+	sqlCommand = setGlobalVariableSQL(name, value)
 
 	log.Printf("[DEBUG] SQL: %s", sqlCommand)
 
 	_, err = db.ExecContext(ctx, sqlCommand)
 	if err != nil {
-		return diag.Errorf("error setting value: %s", err)
+		return rdsErrorDiag(ctx, meta, "error setting value", sqlCommand, err, func(aurora bool) string {
+			return rdsGlobalVariableHint(name, aurora)
+		})
 	}
 
 	d.SetId(name)
@@ -75,22 +72,54 @@ func CreateOrUpdateGlobalVariable(ctx context.Context, d *schema.ResourceData, m
 	return ReadGlobalVariable(ctx, d, meta)
 }
 
+// rdsVariableEquivalents are the variables RDS manages with rds_set_configuration instead.
+var rdsVariableEquivalents = map[string]string{
+	"binlog_expire_logs_seconds": "binlog_retention_hours of mysql_rds_config",
+	"expire_logs_days":           "binlog_retention_hours of mysql_rds_config",
+}
+
+// rdsGlobalVariableHint explains how to set a global variable on RDS, which doesn't allow SET
+// GLOBAL.
+func rdsGlobalVariableHint(name string, aurora bool) string {
+	if equivalent, ok := rdsVariableEquivalents[strings.ToLower(name)]; ok {
+		return fmt.Sprintf("RDS manages %s with rds_set_configuration; use %s instead.", name, equivalent)
+	}
+	parameterGroup := "the DB parameter group of the instance"
+	if aurora {
+		parameterGroup = "the DB cluster parameter group, or the DB parameter group of the instance"
+	}
+	return fmt.Sprintf("RDS doesn't allow SET GLOBAL; set %s in %s instead.", name, parameterGroup)
+}
+
+// setGlobalVariableSQL returns the statement setting a global variable, passing numbers
+// unquoted.
+func setGlobalVariableSQL(name, value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return fmt.Sprintf("SET GLOBAL %s = %s", quoteIdentifier(name), value)
+	}
+	return fmt.Sprintf("SET GLOBAL %s = %s", quoteIdentifier(name), quoteLiteral(value))
+}
+
 func ReadGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	stmt, err := db.Prepare("SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME = ?")
+	stmt, err := db.PrepareContext(ctx, "SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME = ?")
 	if err != nil {
 		return diag.Errorf("error during prepare statement for global variable: %s", err)
 	}
+	defer stmt.Close()
 
 	var name, value string
-	err = stmt.QueryRow(d.Id()).Scan(&name, &value)
-
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	err = stmt.QueryRowContext(ctx, d.Id()).Scan(&name, &value)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The variable is gone, e.g. with the plugin or component that added it.
+		log.Printf("[WARN] Global variable (%s) not found; removing from state", d.Id())
 		d.SetId("")
+		return nil
+	} else if err != nil {
 		return diag.Errorf("error during show global variables: %s", err)
 	}
 
@@ -186,3 +186,19 @@ resource "mysql_global_variable" "test" {
 }
 `, varName, varValue)
 }
+
+func TestRdsGlobalVariableHint(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		aurora   bool
+		expected string
+	}{
+		{"max_connections", false, "RDS doesn't allow SET GLOBAL; set max_connections in the DB parameter group of the instance instead."},
+		{"max_connections", true, "RDS doesn't allow SET GLOBAL; set max_connections in the DB cluster parameter group, or the DB parameter group of the instance instead."},
+		{"binlog_expire_logs_seconds", false, "RDS manages binlog_expire_logs_seconds with rds_set_configuration; use binlog_retention_hours of mysql_rds_config instead."},
+	} {
+		if got := rdsGlobalVariableHint(tc.name, tc.aurora); got != tc.expected {
+			t.Errorf("rdsGlobalVariableHint(%q, %t) = %q, expected %q", tc.name, tc.aurora, got, tc.expected)
+		}
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"strings"
 	"unicode"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -25,8 +24,6 @@ var (
 	kTable     ObjectT = "TABLE"
 )
 
-var grantCreateMutex = NewKeyedMutex()
-
 type MySQLGrant interface {
 	GetId() string
 	SQLGrantStatement() string
@@ -104,9 +101,9 @@ func (u UserOrRole) IDString() string {
 
 func (u UserOrRole) SQLString() string {
 	if u.Host == "" {
-		return fmt.Sprintf("'%s'", u.Name)
+		return quoteLiteral(u.Name)
 	}
-	return fmt.Sprintf("'%s'@'%s'", u.Name, u.Host)
+	return fmt.Sprintf("%s@%s", quoteLiteral(u.Name), quoteLiteral(u.Host))
 }
 
 func (u UserOrRole) Equals(other UserOrRole) bool {
@@ -144,7 +141,7 @@ func (t *TablePrivilegeGrant) GetDatabase() string {
 	if t.Database == "*" {
 		return "*"
 	} else {
-		return fmt.Sprintf("`%s`", t.Database)
+		return quoteIdentifier(t.Database)
 	}
 }
 
@@ -152,7 +149,7 @@ func (t *TablePrivilegeGrant) GetTable() string {
 	if t.Table == "*" || t.Table == "" {
 		return "*"
 	} else {
-		return fmt.Sprintf("`%s`", t.Table)
+		return quoteIdentifier(t.Table)
 	}
 }
 
@@ -166,9 +163,7 @@ func (t *TablePrivilegeGrant) AppendPrivileges(privs []string) {
 
 func (t *TablePrivilegeGrant) SQLGrantStatement() string {
 	stmtSql := fmt.Sprintf("GRANT %s ON %s.%s TO %s", strings.Join(t.Privileges, ", "), t.GetDatabase(), t.GetTable(), t.UserOrRole.SQLString())
-	if t.TLSOption != "" && strings.ToLower(t.TLSOption) != "none" {
-		stmtSql += fmt.Sprintf(" REQUIRE %s", t.TLSOption)
-	}
+	stmtSql += grantRequireSQL(t.TLSOption)
 	if t.Grant {
 		stmtSql += " WITH GRANT OPTION"
 	}
@@ -227,14 +222,15 @@ func (t *ProcedurePrivilegeGrant) GrantOption() bool {
 }
 
 func (t *ProcedurePrivilegeGrant) GetDatabase() string {
-	if strings.Compare(t.Database, "*") != 0 && !strings.HasSuffix(t.Database, "`") {
-		return fmt.Sprintf("`%s`", t.Database)
+	// The database of a procedure may be configured quoted already.
+	if t.Database != "*" && !isQuotedIdentifier(t.Database) {
+		return quoteIdentifier(t.Database)
 	}
 	return t.Database
 }
 
 func (t *ProcedurePrivilegeGrant) GetCallableName() string {
-	return fmt.Sprintf("`%s`", t.CallableName)
+	return quoteIdentifier(t.CallableName)
 }
 
 func (t *ProcedurePrivilegeGrant) GetPrivileges() []string {
@@ -247,9 +243,7 @@ func (t *ProcedurePrivilegeGrant) AppendPrivileges(privs []string) {
 
 func (t *ProcedurePrivilegeGrant) SQLGrantStatement() string {
 	stmtSql := fmt.Sprintf("GRANT %s ON %s %s.%s TO %s", strings.Join(t.Privileges, ", "), t.ObjectT, t.GetDatabase(), t.GetCallableName(), t.UserOrRole.SQLString())
-	if t.TLSOption != "" && strings.ToLower(t.TLSOption) != "none" {
-		stmtSql += fmt.Sprintf(" REQUIRE %s", t.TLSOption)
-	}
+	stmtSql += grantRequireSQL(t.TLSOption)
 	if t.Grant {
 		stmtSql += " WITH GRANT OPTION"
 	}
@@ -278,6 +272,8 @@ type RoleGrant struct {
 	Grant      bool
 	UserOrRole UserOrRole
 	TLSOption  string
+	// MariaDB grants roles without REQUIRE, as MariaDB doesn't accept it there.
+	MariaDB bool
 }
 
 func (t *RoleGrant) GetId() string {
@@ -293,9 +289,9 @@ func (t *RoleGrant) GrantOption() bool {
 }
 
 func (t *RoleGrant) SQLGrantStatement() string {
-	stmtSql := fmt.Sprintf("GRANT '%s' TO %s", strings.Join(t.Roles, "', '"), t.UserOrRole.SQLString())
-	if t.TLSOption != "" && strings.ToLower(t.TLSOption) != "none" {
-		stmtSql += fmt.Sprintf(" REQUIRE %s", t.TLSOption)
+	stmtSql := fmt.Sprintf("GRANT %s TO %s", strings.Join(roleSQLStrings(t.Roles), ", "), t.UserOrRole.SQLString())
+	if !t.MariaDB {
+		stmtSql += grantRequireSQL(t.TLSOption)
 	}
 	if t.Grant {
 		stmtSql += " WITH ADMIN OPTION"
@@ -304,7 +300,7 @@ func (t *RoleGrant) SQLGrantStatement() string {
 }
 
 func (t *RoleGrant) SQLRevokeStatement() string {
-	return fmt.Sprintf("REVOKE '%s' FROM %s", strings.Join(t.Roles, "', '"), t.UserOrRole.SQLString())
+	return fmt.Sprintf("REVOKE %s FROM %s", strings.Join(roleSQLStrings(t.Roles), ", "), t.UserOrRole.SQLString())
 }
 
 func (t *RoleGrant) GetRoles() []string {
@@ -313,6 +309,27 @@ func (t *RoleGrant) GetRoles() []string {
 
 func (t *RoleGrant) AppendRoles(roles []string) {
 	t.Roles = append(t.Roles, roles...)
+	sort.Strings(t.Roles)
+}
+
+// grantsForFlavor splits a role grant into one grant per role on MariaDB, which only accepts
+// a single role in GRANT and REVOKE. Other grants are returned as they are.
+func grantsForFlavor(grant MySQLGrant, flavor serverFlavor) []MySQLGrant {
+	roleGrant, ok := grant.(*RoleGrant)
+	if !ok || flavor != flavorMariaDB {
+		return []MySQLGrant{grant}
+	}
+	grants := make([]MySQLGrant, 0, len(roleGrant.Roles))
+	for _, role := range roleGrant.Roles {
+		grants = append(grants, &RoleGrant{
+			Roles:      []string{role},
+			Grant:      roleGrant.Grant,
+			UserOrRole: roleGrant.UserOrRole,
+			TLSOption:  roleGrant.TLSOption,
+			MariaDB:    true,
+		})
+	}
+	return grants
 }
 
 func resourceGrant() *schema.Resource {
@@ -321,9 +338,18 @@ func resourceGrant() *schema.Resource {
 		UpdateContext: UpdateGrant,
 		ReadContext:   ReadGrant,
 		DeleteContext: DeleteGrant,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportGrant,
 		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceGrantV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceGrantStateUpgradeV0,
+			},
+		},
 
 		Schema: map[string]*schema.Schema{
 			"user": {
@@ -331,6 +357,7 @@ func resourceGrant() *schema.Resource {
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"role"},
+				ValidateFunc:  validateUserName,
 			},
 
 			"role": {
@@ -338,6 +365,7 @@ func resourceGrant() *schema.Resource {
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"user", "host"},
+				ValidateFunc:  validateRoleReference,
 			},
 
 			"host": {
@@ -346,6 +374,7 @@ func resourceGrant() *schema.Resource {
 				ForceNew:      true,
 				Default:       "localhost",
 				ConflictsWith: []string{"role"},
+				ValidateFunc:  validateUserHost,
 			},
 
 			"database": {
@@ -373,7 +402,7 @@ func resourceGrant() *schema.Resource {
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"privileges"},
-				Elem:          &schema.Schema{Type: schema.TypeString},
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validateRoleReference},
 				Set:           schema.HashString,
 			},
 
@@ -385,22 +414,70 @@ func resourceGrant() *schema.Resource {
 			},
 
 			"tls_option": {
-				Type:       schema.TypeString,
-				Optional:   true,
-				ForceNew:   true,
-				Deprecated: "Please use tls_option in mysql_user.",
-				Default:    "NONE",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Deprecated:   "Please use tls_option in mysql_user.",
+				Default:      "NONE",
+				ValidateFunc: validateTLSOption,
 			},
 		},
 	}
 }
 
-func supportsRoles(ctx context.Context, meta interface{}) (bool, error) {
-	currentVersion := getVersionFromMeta(ctx, meta)
+// resourceGrantV0 is the grant schema of older versions and forks, which used other ID formats.
+func resourceGrantV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user":       {Type: schema.TypeString, Optional: true},
+			"role":       {Type: schema.TypeString, Optional: true},
+			"host":       {Type: schema.TypeString, Optional: true},
+			"database":   {Type: schema.TypeString, Required: true},
+			"table":      {Type: schema.TypeString, Optional: true},
+			"privileges": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"roles":      {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"grant":      {Type: schema.TypeBool, Optional: true},
+			"tls_option": {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// resourceGrantStateUpgradeV0 replaces the legacy ID formats of older versions and forks, such as
+// user@host:database, with the current one computed from the attributes.
+func resourceGrantStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if table, _ := rawState["table"].(string); table == "" {
+		rawState["table"] = "*"
+	}
+	if tlsOption, _ := rawState["tls_option"].(string); tlsOption == "" {
+		rawState["tls_option"] = "NONE"
+	}
+
+	r := resourceGrantV0()
+	d := r.Data(nil)
+	for k := range r.Schema {
+		if v, ok := rawState[k]; ok && v != nil {
+			if err := d.Set(k, v); err != nil {
+				return nil, fmt.Errorf("failed upgrading %s of grant %v: %v", k, rawState["id"], err)
+			}
+		}
+	}
+
+	grant, diags := parseResourceFromData(d)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed upgrading grant %v: %s", rawState["id"], diags[0].Summary)
+	}
 
-	requiredVersion, _ := version.NewVersion("8.0.0")
-	hasRoles := currentVersion.GreaterThan(requiredVersion)
-	return hasRoles, nil
+	log.Printf("[DEBUG] Upgrading grant ID %v to %s", rawState["id"], grant.GetId())
+	rawState["id"] = grant.GetId()
+	return rawState, nil
+}
+
+func supportsRoles(ctx context.Context, meta interface{}) (bool, error) {
+	flavor, flavorVersion, err := getFlavorFromMeta(ctx, meta)
+	if err != nil {
+		return false, err
+	}
+	return flavorSupports(flavor, flavorVersion, capabilityRoles) == nil, nil
 }
 
 var kReProcedureWithoutDatabase = regexp.MustCompile(`(?i)^(function|procedure) ([^.]*)$`)
@@ -422,9 +499,7 @@ func parseResourceFromData(d *schema.ResourceData) (MySQLGrant, diag.Diagnostics
 			Host: hostAttr.(string),
 		}
 	} else if roleOk && roleAttr.(string) != "" {
-		userOrRole = UserOrRole{
-			Name: roleAttr.(string),
-		}
+		userOrRole = parseRoleReference(roleAttr.(string))
 	} else {
 		return nil, diag.Errorf("One of user/host or role is required")
 	}
@@ -512,11 +587,11 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 	// Acquire a lock for the user
 	// This is necessary so that the conflicting grant check is correct with respect to other grants being created
-	grantCreateMutex.Lock(grant.GetUserOrRole().IDString())
-	defer grantCreateMutex.Unlock(grant.GetUserOrRole().IDString())
+	objectLocks.Lock(accountLockKey(grant.GetUserOrRole()))
+	defer objectLocks.Unlock(accountLockKey(grant.GetUserOrRole()))
 
 	// Check to see if there are existing roles that might be clobbered by this grant
-	conflictingGrant, err := getMatchingGrant(ctx, db, grant)
+	conflictingGrant, err := findGrant(ctx, db, meta, grant)
 	if err != nil {
 		return diag.Errorf("failed showing grants: %v", err)
 	}
@@ -524,18 +599,29 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.Errorf("user/role %#v already has grant %v - ", grant.GetUserOrRole(), conflictingGrant)
 	}
 
-	stmtSQL := grant.SQLGrantStatement()
-
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
-	_, err = db.ExecContext(ctx, stmtSQL)
+	flavor, _, err := getFlavorFromMeta(ctx, meta)
 	if err != nil {
-		return diag.Errorf("Error running SQL (%v): %v", stmtSQL, err)
+		return diag.FromErr(err)
+	}
+	for _, flavorGrant := range grantsForFlavor(grant, flavor) {
+		stmtSQL := flavorGrant.SQLGrantStatement()
+
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return rdsErrorDiag(ctx, meta, "failed granting privileges", stmtSQL, err, rdsGrantHint)
+		}
 	}
 
 	d.SetId(grant.GetId())
 	return ReadGrant(ctx, d, meta)
 }
 
+// rdsGrantHint explains which privileges can be granted on RDS.
+func rdsGrantHint(aurora bool) string {
+	return "The master user of RDS lacks SUPER, FILE and SHUTDOWN, so it can't grant them; on MySQL 8, grant the rds_superuser_role role instead of SUPER."
+}
+
 func ReadGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -547,7 +633,7 @@ func ReadGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diagErr
 	}
 
-	grantFromDb, err := getMatchingGrant(ctx, db, grantFromTf)
+	grantFromDb, err := findGrant(ctx, db, meta, grantFromTf)
 	if err != nil {
 		return diag.Errorf("ReadGrant - getting all grants failed: %v", err)
 	}
@@ -578,9 +664,12 @@ func UpdateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 			return diagErr
 		}
 
+		objectLocks.Lock(accountLockKey(grant.GetUserOrRole()))
+		defer objectLocks.Unlock(accountLockKey(grant.GetUserOrRole()))
+
 		err = updatePrivileges(ctx, db, d, grant)
 		if err != nil {
-			return diag.Errorf("failed updating privileges: %v", err)
+			return rdsErrorDiag(ctx, meta, "failed updating privileges", "", err, rdsGrantHint)
 		}
 	}
 
@@ -641,15 +730,21 @@ func DeleteGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 	}
 
 	// Acquire a lock for the user
-	grantCreateMutex.Lock(grant.GetUserOrRole().IDString())
-	defer grantCreateMutex.Unlock(grant.GetUserOrRole().IDString())
+	objectLocks.Lock(accountLockKey(grant.GetUserOrRole()))
+	defer objectLocks.Unlock(accountLockKey(grant.GetUserOrRole()))
 
-	sqlStatement := grant.SQLRevokeStatement()
-	log.Printf("[DEBUG] SQL to delete grant: %s", sqlStatement)
-	_, err = db.ExecContext(ctx, sqlStatement)
+	flavor, _, err := getFlavorFromMeta(ctx, meta)
 	if err != nil {
-		if !isNonExistingGrant(err) {
-			return diag.Errorf("error revoking %s: %s", sqlStatement, err)
+		return diag.FromErr(err)
+	}
+	for _, flavorGrant := range grantsForFlavor(grant, flavor) {
+		sqlStatement := flavorGrant.SQLRevokeStatement()
+		log.Printf("[DEBUG] SQL to delete grant: %s", sqlStatement)
+		_, err = db.ExecContext(ctx, sqlStatement)
+		if err != nil {
+			if !isNonExistingGrant(err) {
+				return sqlErrorDiag("error revoking grant", sqlStatement, err)
+			}
 		}
 	}
 
@@ -693,7 +788,20 @@ func ImportGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return nil, fmt.Errorf("got error while getting database from meta: %w", err)
 	}
 
-	grants, err := showUserGrants(ctx, db, userOrRole)
+	if isGlobalGrant(desiredGrant) && serverSupports(ctx, meta, capabilityUserTableGlobalGrants) {
+		foundGrant, err := readUserTableGlobalGrant(ctx, db, userOrRole)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read global grant in import: %w", err)
+		}
+		if foundGrant == nil {
+			return nil, fmt.Errorf("failed to find the grant to import: %v", userHostDatabaseTable)
+		}
+		res := resourceGrant().Data(nil)
+		setDataFromGrant(foundGrant, res)
+		return []*schema.ResourceData{res}, nil
+	}
+
+	grants, err := showUserGrants(ctx, db, userOrRole, grantHostMatchingFromMeta(meta))
 	if err != nil {
 		return nil, fmt.Errorf("failed to showUserGrants in import: %w", err)
 	}
@@ -754,7 +862,7 @@ func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData) *schema.Resource
 	// from the grant itself. We can only infer it from the schema.
 	userOrRole := grant.GetUserOrRole()
 	if d.Get("role") != "" {
-		d.Set("role", userOrRole.Name)
+		d.Set("role", roleReference(userOrRole.Name, userOrRole.Host))
 	} else {
 		d.Set("user", userOrRole.Name)
 		d.Set("host", userOrRole.Host)
@@ -792,8 +900,122 @@ func combineGrants(grantA MySQLGrant, grantB MySQLGrant) (MySQLGrant, error) {
 	return nil, fmt.Errorf("unable to combine MySQLGrant %s of type %T with %s of type %T", grantA, grantA, grantB, grantB)
 }
 
-func getMatchingGrant(ctx context.Context, db *sql.DB, desiredGrant MySQLGrant) (MySQLGrant, error) {
-	allGrants, err := showUserGrants(ctx, db, desiredGrant.GetUserOrRole())
+// findGrant returns the grant of the server covering the same account and objects as
+// desiredGrant, or nil if there's none.
+func findGrant(ctx context.Context, db *sql.DB, meta interface{}, desiredGrant MySQLGrant) (MySQLGrant, error) {
+	if isGlobalGrant(desiredGrant) && serverSupports(ctx, meta, capabilityUserTableGlobalGrants) {
+		return readUserTableGlobalGrant(ctx, db, desiredGrant.GetUserOrRole())
+	}
+	return getMatchingGrant(ctx, db, desiredGrant, grantHostMatchingFromMeta(meta))
+}
+
+// isGlobalGrant returns whether grant is a grant of privileges on *.*.
+func isGlobalGrant(grant MySQLGrant) bool {
+	tableGrant, ok := grant.(*TablePrivilegeGrant)
+	return ok && tableGrant.GetDatabase() == "*" && tableGrant.GetTable() == "*"
+}
+
+// userTableGlobalPrivileges are the privilege columns of mysql.user on MySQL 5.6, in the order
+// of SHOW GRANTS.
+var userTableGlobalPrivileges = []struct {
+	column    string
+	privilege string
+}{
+	{"select_priv", "SELECT"},
+	{"insert_priv", "INSERT"},
+	{"update_priv", "UPDATE"},
+	{"delete_priv", "DELETE"},
+	{"create_priv", "CREATE"},
+	{"drop_priv", "DROP"},
+	{"reload_priv", "RELOAD"},
+	{"shutdown_priv", "SHUTDOWN"},
+	{"process_priv", "PROCESS"},
+	{"file_priv", "FILE"},
+	{"references_priv", "REFERENCES"},
+	{"index_priv", "INDEX"},
+	{"alter_priv", "ALTER"},
+	{"show_db_priv", "SHOW DATABASES"},
+	{"super_priv", "SUPER"},
+	{"create_tmp_table_priv", "CREATE TEMPORARY TABLES"},
+	{"lock_tables_priv", "LOCK TABLES"},
+	{"execute_priv", "EXECUTE"},
+	{"repl_slave_priv", "REPLICATION SLAVE"},
+	{"repl_client_priv", "REPLICATION CLIENT"},
+	{"create_view_priv", "CREATE VIEW"},
+	{"show_view_priv", "SHOW VIEW"},
+	{"create_routine_priv", "CREATE ROUTINE"},
+	{"alter_routine_priv", "ALTER ROUTINE"},
+	{"create_user_priv", "CREATE USER"},
+	{"event_priv", "EVENT"},
+	{"trigger_priv", "TRIGGER"},
+	{"create_tablespace_priv", "CREATE TABLESPACE"},
+}
+
+// readUserTableGlobalGrant reads the global grant of an account from its row of mysql.user.
+// MySQL 5.6 prints the password hash, TLS requirements and resource limits in the global row of
+// SHOW GRANTS, which the parser doesn't reliably tell apart from the grant.
+func readUserTableGlobalGrant(ctx context.Context, db *sql.DB, userOrRole UserOrRole) (MySQLGrant, error) {
+	rows, err := db.QueryContext(ctx, "SELECT * FROM mysql.user WHERE User = ? AND Host = ?", userOrRole.Name, userOrRole.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading mysql.user: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	row, err := scanRowMap(rows, columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading mysql.user: %w", err)
+	}
+	return globalGrantFromUserRow(row, userOrRole), nil
+}
+
+// globalGrantFromUserRow returns the global grant of a row of mysql.user, with lowercased
+// column names, or nil if it only has USAGE.
+func globalGrantFromUserRow(row map[string]string, userOrRole UserOrRole) MySQLGrant {
+	privileges := []string{}
+	for _, p := range userTableGlobalPrivileges {
+		if strings.EqualFold(row[p.column], "Y") {
+			privileges = append(privileges, p.privilege)
+		}
+	}
+	if len(privileges) == len(userTableGlobalPrivileges) {
+		privileges = []string{"ALL PRIVILEGES"}
+	}
+	privileges = normalizePerms(privileges)
+	if len(privileges) == 0 {
+		return nil
+	}
+
+	var tlsReqs tlsRequirements
+	switch strings.ToUpper(row["ssl_type"]) {
+	case "ANY":
+		tlsReqs.SSL = true
+	case "X509":
+		tlsReqs.X509 = true
+	case "SPECIFIED":
+		tlsReqs.Cipher = row["ssl_cipher"]
+		tlsReqs.Issuer = row["x509_issuer"]
+		tlsReqs.Subject = row["x509_subject"]
+	}
+
+	return &TablePrivilegeGrant{
+		Database:   "*",
+		Table:      "*",
+		Privileges: privileges,
+		Grant:      strings.EqualFold(row["grant_priv"], "Y"),
+		UserOrRole: userOrRole,
+		TLSOption:  tlsReqs.SQL(),
+	}
+}
+
+func getMatchingGrant(ctx context.Context, db *sql.DB, desiredGrant MySQLGrant, hostMatching grantHostMatching) (MySQLGrant, error) {
+	allGrants, err := showUserGrants(ctx, db, desiredGrant.GetUserOrRole(), hostMatching)
 	var result MySQLGrant
 	if err != nil {
 		return nil, fmt.Errorf("showGrant - getting all grants failed: %w", err)
@@ -821,38 +1043,50 @@ func getMatchingGrant(ctx context.Context, db *sql.DB, desiredGrant MySQLGrant)
 	return result, nil
 }
 
-var (
-	kUserOrRoleRegex = regexp.MustCompile("['`]?([^'`]+)['`]?(?:@['`]?([^'`]+)['`]?)?")
-)
-
+// parseUserOrRoleFromRow parses the account at the start of s, e.g. `jdoe`@`%` of SHOW GRANTS,
+// ignoring what follows it like WITH GRANT OPTION. The names may contain any quoted characters.
 func parseUserOrRoleFromRow(userOrRoleStr string) (*UserOrRole, error) {
-	userHostMatches := kUserOrRoleRegex.FindStringSubmatch(userOrRoleStr)
-	if len(userHostMatches) == 3 {
-		return &UserOrRole{
-			Name: userHostMatches[1],
-			Host: userHostMatches[2],
-		}, nil
-	} else if len(userHostMatches) == 2 {
-		return &UserOrRole{
-			Name: userHostMatches[1],
-			Host: "%",
-		}, nil
-	} else {
-		return nil, fmt.Errorf("failed to parse user or role portion of grant statement: %s", userOrRoleStr)
+	name, rest, err := cutQuotedName(strings.TrimSpace(userOrRoleStr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user or role portion of grant statement %s: %w", userOrRoleStr, err)
+	}
+	host := ""
+	if strings.HasPrefix(rest, "@") {
+		host, _, err = cutQuotedName(rest[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse host of user or role portion of grant statement %s: %w", userOrRoleStr, err)
+		}
 	}
+	return &UserOrRole{Name: name, Host: host}, nil
 }
 
-var (
-	kDatabaseAndObjectRegex = regexp.MustCompile("['`]?([^'`]+)['`]?\\.['`]?([^'`]+)['`]?")
-)
-
+// parseDatabaseQualifiedObject parses e.g. `app`.`users` or *.* of SHOW GRANTS.
 func parseDatabaseQualifiedObject(objectRef string) (string, string, error) {
-	if matches := kDatabaseAndObjectRegex.FindStringSubmatch(objectRef); len(matches) == 3 {
-		return matches[1], matches[2], nil
+	database, rest, err := cutQuotedName(strings.TrimSpace(objectRef))
+	if err == nil && strings.HasPrefix(rest, ".") {
+		var object string
+		if object, rest, err = cutQuotedName(rest[1:]); err == nil && strings.TrimSpace(rest) == "" {
+			return database, object, nil
+		}
 	}
 	return "", "", fmt.Errorf("failed to parse database and table portion of grant statement: %s", objectRef)
 }
 
+// grantRequireSQL returns the REQUIRE clause of a grant with tls_option, rendered with its values
+// escaped, or nothing for NONE.
+func grantRequireSQL(tlsOption string) string {
+	tlsReqs, err := parseTLSOption(tlsOption)
+	if err != nil {
+		// Configured values are validated, so this is an option read back in an unknown format.
+		log.Printf("[WARN] Ignoring the TLS option of the grant: %v", err)
+		return ""
+	}
+	if requireSQL := tlsReqs.SQL(); requireSQL != "NONE" {
+		return " REQUIRE " + requireSQL
+	}
+	return ""
+}
+
 var (
 	kRequireRegex = regexp.MustCompile(`.*REQUIRE\s+(.*)`)
 
@@ -860,7 +1094,8 @@ var (
 
 	procedureGrantRegex = regexp.MustCompile(`GRANT\s+(.+)\s+ON\s+(FUNCTION|PROCEDURE)\s+(.+)\s+TO\s+(.+)`)
 	tableGrantRegex     = regexp.MustCompile(`GRANT\s+(.+)\s+ON\s+(.+)\s+TO\s+(.+)`)
-	roleGrantRegex      = regexp.MustCompile(`GRANT\s+(.+)\s+TO\s+(.+)`)
+	// MariaDB grants one role per row, with WITH ADMIN OPTION after the grantee.
+	roleGrantRegex = regexp.MustCompile(`GRANT\s+(.+)\s+TO\s+(.+?)(?:\s+WITH\s+ADMIN\s+OPTION)?$`)
 )
 
 func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
@@ -871,10 +1106,19 @@ func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
 		return nil, nil
 	}
 
-	// Parse Require Statement
+	// MariaDB lists default roles among the grants; they're managed by mysql_default_roles.
+	if strings.HasPrefix(grantStr, "SET DEFAULT ROLE") {
+		return nil, nil
+	}
+
+	// Parse Require Statement. MariaDB and MySQL 5.6 print resource limits and GRANT OPTION
+	// after it, which aren't part of it.
 	tlsOption := "NONE"
 	if requireMatches := kRequireRegex.FindStringSubmatch(grantStr); len(requireMatches) == 2 {
 		tlsOption = requireMatches[1]
+		if _, rest := parseTLSRequirements(tlsOption); rest != tlsOption {
+			tlsOption = strings.TrimSpace(strings.TrimSuffix(tlsOption, rest))
+		}
 	}
 
 	if procedureMatches := procedureGrantRegex.FindStringSubmatch(grantStr); len(procedureMatches) == 5 {
@@ -939,12 +1183,17 @@ func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
 		log.Printf("[DEBUG] Got table parsed grant: %s, parsed grant is %s: %v", grantStr, reflect.TypeOf(grant), grant)
 		return grant, nil
 	} else if roleMatches := roleGrantRegex.FindStringSubmatch(grantStr); len(roleMatches) == 3 {
-		rolesStart := strings.Split(roleMatches[1], ",")
+		rolesStart := splitQuotedList(roleMatches[1])
 		roles := make([]string, len(rolesStart))
 
 		for i, role := range rolesStart {
-			roles[i] = strings.Trim(role, "`@%\" ")
+			parsedRole, err := parseUserOrRoleFromRow(strings.TrimSpace(role))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse role of role grant: %w", err)
+			}
+			roles[i] = roleReference(parsedRole.Name, parsedRole.Host)
 		}
+		sort.Strings(roles)
 
 		userOrRole, err := parseUserOrRoleFromRow(roleMatches[2])
 		if err != nil {
@@ -965,12 +1214,13 @@ func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
 	}
 }
 
-func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]MySQLGrant, error) {
+func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole, hostMatching grantHostMatching) ([]MySQLGrant, error) {
 	grants := []MySQLGrant{}
 
 	sqlStatement := fmt.Sprintf("SHOW GRANTS FOR %s", userOrRole.SQLString())
 	log.Printf("[DEBUG] SQL to show grants: %s", sqlStatement)
-	rows, err := db.QueryContext(ctx, sqlStatement)
+	// The rows are shared with the other grants of the account read before the next write.
+	rows, err := cachedQuery(ctx, db, sqlStatement)
 
 	if isNonExistingGrant(err) {
 		return []MySQLGrant{}, nil
@@ -980,14 +1230,11 @@ func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]M
 		return nil, fmt.Errorf("showUserGrants - getting grants failed: %w", err)
 	}
 
-	defer rows.Close()
-	for rows.Next() {
-		var rawGrant string
-
-		err := rows.Scan(&rawGrant)
-		if err != nil {
-			return nil, fmt.Errorf("showUserGrants - reading row failed: %w", err)
+	for _, row := range rows {
+		if len(row) != 1 {
+			return nil, fmt.Errorf("showUserGrants - expected 1 column, got %d", len(row))
 		}
+		rawGrant := row[0].String
 
 		parsedGrant, err := parseGrantFromRow(rawGrant)
 		if err != nil {
@@ -996,21 +1243,121 @@ func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]M
 		if parsedGrant == nil {
 			continue
 		}
-
-		// Filter out any grants that don't match the provided user
-		// Percona returns also grants for % if we requested IP.
-		// Skip them as we don't want terraform to consider it.
-		if !parsedGrant.GetUserOrRole().Equals(userOrRole) {
-			log.Printf("[DEBUG] Skipping grant for %s as it doesn't match %s", parsedGrant.GetUserOrRole().SQLString(), userOrRole.SQLString())
-			continue
-		}
 		grants = append(grants, parsedGrant)
 
 	}
+
+	// Filter out any grants that don't match the provided user
+	// Percona returns also grants for % if we requested IP.
+	// Skip them as we don't want terraform to consider it.
+	grants = filterGrantsByHost(grants, userOrRole, hostMatching)
 	log.Printf("[DEBUG] Parsed grants are: %#v", grants)
 	return grants, nil
 }
 
+// grantHostMatching decides which rows of SHOW GRANTS belong to the requested account, as
+// Percona and some proxies also return the grants of accounts whose host matches it.
+type grantHostMatching string
+
+const (
+	// grantHostMatchingExact keeps the rows whose host is exactly the requested one.
+	grantHostMatchingExact grantHostMatching = "exact"
+	// grantHostMatchingNormalize treats an empty host and % as the same.
+	grantHostMatchingNormalize grantHostMatching = "normalize"
+	// grantHostMatchingMostSpecific keeps the rows of the most specific host covering the
+	// requested one, e.g. 10.0.0.% over % for 10.0.0.1.
+	grantHostMatchingMostSpecific grantHostMatching = "prefer_most_specific"
+)
+
+var grantHostMatchings = []string{
+	string(grantHostMatchingExact),
+	string(grantHostMatchingNormalize),
+	string(grantHostMatchingMostSpecific),
+}
+
+func grantHostMatchingFromMeta(meta interface{}) grantHostMatching {
+	if hostMatching := meta.(*MySQLConfiguration).GrantHostMatching; hostMatching != "" {
+		return hostMatching
+	}
+	return grantHostMatchingNormalize
+}
+
+func normalizeGrantHost(host string) string {
+	if host == "" {
+		return "%"
+	}
+	return strings.ToLower(host)
+}
+
+// grantHostCovers returns whether the host pattern, with the % and _ wildcards, covers host.
+func grantHostCovers(pattern, host string) bool {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range normalizeGrantHost(pattern) {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String()).MatchString(normalizeGrantHost(host))
+}
+
+// grantHostSpecificity ranks host patterns by their number of characters other than wildcards.
+func grantHostSpecificity(pattern string) int {
+	return len(strings.NewReplacer("%", "", "_", "").Replace(normalizeGrantHost(pattern)))
+}
+
+// filterGrantsByHost returns the grants of userOrRole according to the host matching.
+func filterGrantsByHost(grants []MySQLGrant, userOrRole UserOrRole, hostMatching grantHostMatching) []MySQLGrant {
+	var bestHost string
+	if hostMatching == grantHostMatchingMostSpecific {
+		found := false
+		for _, grant := range grants {
+			grantee := grant.GetUserOrRole()
+			if grantee.Name != userOrRole.Name || !grantHostCovers(grantee.Host, userOrRole.Host) {
+				continue
+			}
+			host := normalizeGrantHost(grantee.Host)
+			switch {
+			case !found, host == normalizeGrantHost(userOrRole.Host):
+			case bestHost == normalizeGrantHost(userOrRole.Host):
+				continue
+			case grantHostSpecificity(host) < grantHostSpecificity(bestHost):
+				continue
+			case grantHostSpecificity(host) == grantHostSpecificity(bestHost) && host > bestHost:
+				// Ties are broken by name, so the choice doesn't depend on the order of rows.
+				continue
+			}
+			bestHost, found = host, true
+		}
+	}
+
+	filtered := []MySQLGrant{}
+	for _, grant := range grants {
+		grantee := grant.GetUserOrRole()
+		var matches bool
+		switch hostMatching {
+		case grantHostMatchingExact:
+			matches = grantee.Name == userOrRole.Name && grantee.Host == userOrRole.Host
+		case grantHostMatchingMostSpecific:
+			matches = grantee.Name == userOrRole.Name && normalizeGrantHost(grantee.Host) == bestHost
+		default:
+			matches = grantee.Equals(userOrRole)
+		}
+		if !matches {
+			log.Printf("[DEBUG] Skipping grant for %s as it doesn't match %s", grantee.SQLString(), userOrRole.SQLString())
+			continue
+		}
+		filtered = append(filtered, grant)
+	}
+	return filtered
+}
+
 func removeUselessPerms(grants []string) []string {
 	ret := []string{}
 	for _, grant := range grants {
@@ -1070,7 +1417,7 @@ func normalizeColumnOrder(perm string) string {
 
 	parts := strings.Split(m[2], ",")
 	for i := range parts {
-		parts[i] = strings.Trim(parts[i], "` ")
+		parts[i] = strings.Trim(parts[i], "`\" ")
 	}
 	sort.Strings(parts)
 	precursor := strings.Trim(m[1], " ")
@@ -1083,8 +1430,8 @@ var kReAllPrivileges = regexp.MustCompile(`\bALL ?(PRIVILEGES)?\b`)
 func normalizePerms(perms []string) []string {
 	ret := []string{}
 	for _, perm := range perms {
-		// Remove leading and trailing backticks and spaces
-		permNorm := strings.Trim(perm, "` ")
+		// Remove leading and trailing backticks, double quotes of ANSI_QUOTES and spaces
+		permNorm := strings.Trim(perm, "`\" ")
 		permUcase := strings.ToUpper(permNorm)
 
 		// Normalize ALL and ALLPRIVILEGES to ALL PRIVILEGES
@@ -1105,6 +1452,8 @@ func normalizePerms(perms []string) []string {
 	return ret
 }
 
+// setToArray returns the strings of a set sorted, so statements built from them don't depend on
+// the order of the set.
 func setToArray(s interface{}) []string {
 	set, ok := s.(*schema.Set)
 	if !ok {
@@ -1115,5 +1464,6 @@ func setToArray(s interface{}) []string {
 	for _, elem := range set.List() {
 		ret = append(ret, elem.(string))
 	}
+	sort.Strings(ret)
 	return ret
 }
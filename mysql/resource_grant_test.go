@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -462,7 +464,7 @@ func testAccPrivilege(rn string, privilege string, expectExists bool, expectGran
 			}
 		}
 
-		grants, err := showUserGrants(context.Background(), db, userOrRole)
+		grants, err := showUserGrants(context.Background(), db, userOrRole, grantHostMatchingNormalize)
 		if err != nil {
 			return err
 		}
@@ -548,6 +550,7 @@ func testAccGrantConfigNoGrant(dbName string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -573,6 +576,7 @@ func testAccGrantConfigWithPrivs(dbName, privs string, grantOption bool) string
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -608,6 +612,7 @@ func testAccGrantConfigWithDynamicMySQL8(dbName string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -630,6 +635,7 @@ func testAccGrantConfigBasic(dbName string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -650,6 +656,7 @@ func testAccGrantConfigBasicWithGrant(dbName string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -683,6 +690,7 @@ resource "mysql_grant" "test_bet" {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test_all" {
@@ -721,6 +729,7 @@ func testAccGrantConfigBroken(dbName string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -747,6 +756,7 @@ func testAccGrantConfigRole(dbName string, roleName string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_role" "test" {
@@ -765,6 +775,7 @@ func testAccGrantConfigRoleWithGrantOption(dbName string, roleName string) strin
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_role" "test" {
@@ -784,6 +795,7 @@ func testAccGrantConfigRoleToUser(dbName string, roleName string) string {
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "jdoe" {
@@ -894,6 +906,117 @@ WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = 'PROCEDURE'
 	}
 }
 
+func TestParseGrantFromRowTLSOption(t *testing.T) {
+	grant, err := parseGrantFromRow("GRANT SELECT ON *.* TO `jdoe`@`%` REQUIRE SSL WITH GRANT OPTION MAX_USER_CONNECTIONS 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := grant.(*TablePrivilegeGrant).TLSOption; got != "SSL" {
+		t.Errorf("expected TLS option SSL, got %q", got)
+	}
+}
+
+func TestFilterGrantsByHost(t *testing.T) {
+	grantFor := func(host string) MySQLGrant {
+		return &TablePrivilegeGrant{
+			Database:   "*",
+			Table:      "*",
+			Privileges: []string{"SELECT"},
+			UserOrRole: UserOrRole{Name: "jdoe", Host: host},
+		}
+	}
+	hostsOf := func(grants []MySQLGrant) []string {
+		hosts := []string{}
+		for _, grant := range grants {
+			hosts = append(hosts, grant.GetUserOrRole().Host)
+		}
+		return hosts
+	}
+
+	tests := []struct {
+		requested    string
+		hostMatching grantHostMatching
+		rows         []string
+		expected     []string
+	}{
+		{"10.0.0.1", grantHostMatchingExact, []string{"%", "10.0.0.1"}, []string{"10.0.0.1"}},
+		{"", grantHostMatchingExact, []string{"%"}, []string{}},
+		{"", grantHostMatchingNormalize, []string{"%", "10.0.0.1"}, []string{"%"}},
+		{"10.0.0.1", grantHostMatchingMostSpecific, []string{"%", "10.0.0.%", "10.0.0.1"}, []string{"10.0.0.1"}},
+		{"10.0.0.1", grantHostMatchingMostSpecific, []string{"%", "10.0.0.%", "10.0.%"}, []string{"10.0.0.%"}},
+		{"10.0.0.1", grantHostMatchingMostSpecific, []string{"10.0.%", "%", "10.0.%"}, []string{"10.0.%", "10.0.%"}},
+		{"10.0.0.1", grantHostMatchingMostSpecific, []string{"10.1.%"}, []string{}},
+	}
+	for _, test := range tests {
+		grants := []MySQLGrant{}
+		for _, host := range test.rows {
+			grants = append(grants, grantFor(host))
+		}
+		got := hostsOf(filterGrantsByHost(grants, UserOrRole{Name: "jdoe", Host: test.requested}, test.hostMatching))
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s of %q for %q = %q, expected %q", test.hostMatching, test.rows, test.requested, got, test.expected)
+		}
+	}
+}
+
+func TestResourceGrantStateUpgradeV0(t *testing.T) {
+	cases := []struct {
+		state    map[string]interface{}
+		expected string
+	}{
+		{
+			state: map[string]interface{}{
+				"id":         "jdoe@%:mydb",
+				"user":       "jdoe",
+				"host":       "%",
+				"database":   "mydb",
+				"privileges": []interface{}{"SELECT"},
+			},
+			expected: "jdoe@%:`mydb`:*",
+		},
+		{
+			state: map[string]interface{}{
+				"id":         "jdoe@localhost:mydb",
+				"user":       "jdoe",
+				"host":       "localhost",
+				"database":   "mydb",
+				"table":      "users",
+				"privileges": []interface{}{"SELECT"},
+			},
+			expected: "jdoe@localhost:`mydb`:`users`",
+		},
+		{
+			state: map[string]interface{}{
+				"id":       "jdoe@%:",
+				"user":     "jdoe",
+				"host":     "%",
+				"database": "",
+				"roles":    []interface{}{"developer"},
+			},
+			expected: "jdoe@%",
+		},
+		{
+			state: map[string]interface{}{
+				"id":         "developer:PROCEDURE mydb.myproc",
+				"role":       "developer",
+				"database":   "PROCEDURE mydb.myproc",
+				"privileges": []interface{}{"EXECUTE"},
+			},
+			expected: "developer:`mydb`:`myproc`",
+		},
+	}
+
+	for _, c := range cases {
+		state, err := resourceGrantStateUpgradeV0(context.Background(), c.state, nil)
+		if err != nil {
+			t.Fatalf("upgrading %v failed: %v", c.state["id"], err)
+		}
+		if state["id"] != c.expected {
+			t.Errorf("upgraded ID = %v, expected %s", state["id"], c.expected)
+		}
+	}
+}
+
 func TestAccGrantOnProcedure(t *testing.T) {
 	procedureName := "test_procedure"
 	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
@@ -953,6 +1076,7 @@ func testAccGrantConfigProcedureWithTable(procedureName string, dbName string, h
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -979,6 +1103,7 @@ func testAccGrantConfigProcedureWithDatabase(procedureName string, dbName string
 	return fmt.Sprintf(`
 resource "mysql_database" "test" {
   name = "%s"
+  force_destroy = true
 }
 
 resource "mysql_user" "test" {
@@ -1076,6 +1201,7 @@ func TestAllowDuplicateUsersDifferentTables(t *testing.T) {
 	duplicateUserConfig := fmt.Sprintf(`
 	resource "mysql_database" "test" {
 	  name = "%s"
+	  force_destroy = true
 	}
 
 	resource "mysql_user" "test" {
@@ -1141,6 +1267,7 @@ func TestDisallowDuplicateUsersSameTable(t *testing.T) {
 	duplicateUserConfig := fmt.Sprintf(`
 	resource "mysql_database" "test" {
 	  name = "%s"
+	  force_destroy = true
 	}
 
 	resource "mysql_user" "test" {
@@ -1183,3 +1310,87 @@ func TestDisallowDuplicateUsersSameTable(t *testing.T) {
 		},
 	})
 }
+
+func TestParseGrantFromRowANSIQuotes(t *testing.T) {
+	grant, err := parseGrantFromRow(`GRANT SELECT ("b", "a"), INSERT ON "my app"."my""table" TO "jdoe"@"10.0.%" WITH GRANT OPTION`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableGrant := grant.(*TablePrivilegeGrant)
+	if tableGrant.Database != "my app" || tableGrant.Table != `my"table` || tableGrant.UserOrRole != (UserOrRole{Name: "jdoe", Host: "10.0.%"}) || !tableGrant.Grant {
+		t.Errorf("unexpected grant %#v", tableGrant)
+	}
+	if expected := []string{"INSERT", "SELECT(A, B)"}; !reflect.DeepEqual(tableGrant.Privileges, expected) {
+		t.Errorf("expected privileges %v, got %v", expected, tableGrant.Privileges)
+	}
+
+	grant, err = parseGrantFromRow(`GRANT "reader"@"%","writer"@"%" TO "jdoe"@"%"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roles := grant.(*RoleGrant).Roles; !reflect.DeepEqual(roles, []string{"reader", "writer"}) {
+		t.Errorf("unexpected roles %v", roles)
+	}
+
+	if got := extractIdentAfter(`CREATE DATABASE "app" /*!40100 DEFAULT CHARACTER SET "latin1" */`, defaultCharacterSetKeyword); got != "latin1" {
+		t.Errorf("extractIdentAfter = %q", got)
+	}
+}
+
+func TestRoleGrantRolesSorted(t *testing.T) {
+	grant, err := parseGrantFromRow("GRANT `writer`@`%`,`admin`@`%` TO `jdoe`@`%`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roleGrant := grant.(*RoleGrant)
+	roleGrant.AppendRoles([]string{"auditor"})
+	if expected := []string{"admin", "auditor", "writer"}; !reflect.DeepEqual(roleGrant.Roles, expected) {
+		t.Errorf("expected roles %v, got %v", expected, roleGrant.Roles)
+	}
+
+	roles := schema.NewSet(schema.HashString, []interface{}{"writer", "reader", "admin"})
+	if expected := []string{"admin", "reader", "writer"}; !reflect.DeepEqual(setToArray(roles), expected) {
+		t.Errorf("expected sorted roles %v, got %v", expected, setToArray(roles))
+	}
+}
+
+func TestGlobalGrantFromUserRow(t *testing.T) {
+	jdoe := UserOrRole{Name: "jdoe", Host: "%"}
+	row := map[string]string{
+		"select_priv":      "Y",
+		"process_priv":     "Y",
+		"repl_client_priv": "Y",
+		"grant_priv":       "Y",
+		"ssl_type":         "SPECIFIED",
+		"ssl_cipher":       "",
+		"x509_issuer":      "CN=ca",
+		"x509_subject":     "",
+		"password":         "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19",
+	}
+	grant := globalGrantFromUserRow(row, jdoe).(*TablePrivilegeGrant)
+	if expected := []string{"PROCESS", "REPLICATION CLIENT", "SELECT"}; !reflect.DeepEqual(grant.Privileges, expected) {
+		t.Errorf("expected privileges %v, got %v", expected, grant.Privileges)
+	}
+	if !grant.Grant {
+		t.Error("expected the grant option")
+	}
+	if grant.TLSOption != "ISSUER 'CN=ca'" {
+		t.Errorf("unexpected TLS option %q", grant.TLSOption)
+	}
+	if !isGlobalGrant(grant) {
+		t.Error("expected a global grant")
+	}
+
+	all := map[string]string{"ssl_type": ""}
+	for _, p := range userTableGlobalPrivileges {
+		all[p.column] = "Y"
+	}
+	grant = globalGrantFromUserRow(all, jdoe).(*TablePrivilegeGrant)
+	if !reflect.DeepEqual(grant.Privileges, []string{"ALL PRIVILEGES"}) || grant.Grant || grant.TLSOption != "NONE" {
+		t.Errorf("unexpected grant %#v", grant)
+	}
+
+	if grant := globalGrantFromUserRow(map[string]string{"grant_priv": "Y"}, jdoe); grant != nil {
+		t.Errorf("expected no grant for USAGE, got %#v", grant)
+	}
+}
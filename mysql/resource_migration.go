@@ -0,0 +1,353 @@
+package mysql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const unknownTableErrCode = 1146
+
+// migrationFileRe matches migration files named <version>_<description>.sql.
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+
+type migration struct {
+	Version     int64
+	Description string
+	Path        string
+	Checksum    string
+}
+
+func resourceMigration() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: ApplyMigrations,
+		ReadContext:   ReadMigrations,
+		UpdateContext: ApplyMigrations,
+		DeleteContext: DeleteMigrations,
+		Timeouts:      defaultResourceTimeouts(true),
+		CustomizeDiff: customizeDiffMigrations,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportMigrations,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"directory": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"history_table": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "schema_migrations",
+			},
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"applied": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// readMigrationFiles returns the migrations in directory ordered by version. Files not named
+// <version>_<description>.sql are ignored.
+func readMigrationFiles(directory string) ([]migration, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading migrations directory: %w", err)
+	}
+
+	var migrations []migration
+	seen := make(map[int64]string)
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration %s: %w", entry.Name(), err)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		path := filepath.Join(directory, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading migration %s: %w", entry.Name(), err)
+		}
+		sum := sha256.Sum256(content)
+
+		migrations = append(migrations, migration{
+			Version:     version,
+			Description: match[2],
+			Path:        path,
+			Checksum:    hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// pendingMigrations returns the migrations not yet applied, and refuses applied migrations which
+// were modified since and pending migrations older than the latest applied one.
+func pendingMigrations(migrations []migration, applied map[int64]string) ([]migration, error) {
+	var latest int64
+	for version := range applied {
+		if version > latest {
+			latest = version
+		}
+	}
+
+	var pending []migration
+	for _, m := range migrations {
+		checksum, ok := applied[m.Version]
+		if ok {
+			if checksum != m.Checksum {
+				return nil, fmt.Errorf("migration %d (%s) was modified after it was applied", m.Version, m.Description)
+			}
+			continue
+		}
+		if m.Version < latest {
+			return nil, fmt.Errorf("migration %d (%s) is older than the latest applied migration %d", m.Version, m.Description, latest)
+		}
+		pending = append(pending, m)
+	}
+	return pending, nil
+}
+
+// appliedFromDiff returns the checksums of the applied migrations in state by version.
+func appliedFromDiff(d *schema.ResourceDiff) (map[int64]string, error) {
+	applied := make(map[int64]string)
+	for k, v := range d.Get("applied").(map[string]interface{}) {
+		version, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid applied migration version %q: %w", k, err)
+		}
+		applied[version] = v.(string)
+	}
+	return applied, nil
+}
+
+// customizeDiffMigrations plans the version of the latest migration file, and already fails the
+// plan on modified and out-of-order migrations according to the applied migrations in state.
+func customizeDiffMigrations(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("directory") {
+		return d.SetNewComputed("version")
+	}
+
+	migrations, err := readMigrationFiles(d.Get("directory").(string))
+	if err != nil {
+		return err
+	}
+	applied, err := appliedFromDiff(d)
+	if err != nil {
+		return err
+	}
+	pending, err := pendingMigrations(migrations, applied)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := d.SetNew("version", int(pending[len(pending)-1].Version)); err != nil {
+		return err
+	}
+	return d.SetNewComputed("applied")
+}
+
+func migrationsID(database, historyTable string) string {
+	return fmt.Sprintf("%s.%s", database, historyTable)
+}
+
+func migrationHistoryTable(d *schema.ResourceData) string {
+	return fmt.Sprintf("%s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("history_table").(string)))
+}
+
+// readAppliedMigrations returns the checksums of the applied migrations by version.
+func readAppliedMigrations(ctx context.Context, db *sql.DB, historyTable string) (map[int64]string, error) {
+	stmtSQL := fmt.Sprintf("SELECT version, checksum FROM %s", historyTable)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]string)
+	for rows.Next() {
+		var version int64
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+func ApplyMigrations(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	historyTable := migrationHistoryTable(d)
+
+	stmtSQL := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"version BIGINT NOT NULL PRIMARY KEY, "+
+		"description VARCHAR(255) NOT NULL, "+
+		"checksum CHAR(64) NOT NULL, "+
+		"applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)", historyTable)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed creating migration history table %s: %v", historyTable, err)
+	}
+	d.SetId(migrationsID(database, d.Get("history_table").(string)))
+
+	migrations, err := readMigrationFiles(d.Get("directory").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// The history table is checked again, as it may have changed since the plan.
+	applied, err := readAppliedMigrations(ctx, db, historyTable)
+	if err != nil {
+		return diag.Errorf("failed reading migration history: %v", err)
+	}
+	pending, err := pendingMigrations(migrations, applied)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, m := range pending {
+		if err := applyMigration(ctx, db, database, historyTable, m); err != nil {
+			// Record the migrations applied before the failure in state.
+			diags := ReadMigrations(ctx, d, meta)
+			return append(diags, diag.Errorf("failed applying migration %d (%s): %v", m.Version, m.Description, err)...)
+		}
+	}
+
+	return ReadMigrations(ctx, d, meta)
+}
+
+// applyMigration executes the statements of a migration with the database as default, and records
+// it in the history table. MySQL commits DDL implicitly, so a failed migration isn't rolled back.
+func applyMigration(ctx context.Context, db *sql.DB, database, historyTable string, m migration) error {
+	content, err := os.ReadFile(m.Path)
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("USE %s", quoteIdentifier(database))); err != nil {
+		return err
+	}
+
+	for i, stmt := range splitSqlStatements(string(content)) {
+		log.Println("[DEBUG] Executing statement:", stmt)
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d failed: %w", i+1, err)
+		}
+	}
+
+	stmtSQL := fmt.Sprintf("INSERT INTO %s (version, description, checksum) VALUES (?, ?, ?)", historyTable)
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := conn.ExecContext(ctx, stmtSQL, m.Version, m.Description, m.Checksum); err != nil {
+		return fmt.Errorf("failed recording migration: %w", err)
+	}
+	return nil
+}
+
+func ReadMigrations(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	applied, err := readAppliedMigrations(ctx, db, migrationHistoryTable(d))
+	if err != nil {
+		if errNum := mysqlErrorNumber(err); errNum == unknownDatabaseErrCode || errNum == unknownTableErrCode {
+			log.Printf("[WARN] migration history table %s not found; removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed reading migration history: %v", err)
+	}
+
+	var latest int64
+	appliedMap := make(map[string]string, len(applied))
+	for version, checksum := range applied {
+		appliedMap[strconv.FormatInt(version, 10)] = checksum
+		if version > latest {
+			latest = version
+		}
+	}
+
+	d.Set("version", int(latest))
+	d.Set("applied", appliedMap)
+	return nil
+}
+
+// DeleteMigrations only removes the resource from state; migrations aren't reverted and the
+// history table is kept.
+func DeleteMigrations(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[WARN] removing migrations %s from state; the schema and history table are kept", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func ImportMigrations(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, historyTable, ok := strings.Cut(d.Id(), ".")
+	if !ok || database == "" || historyTable == "" {
+		return nil, fmt.Errorf("wrong ID format %s (expected DATABASE.HISTORY_TABLE)", d.Id())
+	}
+
+	d.Set("database", database)
+	d.Set("history_table", historyTable)
+
+	diags := ReadMigrations(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed reading migrations: %v", diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("migration history table %s.%s not found", database, historyTable)
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,173 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccMigration_basic(t *testing.T) {
+	dbName := "tf-test-migration"
+	resourceName := "mysql_migration.test"
+	dir := t.TempDir()
+	writeMigration := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeMigration("001_create_users.sql", "CREATE TABLE users (id INT PRIMARY KEY);")
+	writeMigration("README.md", "Not a migration")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMigrationConfig(dbName, dir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".schema_migrations"),
+					resource.TestCheckResourceAttr(resourceName, "version", "1"),
+					resource.TestCheckResourceAttr(resourceName, "applied.%", "1"),
+					testAccMigrationCheckColumns(dbName, 1),
+				),
+			},
+			{
+				PreConfig: func() {
+					writeMigration("002_add_name.sql", "ALTER TABLE users ADD COLUMN name VARCHAR(64);\nALTER TABLE users ADD COLUMN email VARCHAR(64);")
+				},
+				Config: testAccMigrationConfig(dbName, dir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "version", "2"),
+					resource.TestCheckResourceAttr(resourceName, "applied.%", "2"),
+					testAccMigrationCheckColumns(dbName, 3),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"directory"},
+			},
+			{
+				PreConfig: func() {
+					writeMigration("001_create_users.sql", "CREATE TABLE users (id BIGINT PRIMARY KEY);")
+				},
+				Config:      testAccMigrationConfig(dbName, dir),
+				ExpectError: regexp.MustCompile(`migration 1 \(create_users\) was modified after it was applied`),
+			},
+			{
+				PreConfig: func() {
+					writeMigration("001_create_users.sql", "CREATE TABLE users (id INT PRIMARY KEY);")
+					writeMigration("000_init.sql", "SELECT 1;")
+				},
+				Config:      testAccMigrationConfig(dbName, dir),
+				ExpectError: regexp.MustCompile(`migration 0 \(init\) is older than the latest applied migration 2`),
+			},
+			{
+				PreConfig: func() {
+					if err := os.Remove(filepath.Join(dir, "000_init.sql")); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:   testAccMigrationConfig(dbName, dir),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestPendingMigrations(t *testing.T) {
+	migrations := []migration{
+		{Version: 1, Description: "one", Checksum: "a"},
+		{Version: 2, Description: "two", Checksum: "b"},
+		{Version: 3, Description: "three", Checksum: "c"},
+	}
+
+	pending, err := pendingMigrations(migrations, map[int64]string{1: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].Version != 2 || pending[1].Version != 3 {
+		t.Errorf("expected migrations 2 and 3 to be pending, got %v", pending)
+	}
+
+	if _, err := pendingMigrations(migrations, map[int64]string{1: "x"}); err == nil {
+		t.Error("expected an error for a modified migration")
+	}
+	if _, err := pendingMigrations(migrations, map[int64]string{1: "a", 3: "c"}); err == nil {
+		t.Error("expected an error for an out-of-order migration")
+	}
+}
+
+func TestReadMigrationFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"10_ten.sql":     "SELECT 10",
+		"2_two.sql":      "SELECT 2",
+		"notes.sql":      "SELECT 0",
+		"3_three.sql.gz": "",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrations, err := readMigrationFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Version != 10 {
+		t.Fatalf("expected migrations 2 and 10, got %v", migrations)
+	}
+	if migrations[0].Description != "two" {
+		t.Errorf("expected description two, got %s", migrations[0].Description)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "02_duplicate.sql"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readMigrationFiles(dir); err == nil {
+		t.Error("expected an error for duplicate versions")
+	}
+}
+
+func testAccMigrationCheckColumns(dbName string, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = 'users'", dbName).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count != expected {
+			return fmt.Errorf("expected %d columns in users, got %d", expected, count)
+		}
+		return nil
+	}
+}
+
+func testAccMigrationConfig(dbName, dir string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name          = "%s"
+  force_destroy = true
+}
+
+resource "mysql_migration" "test" {
+  database  = mysql_database.test.name
+  directory = "%s"
+}
+`, dbName, dir)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -14,21 +15,72 @@ import (
 // stable non-empty ID
 const mysqlRdsConfigId = "1223234548"
 
+// rdsTypedConfigurations are the configurations with their own attributes, which can't be
+// in settings.
+var rdsTypedConfigurations = map[string]string{
+	"binlog retention hours": "binlog_retention_hours",
+	"target delay":           "replication_target_delay",
+}
+
+// The bounds of binlog retention hours; Aurora MySQL retains binary logs for up to 90 days.
+const (
+	rdsMaxBinlogRetentionHours    = 168
+	auroraMaxBinlogRetentionHours = 2160
+)
+
+// validateBinlogRetentionHours accepts 0 for NULL and the hours accepted by Aurora, warning
+// about those only Aurora accepts.
+func validateBinlogRetentionHours(val interface{}, key string) (warns []string, errs []error) {
+	hours := val.(int)
+	switch {
+	case hours < 0 || hours > auroraMaxBinlogRetentionHours:
+		errs = append(errs, fmt.Errorf("%q must be between 1 and %d, or 0 to reset it to NULL, got: %d", key, auroraMaxBinlogRetentionHours, hours))
+	case hours > rdsMaxBinlogRetentionHours:
+		warns = append(warns, fmt.Sprintf("%q above %d is only accepted by Aurora MySQL, which allows up to %d", key, rdsMaxBinlogRetentionHours, auroraMaxBinlogRetentionHours))
+	}
+	return
+}
+
+// customizeDiffRDSConfig rejects binlog retention RDS doesn't accept once it's known whether
+// the server is Aurora.
+func customizeDiffRDSConfig(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	hours := d.Get("binlog_retention_hours").(int)
+	if hours <= rdsMaxBinlogRetentionHours || !d.HasChange("binlog_retention_hours") {
+		return nil
+	}
+	if isRds, isAurora := getRdsFromMeta(ctx, meta); isRds && !isAurora {
+		return fmt.Errorf("binlog_retention_hours must be between 1 and %d on RDS for MySQL, got: %d", rdsMaxBinlogRetentionHours, hours)
+	}
+	return nil
+}
+
+func validateRDSConfigSettings(val interface{}, key string) (warns []string, errs []error) {
+	for name := range val.(map[string]interface{}) {
+		if attribute, ok := rdsTypedConfigurations[name]; ok {
+			errs = append(errs, fmt.Errorf("%q can't set %q, use %s instead", key, name, attribute))
+		}
+	}
+	return
+}
+
 func resourceRDSConfig() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateRDSConfig,
 		UpdateContext: UpdateRDSConfig,
 		ReadContext:   ReadRDSConfig,
 		DeleteContext: DeleteRDSConfig,
+		CustomizeDiff: customizeDiffRDSConfig,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"binlog_retention_hours": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     0,
-				Description: "Sets the number of hours to retain binary log files",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validateBinlogRetentionHours,
+				Description:  "Sets the number of hours to retain binary log files",
 			},
 			"replication_target_delay": {
 				Type:        schema.TypeInt,
@@ -36,6 +88,13 @@ func resourceRDSConfig() *schema.Resource {
 				Default:     0,
 				Description: "Sets the number of seconds to delay replication from source database instance to the read replica",
 			},
+			"settings": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateRDSConfigSettings,
+				Description:  "Sets other configurations of rds_set_configuration by name",
+			},
 		},
 	}
 }
@@ -51,10 +110,14 @@ func CreateRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface
 
 		_, err = db.ExecContext(ctx, stmtSQL)
 		if err != nil {
-			return diag.Errorf("failed running SQL to set RDS Config: %v", err)
+			return sqlErrorDiag("failed running SQL to set RDS Config", stmtSQL, err)
 		}
 	}
 
+	if diags := setRDSConfigSettings(ctx, db, d.Get("settings").(map[string]interface{})); diags != nil {
+		return diags
+	}
+
 	d.SetId(mysqlRdsConfigId)
 
 	return nil
@@ -71,7 +134,26 @@ func UpdateRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface
 
 		_, err = db.ExecContext(ctx, stmtSQL)
 		if err != nil {
-			return diag.Errorf("failed updating RDS config: %v", err)
+			return sqlErrorDiag("failed updating RDS config", stmtSQL, err)
+		}
+	}
+
+	if d.HasChange("settings") {
+		oldRaw, newRaw := d.GetChange("settings")
+		settings := newRaw.(map[string]interface{})
+
+		// Removed settings are unset.
+		changed := map[string]interface{}{}
+		for name := range oldRaw.(map[string]interface{}) {
+			if _, ok := settings[name]; !ok {
+				changed[name] = nil
+			}
+		}
+		for name, value := range settings {
+			changed[name] = value
+		}
+		if diags := setRDSConfigSettings(ctx, db, changed); diags != nil {
+			return diags
 		}
 	}
 
@@ -84,25 +166,15 @@ func ReadRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface{}
 		return diag.FromErr(err)
 	}
 
-	stmtSQL := "call mysql.rds_show_configuration"
-
-	log.Println("[DEBUG] Executing query:", stmtSQL)
-	rows, err := db.QueryContext(ctx, stmtSQL)
+	configurations, err := readRDSConfigurations(ctx, db)
 	if err != nil {
 		return diag.Errorf("Error reading RDS config from DB: %v", err)
 	}
 
 	results := make(map[string]string)
-	for rows.Next() {
-		var name, description string
-		var value sql.NullString
-
-		if err := rows.Scan(&name, &value, &description); err != nil {
-			return diag.Errorf("failed validating RDS config: %v", err)
-		}
-
-		if value.Valid {
-			results[name] = value.String
+	for _, configuration := range configurations {
+		if configuration.Value.Valid {
+			results[configuration.Name] = configuration.Value.String
 		}
 	}
 
@@ -127,9 +199,43 @@ func ReadRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface{}
 	d.Set("replication_target_delay", replicationTargetDelay)
 	d.Set("binlog_retention_hours", binlogRetentionPeriod)
 
+	settings := map[string]interface{}{}
+	for name := range d.Get("settings").(map[string]interface{}) {
+		settings[name] = results[name]
+	}
+	d.Set("settings", settings)
+
 	return nil
 }
 
+// rdsConfiguration is a row of mysql.rds_show_configuration; Value is NULL when unset.
+type rdsConfiguration struct {
+	Name        string
+	Value       sql.NullString
+	Description string
+}
+
+func readRDSConfigurations(ctx context.Context, db *sql.DB) ([]rdsConfiguration, error) {
+	stmtSQL := "call mysql.rds_show_configuration"
+
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var configurations []rdsConfiguration
+	for rows.Next() {
+		var configuration rdsConfiguration
+		if err := rows.Scan(&configuration.Name, &configuration.Value, &configuration.Description); err != nil {
+			return nil, err
+		}
+		configurations = append(configurations, configuration)
+	}
+	return configurations, rows.Err()
+}
+
 func DeleteRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -142,10 +248,18 @@ func DeleteRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface
 
 		_, err = db.ExecContext(ctx, stmtSQL)
 		if err != nil {
-			return diag.Errorf("failed unsetting RDS config: %v", err)
+			return sqlErrorDiag("failed unsetting RDS config", stmtSQL, err)
 		}
 	}
 
+	unset := map[string]interface{}{}
+	for name := range d.Get("settings").(map[string]interface{}) {
+		unset[name] = nil
+	}
+	if diags := setRDSConfigSettings(ctx, db, unset); diags != nil {
+		return diags
+	}
+
 	d.SetId("")
 	return nil
 }
@@ -167,3 +281,23 @@ func RDSConfigSQL(d *schema.ResourceData) []string {
 
 	return result
 }
+
+// setRDSConfigSettings sets the configurations by name in order, unsetting those with a nil
+// value.
+func setRDSConfigSettings(ctx context.Context, db *sql.DB, settings map[string]interface{}) diag.Diagnostics {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stmtSQL := "call mysql.rds_set_configuration(?, ?)"
+	for _, name := range names {
+		log.Printf("[DEBUG] Executing statement: %s with %q", stmtSQL, name)
+
+		if _, err := db.ExecContext(ctx, stmtSQL, name, settings[name]); err != nil {
+			return sqlErrorDiag(fmt.Sprintf("failed setting RDS config %q", name), stmtSQL, err)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -210,3 +211,36 @@ func testAccRDSCheckFull(rn string, binlogUpdated, targetDelayUpdated int) resou
 		return nil
 	}
 }
+
+func TestValidateRDSConfigSettings(t *testing.T) {
+	if _, errs := validateRDSConfigSettings(map[string]interface{}{"source delay": "60"}, "settings"); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	if _, errs := validateRDSConfigSettings(map[string]interface{}{"binlog retention hours": "24"}, "settings"); len(errs) != 1 {
+		t.Errorf("expected an error for a configuration with its own attribute, got %v", errs)
+	}
+}
+
+func TestValidateBinlogRetentionHours(t *testing.T) {
+	tests := []struct {
+		hours       int
+		errs, warns int
+	}{
+		{0, 0, 0},
+		{168, 0, 0},
+		{720, 0, 1},
+		{2161, 1, 0},
+		{-1, 1, 0},
+	}
+	for _, test := range tests {
+		warns, errs := validateBinlogRetentionHours(test.hours, "binlog_retention_hours")
+		if len(errs) != test.errs || len(warns) != test.warns {
+			t.Errorf("validateBinlogRetentionHours(%d) = %v, %v, expected %d warnings and %d errors", test.hours, warns, errs, test.warns, test.errs)
+		}
+	}
+
+	_, errs := validateBinlogRetentionHours(2161, "binlog_retention_hours")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "between 1 and 2160") {
+		t.Errorf("expected the error to report the Aurora bound, got %v", errs)
+	}
+}
@@ -0,0 +1,337 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ReplicaStatusMinVersion is the version that renamed SHOW SLAVE STATUS to SHOW REPLICA STATUS.
+var ReplicaStatusMinVersion = "8.0.22"
+
+// resourceRDSReplication replicates an RDS instance from an external source with the RDS
+// stored procedures, since RDS doesn't allow CHANGE MASTER TO.
+func resourceRDSReplication() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateRDSReplication,
+		ReadContext:   ReadRDSReplication,
+		UpdateContext: UpdateRDSReplication,
+		DeleteContext: DeleteRDSReplication,
+		CustomizeDiff: customizeDiffRDSReplication,
+		Timeouts:      defaultResourceTimeouts(true),
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      3306,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"password": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"binlog_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"binlog_position"},
+			},
+			"binlog_position": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"binlog_file"},
+			},
+			"auto_position": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"ssl_encryption": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"running": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"io_running": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sql_running": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"seconds_behind_source": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"last_error": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// customizeDiffRDSReplication requires either a binlog position or GTID auto positioning.
+func customizeDiffRDSReplication(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("binlog_file") {
+		return nil
+	}
+	autoPosition := d.Get("auto_position").(bool)
+	binlogFile := d.Get("binlog_file").(string)
+	if autoPosition && binlogFile != "" {
+		return fmt.Errorf("binlog_file and binlog_position can't be set with auto_position")
+	}
+	if !autoPosition && binlogFile == "" {
+		return fmt.Errorf("either binlog_file and binlog_position, or auto_position must be set")
+	}
+	return nil
+}
+
+// execRDSProcedure calls a stored procedure of RDS. The arguments are passed as placeholders,
+// so the password isn't logged.
+func execRDSProcedure(ctx context.Context, db *sql.DB, summary, stmtSQL string, args ...interface{}) diag.Diagnostics {
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL, args...); err != nil {
+		return sqlErrorDiag(summary, stmtSQL, err)
+	}
+	return nil
+}
+
+func CreateRDSReplication(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if isRds, _ := getRdsFromMeta(ctx, meta); !isRds {
+		return diag.Errorf("mysql_rds_replication is only available on Amazon RDS")
+	}
+
+	host := d.Get("host").(string)
+	port := d.Get("port").(int)
+
+	if diags := setRDSExternalSource(ctx, db, d, d.Get("binlog_file").(string), d.Get("binlog_position").(int)); diags != nil {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d", host, port))
+
+	if d.Get("running").(bool) {
+		if diags := execRDSProcedure(ctx, db, "error starting replication", "CALL mysql.rds_start_replication"); diags != nil {
+			return diags
+		}
+	}
+
+	return ReadRDSReplication(ctx, d, meta)
+}
+
+// setRDSExternalSource sets the external source of replication, from the binlog position or
+// with GTID auto positioning.
+func setRDSExternalSource(ctx context.Context, db *sql.DB, d *schema.ResourceData, binlogFile string, binlogPosition int) diag.Diagnostics {
+	ssl := 0
+	if d.Get("ssl_encryption").(bool) {
+		ssl = 1
+	}
+
+	if d.Get("auto_position").(bool) {
+		return execRDSProcedure(ctx, db, "error setting external source",
+			"CALL mysql.rds_set_external_master_with_auto_position(?, ?, ?, ?, ?, 0)",
+			d.Get("host").(string), d.Get("port").(int), d.Get("user").(string), d.Get("password").(string), ssl)
+	}
+	return execRDSProcedure(ctx, db, "error setting external source",
+		"CALL mysql.rds_set_external_master(?, ?, ?, ?, ?, ?, ?)",
+		d.Get("host").(string), d.Get("port").(int), d.Get("user").(string), d.Get("password").(string),
+		binlogFile, binlogPosition, ssl)
+}
+
+// updateRDSReplicationPassword sets the external source again with the new password. It
+// continues from the position replication stopped at, as the binlog position of the
+// configuration is long applied and would be replayed.
+func updateRDSReplicationPassword(ctx context.Context, db *sql.DB, d *schema.ResourceData) diag.Diagnostics {
+	if diags := execRDSProcedure(ctx, db, "error stopping replication", "CALL mysql.rds_stop_replication"); diags != nil {
+		return diags
+	}
+
+	var binlogFile string
+	var binlogPosition int
+	if !d.Get("auto_position").(bool) {
+		status, err := getReplicaStatus(ctx, db)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if status == nil {
+			return diag.Errorf("error changing replication password: the server is no longer a replica")
+		}
+		binlogFile = firstColumn(status, "relay_source_log_file", "relay_master_log_file")
+		binlogPosition, err = strconv.Atoi(firstColumn(status, "exec_source_log_pos", "exec_master_log_pos"))
+		if err != nil || binlogFile == "" {
+			return diag.Errorf("error changing replication password: could not read the executed binlog position: %v", err)
+		}
+	}
+
+	if diags := execRDSProcedure(ctx, db, "error resetting external source", "CALL mysql.rds_reset_external_master"); diags != nil {
+		return diags
+	}
+	if diags := setRDSExternalSource(ctx, db, d, binlogFile, binlogPosition); diags != nil {
+		return diags
+	}
+
+	if d.Get("running").(bool) {
+		return execRDSProcedure(ctx, db, "error starting replication", "CALL mysql.rds_start_replication")
+	}
+	return nil
+}
+
+// firstColumn returns the first of the columns of status that exists, as they were renamed from
+// Slave and Master to Replica and Source in MySQL 8.0.22.
+func firstColumn(status map[string]string, names ...string) string {
+	for _, name := range names {
+		if value, ok := status[name]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+func UpdateRDSReplication(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("password") {
+		// Sets the running state of the configuration too.
+		if diags := updateRDSReplicationPassword(ctx, db, d); diags != nil {
+			return diags
+		}
+		return ReadRDSReplication(ctx, d, meta)
+	}
+
+	if d.HasChange("running") {
+		var diags diag.Diagnostics
+		if d.Get("running").(bool) {
+			diags = execRDSProcedure(ctx, db, "error starting replication", "CALL mysql.rds_start_replication")
+		} else {
+			diags = execRDSProcedure(ctx, db, "error stopping replication", "CALL mysql.rds_stop_replication")
+		}
+		if diags != nil {
+			return diags
+		}
+	}
+
+	return ReadRDSReplication(ctx, d, meta)
+}
+
+func ReadRDSReplication(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	status, err := getReplicaStatus(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if status == nil {
+		log.Printf("[WARN] Replication (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	column := func(names ...string) string {
+		return firstColumn(status, names...)
+	}
+
+	ioRunning := column("replica_io_running", "slave_io_running")
+	sqlRunning := column("replica_sql_running", "slave_sql_running")
+	secondsBehind, _ := strconv.Atoi(column("seconds_behind_source", "seconds_behind_master"))
+
+	lastError := column("last_io_error")
+	if lastError == "" {
+		lastError = column("last_sql_error")
+	}
+
+	d.Set("host", column("source_host", "master_host"))
+	if port, err := strconv.Atoi(column("source_port", "master_port")); err == nil {
+		d.Set("port", port)
+	}
+	d.Set("io_running", ioRunning)
+	d.Set("sql_running", sqlRunning)
+	d.Set("seconds_behind_source", secondsBehind)
+	d.Set("last_error", lastError)
+	d.Set("running", ioRunning != "No" && sqlRunning != "No")
+
+	return nil
+}
+
+func DeleteRDSReplication(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if diags := execRDSProcedure(ctx, db, "error stopping replication", "CALL mysql.rds_stop_replication"); diags != nil {
+		return diags
+	}
+	if diags := execRDSProcedure(ctx, db, "error resetting external source", "CALL mysql.rds_reset_external_master"); diags != nil {
+		return diags
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// getReplicaStatus returns the row of SHOW REPLICA STATUS by lowercased column, or nil when
+// the server isn't a replica.
+func getReplicaStatus(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	query := "SHOW SLAVE STATUS"
+	if flavor, flavorVersion, err := detectFlavor(ctx, db); err == nil && flavorSupports(flavor, flavorVersion, capabilityReplicaStatus) == nil {
+		query = "SHOW REPLICA STATUS"
+	}
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error reading replica status: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var status map[string]string
+	for rows.Next() {
+		if status, err = scanRowMap(rows, columns); err != nil {
+			return nil, fmt.Errorf("error scanning replica status: %w", err)
+		}
+	}
+	return status, rows.Err()
+}
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRDSReplication_positionRequired(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_rds_replication" "test" {
+	host     = "source.example.com"
+	user     = "repl"
+	password = "secret"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("either binlog_file and binlog_position, or auto_position must be set"),
+			},
+		},
+	})
+}
+
+func TestAccRDSReplication_autoPosition(t *testing.T) {
+	host := os.Getenv("MYSQL_RDS_REPLICATION_SOURCE")
+	resourceName := "mysql_rds_replication.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotRds(t)
+			if host == "" {
+				t.Skip("MYSQL_RDS_REPLICATION_SOURCE must be set to a GTID enabled source with a repl user to run this test")
+			}
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRDSReplicationConfig(host, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", fmt.Sprintf("%s:3306", host)),
+					resource.TestCheckResourceAttr(resourceName, "running", "true"),
+				),
+			},
+			{
+				Config: testAccRDSReplicationConfig(host, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "running", "false"),
+					resource.TestCheckResourceAttr(resourceName, "io_running", "No"),
+				),
+			},
+		},
+	})
+}
+
+func testAccRDSReplicationConfig(host string, running bool) string {
+	return fmt.Sprintf(`
+resource "mysql_rds_replication" "test" {
+	host          = "%s"
+	user          = "repl"
+	password      = "%s"
+	auto_position = true
+	running       = %t
+}
+`, host, os.Getenv("MYSQL_RDS_REPLICATION_PASSWORD"), running)
+}
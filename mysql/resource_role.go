@@ -2,29 +2,96 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceRole() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateRole,
 		ReadContext:   ReadRole,
+		UpdateContext: schema.NoopContext,
 		DeleteContext: DeleteRole,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportRole,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateUserName,
+			},
+
+			"host": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "%",
+				ValidateFunc: validateUserHost,
+			},
+
+			"on_exists": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "fail",
+				ValidateFunc: validation.StringInSlice([]string{"fail", "adopt"}, false),
+			},
+
+			"fail_if_granted": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 		},
 	}
 }
 
+// parseRoleReference parses a role as referenced by grants and default roles: either a
+// bare name, which MySQL resolves to the role with host '%', or name@host.
+func parseRoleReference(role string) UserOrRole {
+	// Hosts can't contain @, but names can.
+	if i := strings.LastIndex(role, "@"); i >= 0 {
+		return UserOrRole{Name: role[:i], Host: role[i+1:]}
+	}
+	return UserOrRole{Name: role}
+}
+
+// validateRoleReference checks a role given as name or name@host with the limits of account names.
+func validateRoleReference(val any, key string) (warns []string, errs []error) {
+	role := parseRoleReference(val.(string))
+	warns, errs = validateUserName(role.Name, key)
+	if role.Host != "" {
+		hostWarns, hostErrs := validateUserHost(role.Host, key)
+		warns, errs = append(warns, hostWarns...), append(errs, hostErrs...)
+	}
+	return
+}
+
+// roleReference is the inverse of parseRoleReference and also serves as the ID of mysql_role.
+func roleReference(name, host string) string {
+	if host == "" || host == "%" {
+		return name
+	}
+	return fmt.Sprintf("%s@%s", name, host)
+}
+
+func roleSQLStrings(roles []string) []string {
+	sqlStrings := make([]string, len(roles))
+	for i, role := range roles {
+		sqlStrings[i] = parseRoleReference(role).SQLString()
+	}
+	return sqlStrings
+}
+
 func CreateRole(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -32,16 +99,38 @@ func CreateRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	}
 
 	roleName := d.Get("name").(string)
+	roleHost := d.Get("host").(string)
+
+	role := parseRoleReference(roleReference(roleName, roleHost))
+	objectLocks.Lock(accountLockKey(role))
+	defer objectLocks.Unlock(accountLockKey(role))
+
+	createObj := "ROLE"
+	if d.Get("on_exists").(string) == "adopt" {
+		createObj = "ROLE IF NOT EXISTS"
+	}
 
-	sql := fmt.Sprintf("CREATE ROLE '%s'", roleName)
+	// Roles with the default host are created without one, as MariaDB roles can't have a host.
+	sql := fmt.Sprintf("CREATE %s %s", createObj, role.SQLString())
 	log.Printf("[DEBUG] SQL: %s", sql)
 
 	_, err = db.ExecContext(ctx, sql)
 	if err != nil {
-		return diag.Errorf("error creating role: %s", err)
+		return sqlErrorDiag("error creating role", sql, err)
+	}
+
+	if createObj != "ROLE" {
+		// IF NOT EXISTS also succeeds when a user has the name, which must not be adopted.
+		exists, err := roleExists(ctx, db, roleName, roleHost)
+		if err != nil {
+			return diag.Errorf("error reading role %s: %s", roleName, err)
+		}
+		if !exists {
+			return diag.Errorf("cannot adopt %s: the account exists but is not a role", roleReference(roleName, roleHost))
+		}
 	}
 
-	d.SetId(roleName)
+	d.SetId(roleReference(roleName, roleHost))
 
 	return nil
 }
@@ -52,34 +141,152 @@ func ReadRole(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 		return diag.FromErr(err)
 	}
 
-	sql := fmt.Sprintf("SHOW GRANTS FOR '%s'", d.Id())
-	log.Printf("[DEBUG] SQL: %s", sql)
+	role := parseRoleReference(d.Id())
+	if role.Host == "" {
+		role.Host = "%"
+	}
 
-	_, err = db.ExecContext(ctx, sql)
+	exists, err := roleExists(ctx, db, role.Name, role.Host)
 	if err != nil {
+		return diag.Errorf("error reading role %s: %s", d.Id(), err)
+	}
+	if !exists {
 		log.Printf("[WARN] Role (%s) not found; removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	d.Set("name", d.Id())
+	d.Set("name", role.Name)
+	d.Set("host", role.Host)
 
 	return nil
 }
 
+// roleExists looks the role up in mysql.user. SHOW GRANTS also succeeds for users, so
+// roles are told apart from them: MariaDB flags them with is_role, while MySQL and TiDB
+// create them as locked accounts without an authentication string.
+func roleExists(ctx context.Context, db *sql.DB, name, host string) (bool, error) {
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	var stmtSQL string
+	var args []interface{}
+	if isMariaDB {
+		// MariaDB roles have no host part.
+		stmtSQL = "SELECT COUNT(*) FROM mysql.user WHERE User = ? AND is_role = 'Y'"
+		args = []interface{}{name}
+	} else {
+		stmtSQL = "SELECT COUNT(*) FROM mysql.user WHERE User = ? AND Host = ? AND account_locked = 'Y' AND authentication_string = ''"
+		args = []interface{}{name, host}
+	}
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+
+	var count int
+	if err := db.QueryRowContext(ctx, stmtSQL, args...).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// roleGrantees returns the accounts the role is granted to according to mysql.role_edges,
+// or mysql.roles_mapping on MariaDB, leaving out the provider's own grant as its creator.
+func roleGrantees(ctx context.Context, db *sql.DB, name, host string) ([]string, error) {
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var stmtSQL string
+	var args []interface{}
+	if isMariaDB {
+		// CREATE ROLE grants the role WITH ADMIN OPTION to the account creating it, which isn't
+		// a grantee to revoke it from.
+		stmtSQL = `SELECT User, Host FROM mysql.roles_mapping WHERE Role = ?
+			AND NOT (Admin_option = 'Y' AND CONCAT(User, '@', Host) = CURRENT_USER())
+			ORDER BY User, Host`
+		args = []interface{}{name}
+	} else {
+		stmtSQL = "SELECT TO_USER, TO_HOST FROM mysql.role_edges WHERE FROM_USER = ? AND FROM_HOST = ? ORDER BY TO_USER, TO_HOST"
+		args = []interface{}{name, host}
+	}
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grantees := make([]string, 0)
+	for rows.Next() {
+		var user, userHost string
+		if err := rows.Scan(&user, &userHost); err != nil {
+			return nil, err
+		}
+		grantees = append(grantees, fmt.Sprintf("%s@%s", user, userHost))
+	}
+
+	return grantees, rows.Err()
+}
+
 func DeleteRole(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	sql := fmt.Sprintf("DROP ROLE '%s'", d.Get("name").(string))
+	role := parseRoleReference(roleReference(d.Get("name").(string), d.Get("host").(string)))
+	objectLocks.Lock(accountLockKey(role))
+	defer objectLocks.Unlock(accountLockKey(role))
+
+	if d.Get("fail_if_granted").(bool) {
+		grantees, err := roleGrantees(ctx, db, d.Get("name").(string), d.Get("host").(string))
+		if err != nil {
+			return diag.Errorf("error reading grantees of role %s: %s", d.Id(), err)
+		}
+		if len(grantees) > 0 {
+			return diag.Errorf("role %s is still granted to %s; revoke it first or set fail_if_granted to false", d.Id(), strings.Join(grantees, ", "))
+		}
+	}
+
+	sql := fmt.Sprintf("DROP ROLE %s", role.SQLString())
 	log.Printf("[DEBUG] SQL: %s", sql)
 
 	_, err = db.ExecContext(ctx, sql)
 	if err != nil {
-		return diag.FromErr(err)
+		return sqlErrorDiag("error dropping role", sql, err)
 	}
 
 	return nil
 }
+
+func ImportRole(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	role := parseRoleReference(d.Id())
+	if role.Host == "" {
+		role.Host = "%"
+	}
+
+	exists, err := roleExists(ctx, db, role.Name, role.Host)
+	if err != nil {
+		return nil, fmt.Errorf("error reading role %s: %w", d.Id(), err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("role %s not found", d.Id())
+	}
+
+	d.SetId(roleReference(role.Name, role.Host))
+	d.Set("on_exists", "fail")
+	d.Set("fail_if_granted", false)
+	d.Set("name", role.Name)
+	d.Set("host", role.Host)
+
+	return []*schema.ResourceData{d}, nil
+}
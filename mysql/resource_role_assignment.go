@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceRoleAssignment() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateRoleAssignment,
+		ReadContext:   ReadRoleAssignment,
+		DeleteContext: DeleteRoleAssignment,
+		Timeouts:      defaultResourceTimeouts(false),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportRoleAssignment,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateUserName,
+			},
+
+			"host": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "localhost",
+				ValidateFunc: validateUserHost,
+			},
+
+			"role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRoleReference,
+			},
+
+			"admin_option": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func roleAssignmentID(user, host, role string) string {
+	return fmt.Sprintf("%s@%s@%s", user, host, role)
+}
+
+func CreateRoleAssignment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := checkDefaultRolesSupport(ctx, meta); err != nil {
+		return diag.Errorf("cannot use role assignments: %v", err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	role := d.Get("role").(string)
+
+	objectLocks.Lock(accountLockKey(UserOrRole{Name: user, Host: host}))
+	defer objectLocks.Unlock(accountLockKey(UserOrRole{Name: user, Host: host}))
+
+	stmtSQL := fmt.Sprintf("GRANT %s TO %s", parseRoleReference(role).SQLString(), UserOrRole{Name: user, Host: host}.SQLString())
+	if d.Get("admin_option").(bool) {
+		stmtSQL += " WITH ADMIN OPTION"
+	}
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("failed granting role %s to %s@%s", role, user, host), stmtSQL, err)
+	}
+
+	d.SetId(roleAssignmentID(user, host, role))
+
+	return ReadRoleAssignment(ctx, d, meta)
+}
+
+func ReadRoleAssignment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	role := d.Get("role").(string)
+
+	adminOption, err := readRoleAssignment(ctx, db, user, host, role)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Printf("[WARN] Role assignment (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("failed reading role assignment %s: %v", d.Id(), err)
+	}
+
+	d.Set("admin_option", adminOption)
+
+	return nil
+}
+
+// readRoleAssignment returns whether the role is granted with ADMIN OPTION, or sql.ErrNoRows
+// if it isn't granted to the user.
+func readRoleAssignment(ctx context.Context, db *sql.DB, user, host, role string) (bool, error) {
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	parsed := parseRoleReference(role)
+	var stmtSQL string
+	var args []interface{}
+	if isMariaDB {
+		stmtSQL = "SELECT Admin_option FROM mysql.roles_mapping WHERE User = ? AND Host = ? AND Role = ?"
+		args = []interface{}{user, host, parsed.Name}
+	} else {
+		if parsed.Host == "" {
+			parsed.Host = "%"
+		}
+		stmtSQL = "SELECT WITH_ADMIN_OPTION FROM mysql.role_edges WHERE TO_USER = ? AND TO_HOST = ? AND FROM_USER = ? AND FROM_HOST = ?"
+		args = []interface{}{user, host, parsed.Name, parsed.Host}
+	}
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var adminOption string
+	if err := db.QueryRowContext(ctx, stmtSQL, args...).Scan(&adminOption); err != nil {
+		return false, err
+	}
+
+	return adminOption == "Y", nil
+}
+
+func DeleteRoleAssignment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	role := d.Get("role").(string)
+
+	objectLocks.Lock(accountLockKey(UserOrRole{Name: user, Host: host}))
+	defer objectLocks.Unlock(accountLockKey(UserOrRole{Name: user, Host: host}))
+
+	stmtSQL := fmt.Sprintf("REVOKE %s FROM %s", parseRoleReference(role).SQLString(), UserOrRole{Name: user, Host: host}.SQLString())
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("failed revoking role %s from %s@%s", role, user, host), stmtSQL, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportRoleAssignment(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "@", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST@ROLE)", d.Id())
+	}
+
+	d.Set("user", parts[0])
+	d.Set("host", parts[1])
+	d.Set("role", parts[2])
+
+	readDiags := ReadRoleAssignment(ctx, d, meta)
+	for _, readDiag := range readDiags {
+		if readDiag.Severity == diag.Error {
+			return nil, fmt.Errorf("failed to read role assignment: %s", readDiag.Summary)
+		}
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("role %s is not granted to %s@%s", parts[2], parts[0], parts[1])
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
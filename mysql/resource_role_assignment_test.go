@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRoleAssignment_basic(t *testing.T) {
+	resourceName := "mysql_role_assignment.role1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRoleAssignmentCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleAssignmentConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoleAssignmentExists(resourceName),
+					testAccRoleAssignmentExists("mysql_role_assignment.role2"),
+					resource.TestCheckResourceAttr(resourceName, "id", "jdoe-roles@%@tf-test-role1"),
+					resource.TestCheckResourceAttr(resourceName, "admin_option", "false"),
+				),
+			},
+			{
+				Config: testAccRoleAssignmentConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoleAssignmentExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "admin_option", "true"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccRoleAssignmentExists(rn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		_, err = readRoleAssignment(ctx, db, rs.Primary.Attributes["user"], rs.Primary.Attributes["host"], rs.Primary.Attributes["role"])
+		if err != nil {
+			return fmt.Errorf("error reading role assignment %s: %w", rs.Primary.ID, err)
+		}
+
+		return nil
+	}
+}
+
+func testAccRoleAssignmentCheckDestroy(s *terraform.State) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mysql_role_assignment" {
+			continue
+		}
+
+		_, err := readRoleAssignment(ctx, db, rs.Primary.Attributes["user"], rs.Primary.Attributes["host"], rs.Primary.Attributes["role"])
+		if err == nil {
+			return fmt.Errorf("role assignment %s still exists after destroy", rs.Primary.ID)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+	return nil
+}
+
+func testAccRoleAssignmentConfig(adminOption bool) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "role1" {
+  name = "tf-test-role1"
+}
+
+resource "mysql_role" "role2" {
+  name = "tf-test-role2"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe-roles"
+  host = "%%"
+}
+
+resource "mysql_role_assignment" "role1" {
+  user         = mysql_user.test.user
+  host         = mysql_user.test.host
+  role         = mysql_role.role1.id
+  admin_option = %t
+}
+
+resource "mysql_role_assignment" "role2" {
+  user = mysql_user.test.user
+  host = mysql_user.test.host
+  role = mysql_role.role2.id
+}
+`, adminOption)
+}
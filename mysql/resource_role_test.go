@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -26,7 +29,7 @@ func TestAccRole_basic(t *testing.T) {
 			}
 
 			requiredVersion, _ := version.NewVersion("8.0.0")
-			currentVersion, err := serverVersion(db)
+			currentVersion, err := serverVersion(ctx, db)
 			if err != nil {
 				return
 			}
@@ -45,10 +48,273 @@ func TestAccRole_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "name", roleName),
 				),
 			},
+			{
+				// A role dropped out of band is recreated.
+				PreConfig: func() {
+					testAccSqlExec(t, fmt.Sprintf("DROP ROLE '%s'", roleName))
+				},
+				Config: testAccRoleConfigBasic(roleName),
+				Check:  testAccRoleExists(roleName),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "tf-test-not-a-role",
+				ExpectError:   regexp.MustCompile("role tf-test-not-a-role not found"),
+			},
+		},
+	})
+}
+
+func TestAccRole_host(t *testing.T) {
+	resourceName := "mysql_role.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			ctx := context.Background()
+			db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+			if err != nil {
+				return err
+			}
+
+			exists, err := roleExists(ctx, db, "tf-test-role", "localhost")
+			if err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("role tf-test-role@localhost still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleConfigHost("tf-test-role", "localhost"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "tf-test-role@localhost"),
+					resource.TestCheckResourceAttr(resourceName, "host", "localhost"),
+					resource.TestCheckResourceAttr("mysql_grant.test", "roles.#", "1"),
+					testAccDefaultRoles("mysql_default_roles.test", "tf-test-role"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
 
+func TestAccRole_adopt(t *testing.T) {
+	roleName := "tf-test-role"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRoleCheckDestroy(roleName),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE ROLE '%s'", roleName)); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccRoleConfigOnExists(roleName, "adopt"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoleExists(roleName),
+					resource.TestCheckResourceAttr("mysql_role.test", "name", roleName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRole_failIfGranted(t *testing.T) {
+	roleName := "tf-test-role"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			ctx := context.Background()
+			db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+			if err != nil {
+				return err
+			}
+			if _, err := db.ExecContext(ctx, "DROP USER IF EXISTS 'jdoe'@'%'"); err != nil {
+				return err
+			}
+			return testAccRoleCheckDestroy(roleName)(s)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleConfigFailIfGranted(roleName, true),
+			},
+			{
+				// The grant is made outside of Terraform, so destroy doesn't revoke it first.
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, stmt := range []string{
+						"CREATE USER 'jdoe'@'%'",
+						fmt.Sprintf("GRANT '%s' TO 'jdoe'@'%%'", roleName),
+					} {
+						if _, err := db.ExecContext(ctx, stmt); err != nil {
+							t.Fatal(err)
+						}
+					}
+				},
+				Config:      testAccRoleConfigFailIfGranted(roleName, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("role tf-test-role is still granted to jdoe@%"),
+			},
+			{
+				Config: testAccRoleConfigFailIfGranted(roleName, false),
+			},
+		},
+	})
+}
+
+func TestAccRole_failIfGrantedMariaDB(t *testing.T) {
+	roleName := "tf-test-role"
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotMariaDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		// The role is only granted to the provider's account, which created it, so it's dropped.
+		CheckDestroy: testAccRoleCheckDestroy(roleName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleConfigFailIfGranted(roleName, true),
+			},
+		},
+	})
+}
+
+func TestParseRoleReference(t *testing.T) {
+	cases := []struct {
+		reference string
+		role      UserOrRole
+		sql       string
+	}{
+		{"developer", UserOrRole{Name: "developer"}, "'developer'"},
+		{"developer@localhost", UserOrRole{Name: "developer", Host: "localhost"}, "'developer'@'localhost'"},
+	}
+
+	for _, c := range cases {
+		role := parseRoleReference(c.reference)
+		if role != c.role {
+			t.Errorf("parseRoleReference(%q) = %#v, expected %#v", c.reference, role, c.role)
+		}
+		if got := role.SQLString(); got != c.sql {
+			t.Errorf("SQLString() of %q = %q, expected %q", c.reference, got, c.sql)
+		}
+		if got := roleReference(role.Name, role.Host); got != c.reference {
+			t.Errorf("roleReference(%q, %q) = %q, expected %q", role.Name, role.Host, got, c.reference)
+		}
+	}
+
+	if got := roleReference("developer", "%"); got != "developer" {
+		t.Errorf("roleReference with host %% = %q, expected a bare name", got)
+	}
+}
+
+func TestParseGrantFromRowRolesWithHost(t *testing.T) {
+	grant, err := parseGrantFromRow("GRANT `developer`@`%`,`local`@`localhost` TO `jdoe`@`%`")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roleGrant, ok := grant.(*RoleGrant)
+	if !ok {
+		t.Fatalf("expected a role grant, got %T", grant)
+	}
+	if expected := []string{"developer", "local@localhost"}; !reflect.DeepEqual(roleGrant.Roles, expected) {
+		t.Errorf("roles = %v, expected %v", roleGrant.Roles, expected)
+	}
+	if expected := "GRANT 'developer', 'local'@'localhost' TO 'jdoe'@'%'"; roleGrant.SQLGrantStatement() != expected {
+		t.Errorf("grant statement = %q, expected %q", roleGrant.SQLGrantStatement(), expected)
+	}
+}
+
+func TestParseGrantFromRowMariaDBRoles(t *testing.T) {
+	grant, err := parseGrantFromRow("GRANT `dev,ops` TO `jdoe`@`%` WITH ADMIN OPTION")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roleGrant, ok := grant.(*RoleGrant)
+	if !ok {
+		t.Fatalf("expected a role grant, got %T", grant)
+	}
+	if expected := []string{"dev,ops"}; !reflect.DeepEqual(roleGrant.Roles, expected) {
+		t.Errorf("roles = %v, expected %v", roleGrant.Roles, expected)
+	}
+	if expected := (UserOrRole{Name: "jdoe", Host: "%"}); roleGrant.UserOrRole != expected || !roleGrant.Grant {
+		t.Errorf("grantee = %v with admin option %t, expected %v with admin option", roleGrant.UserOrRole, roleGrant.Grant, expected)
+	}
+
+	grant, err = parseGrantFromRow("SET DEFAULT ROLE `dev` FOR `jdoe`@`%`")
+	if err != nil || grant != nil {
+		t.Errorf("expected SET DEFAULT ROLE to be skipped, got %v, %v", grant, err)
+	}
+}
+
+func TestGrantsForFlavorMariaDB(t *testing.T) {
+	grant := &RoleGrant{
+		Roles:      []string{"dev", "ops"},
+		Grant:      true,
+		UserOrRole: UserOrRole{Name: "jdoe", Host: "%"},
+		TLSOption:  "SSL",
+	}
+
+	if grants := grantsForFlavor(grant, flavorMySQL); len(grants) != 1 {
+		t.Errorf("expected a single grant on MySQL, got %d", len(grants))
+	}
+
+	var statements []string
+	for _, g := range grantsForFlavor(grant, flavorMariaDB) {
+		statements = append(statements, g.SQLGrantStatement(), g.SQLRevokeStatement())
+	}
+	expected := []string{
+		"GRANT 'dev' TO 'jdoe'@'%' WITH ADMIN OPTION",
+		"REVOKE 'dev' FROM 'jdoe'@'%'",
+		"GRANT 'ops' TO 'jdoe'@'%' WITH ADMIN OPTION",
+		"REVOKE 'ops' FROM 'jdoe'@'%'",
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("statements = %q, expected %q", statements, expected)
+	}
+}
+
 func testAccRoleExists(roleName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		ctx := context.Background()
@@ -104,6 +370,51 @@ func testAccRoleCheckDestroy(roleName string) resource.TestCheckFunc {
 	}
 }
 
+func testAccRoleConfigHost(roleName, host string) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = "%s"
+  host = "%s"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe"
+  host = "%%"
+}
+
+resource "mysql_grant" "test" {
+  user     = mysql_user.test.user
+  host     = mysql_user.test.host
+  database = ""
+  roles    = [mysql_role.test.id]
+}
+
+resource "mysql_default_roles" "test" {
+  user  = mysql_user.test.user
+  host  = mysql_user.test.host
+  roles = mysql_grant.test.roles
+}
+`, roleName, host)
+}
+
+func testAccRoleConfigOnExists(roleName, onExists string) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name      = "%s"
+  on_exists = "%s"
+}
+`, roleName, onExists)
+}
+
+func testAccRoleConfigFailIfGranted(roleName string, failIfGranted bool) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name            = "%s"
+  fail_if_granted = %t
+}
+`, roleName, failIfGranted)
+}
+
 func testAccRoleConfigBasic(roleName string) string {
 	return fmt.Sprintf(`
 resource "mysql_role" "test" {
@@ -111,3 +422,19 @@ resource "mysql_role" "test" {
 }
 `, roleName)
 }
+
+func TestValidateRoleReference(t *testing.T) {
+	valid := []string{"reader", "reader@localhost", "reader@%", "team@example.com@10.0.%", "o'brien", "tick`@localhost"}
+	invalid := []string{"", "@localhost", strings.Repeat("r", 33), "reader@bad host", "new\nline"}
+
+	for _, v := range valid {
+		if _, errs := validateRoleReference(v, "role"); len(errs) != 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range invalid {
+		if _, errs := validateRoleReference(v, "role"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
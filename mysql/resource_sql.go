@@ -2,8 +2,15 @@ package mysql
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"os"
+	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -12,7 +19,13 @@ func resourceSql() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateSql,
 		ReadContext:   ReadSql,
+		UpdateContext: UpdateSql,
 		DeleteContext: DeleteSql,
+		Timeouts:      defaultResourceTimeouts(true),
+		CustomizeDiff: customizeDiffSql,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportSql,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -21,32 +34,455 @@ func resourceSql() *schema.Resource {
 				ForceNew: true,
 			},
 			"create_sql": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"create_sql", "create_sql_file"},
+			},
+			"create_sql_file": {
 				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Optional: true,
 			},
 			"delete_sql": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"delete_sql", "delete_sql_file"},
+			},
+			"delete_sql_file": {
 				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Optional: true,
+			},
+			"variables": {
+				Type:      schema.TypeMap,
+				Optional:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"only_if": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"multi_statement": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"rows_affected": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"result": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"source_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"update_sql": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"read_sql": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"expected_result": {
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 		},
 	}
 }
 
+// sqlUpdateKeys are the attributes whose change runs update_sql, or replaces the resource
+// without it.
+var sqlUpdateKeys = []string{"create_sql", "create_sql_file", "source_hash", "update_sql", "expected_result", "triggers", "variables"}
+
+// customizeDiffSql replaces the resource on changes unless update_sql is set, which is
+// executed in place instead.
+func customizeDiffSql(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	adopting := d.Id() != "" && sqlImported(d.GetChange)
+	if adopting {
+		if err := checkImportedSqlHash(d); err != nil {
+			return err
+		}
+	}
+
+	if d.NewValueKnown("create_sql_file") && d.NewValueKnown("delete_sql_file") {
+		hash, err := sqlSourceHash(d.Get("create_sql_file").(string), d.Get("delete_sql_file").(string))
+		if err != nil {
+			return err
+		}
+		if hash != d.Get("source_hash").(string) {
+			if err := d.SetNew("source_hash", hash); err != nil {
+				return err
+			}
+		}
+	} else {
+		if err := d.SetNewComputed("source_hash"); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() == "" || adopting {
+		return nil
+	}
+	if d.Get("update_sql").(string) != "" {
+		if d.HasChanges(sqlUpdateKeys...) {
+			return d.SetNewComputed("rows_affected")
+		}
+		return nil
+	}
+
+	for _, key := range []string{"create_sql", "create_sql_file", "delete_sql", "delete_sql_file", "source_hash", "expected_result", "triggers", "variables"} {
+		if d.HasChange(key) {
+			if err := d.ForceNew(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sqlImported returns whether the resource was imported and its statements aren't in the state
+// yet, which one of create_sql and create_sql_file always is otherwise. The configured statements
+// are then adopted in place instead of replacing the resource or running update_sql.
+func sqlImported(getChange func(string) (interface{}, interface{})) bool {
+	oldCreateSql, _ := getChange("create_sql")
+	oldCreateSqlFile, _ := getChange("create_sql_file")
+	return oldCreateSql.(string) == "" && oldCreateSqlFile.(string) == ""
+}
+
+// sqlStatementHash returns the hash of a create statement given on import.
+func sqlStatementHash(statement string) string {
+	sum := sha256.Sum256([]byte(statement))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkImportedSqlHash checks that the configured create statement is the one whose hash was
+// given on import, if one was.
+func checkImportedSqlHash(d *schema.ResourceDiff) error {
+	expected, _ := d.GetChange("source_hash")
+	if expected.(string) == "" || !d.NewValueKnown("create_sql") || !d.NewValueKnown("create_sql_file") {
+		return nil
+	}
+
+	statement := d.Get("create_sql").(string)
+	if file := d.Get("create_sql_file").(string); file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed reading create_sql_file: %w", err)
+		}
+		statement = string(content)
+	}
+	if hash := sqlStatementHash(statement); hash != expected.(string) {
+		return fmt.Errorf("the create statement of %s has hash %s, but it was imported with %s", d.Id(), hash, expected)
+	}
+	return nil
+}
+
+// sqlSourceHash returns a hash of the contents of the SQL files, so editing a file causes a diff.
+func sqlSourceHash(createSqlFile, deleteSqlFile string) (string, error) {
+	if createSqlFile == "" && deleteSqlFile == "" {
+		return "", nil
+	}
+
+	h := sha256.New()
+	for _, file := range []string{createSqlFile, deleteSqlFile} {
+		if file == "" {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed reading SQL file: %w", err)
+		}
+		h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// getSqlFromData returns the SQL of key, read from the file in key_file if that's set instead.
+func getSqlFromData(d *schema.ResourceData, key string) (string, error) {
+	if file := d.Get(key + "_file").(string); file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed reading %s_file: %w", key, err)
+		}
+		return string(content), nil
+	}
+	return d.Get(key).(string), nil
+}
+
+// execSql executes sqlText as a single statement, or with multi_statement as a list of statements
+// within one transaction, which is rolled back when a statement fails. It returns the total number
+// of affected rows and the first row of the last statement returning rows, by column name.
+func execSql(ctx context.Context, db *sql.DB, d *schema.ResourceData, sqlText string) (int64, map[string]string, error) {
+	variables := d.Get("variables").(map[string]interface{})
+
+	if !d.Get("multi_statement").(bool) {
+		stmt, args, err := bindSqlVariables(sqlText, variables)
+		if err != nil {
+			return 0, nil, err
+		}
+		return execSqlStatement(ctx, db, stmt, args)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed starting transaction: %w", err)
+	}
+
+	var rowsAffected int64
+	var result map[string]string
+	for i, stmt := range splitSqlStatements(sqlText) {
+		log.Println("[DEBUG] Executing statement:", stmt)
+		stmt, args, err := bindSqlVariables(stmt, variables)
+		if err == nil {
+			var n int64
+			var row map[string]string
+			n, row, err = execSqlStatement(ctx, tx, stmt, args)
+			rowsAffected += n
+			if row != nil {
+				result = row
+			}
+		}
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.Printf("[WARN] failed rolling back transaction: %v", rollbackErr)
+			}
+			return 0, nil, fmt.Errorf("statement %d failed, transaction rolled back: %w", i+1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("failed committing transaction: %w", err)
+	}
+	return rowsAffected, result, nil
+}
+
+// sqlExecQueryer is implemented by both *sql.DB and *sql.Tx.
+type sqlExecQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// execSqlStatement executes a statement, querying it instead when it may return rows, like reads
+// and procedure calls, so the first row can be returned. Rows which are read don't count as
+// affected.
+func execSqlStatement(ctx context.Context, q sqlExecQueryer, stmt string, args []interface{}) (int64, map[string]string, error) {
+	if !mayReturnRows(stmt) {
+		result, err := q.ExecContext(ctx, stmt, args...)
+		if err != nil {
+			return 0, nil, err
+		}
+		n, err := result.RowsAffected()
+		return n, nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	row := map[string]string{}
+	if rows.Next() {
+		_, row, err = scanSqlRow(rows)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return 0, row, rows.Err()
+}
+
+// scanSqlRow scans the current row into its values and a map by column name. NULL columns are
+// left out of the map, as map values can't be null.
+func scanSqlRow(rows *sql.Rows) ([]sql.NullString, map[string]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, nil, err
+	}
+
+	row := make(map[string]string, len(columns))
+	for i, column := range columns {
+		if values[i].Valid {
+			row[column] = values[i].String
+		}
+	}
+	return values, row, nil
+}
+
+// splitSqlStatements splits sqlText on semicolons outside of quotes and comments, which are
+// kept as they may be optimizer hints or version-specific code. Empty statements are dropped.
+func splitSqlStatements(sqlText string) []string {
+	var statements []string
+	var current strings.Builder
+
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sqlText); i++ {
+		if end := sqlSkipEnd(sqlText, i); end > i {
+			current.WriteString(sqlText[i:end])
+			i = end - 1
+		} else if sqlText[i] == ';' {
+			flush()
+		} else {
+			current.WriteByte(sqlText[i])
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// sqlSkipEnd returns the end of the quoted string or comment starting at i of sqlText, or i if
+// none starts there. Unterminated ones end at the end of sqlText.
+func sqlSkipEnd(sqlText string, i int) int {
+	switch c := sqlText[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		for j := i + 1; j < len(sqlText); j++ {
+			if sqlText[j] == '\\' && c != '`' {
+				j++
+			} else if sqlText[j] == c {
+				return j + 1
+			}
+		}
+		return len(sqlText)
+	case c == '#' || isSqlLineComment(sqlText[i:]):
+		if end := strings.IndexByte(sqlText[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(sqlText)
+	case strings.HasPrefix(sqlText[i:], "/*"):
+		if end := strings.Index(sqlText[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(sqlText)
+	}
+	return i
+}
+
+// isSqlLineComment reports whether s starts with "-- ", which MySQL requires to be followed by
+// whitespace or the end of the input.
+func isSqlLineComment(s string) bool {
+	if !strings.HasPrefix(s, "--") {
+		return false
+	}
+	return len(s) == 2 || strings.ContainsRune(" \t\r\n", rune(s[2]))
+}
+
+// bindSqlVariables replaces the :name references of variables outside of quotes and comments with
+// placeholders, and returns the values to pass as query arguments. The driver escapes the values,
+// so they can't change the statement.
+func bindSqlVariables(sqlText string, variables map[string]interface{}) (string, []interface{}, error) {
+	if len(variables) == 0 {
+		return sqlText, nil, nil
+	}
+
+	var bound strings.Builder
+	var args []interface{}
+	for i := 0; i < len(sqlText); i++ {
+		if end := sqlSkipEnd(sqlText, i); end > i {
+			bound.WriteString(sqlText[i:end])
+			i = end - 1
+			continue
+		}
+
+		match := sqlVariableRe.FindString(sqlText[i:])
+		// A colon right after an identifier character isn't a reference, e.g. in @a:=1.
+		if match == "" || (i > 0 && isSqlIdentifierChar(sqlText[i-1])) {
+			bound.WriteByte(sqlText[i])
+			continue
+		}
+
+		value, ok := variables[match[1:]]
+		if !ok {
+			return "", nil, fmt.Errorf("variable %s is not set", match[1:])
+		}
+		bound.WriteByte('?')
+		args = append(args, value)
+		i += len(match) - 1
+	}
+	return bound.String(), args, nil
+}
+
+var sqlVariableRe = regexp.MustCompile(`^:[A-Za-z_][A-Za-z0-9_]*`)
+
+func isSqlIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c == '@' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// sqlConditionMet runs the only_if query, whose condition holds when it returns at least one
+// row. Without only_if the condition always holds.
+func sqlConditionMet(ctx context.Context, db *sql.DB, d *schema.ResourceData) (bool, error) {
+	onlyIf := d.Get("only_if").(string)
+	if onlyIf == "" {
+		return true, nil
+	}
+
+	log.Println("[DEBUG] Executing SQL:", onlyIf)
+
+	query, args, err := bindSqlVariables(onlyIf, d.Get("variables").(map[string]interface{}))
+	if err != nil {
+		return false, fmt.Errorf("failed to run only_if SQL: %w", err)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to run only_if SQL: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return true, nil
+	}
+	return false, rows.Err()
+}
+
 func CreateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
-	createSql := d.Get("create_sql").(string)
-
-	log.Println("[DEBUG] Executing SQL", createSql)
+	createSql, err := getSqlFromData(d, "create_sql")
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	_, err = db.ExecContext(ctx, createSql)
+	met, err := sqlConditionMet(ctx, db, d)
 	if err != nil {
-		return diag.Errorf("couldn't exec SQL: %v", err)
+		return diag.FromErr(err)
+	}
+	if met {
+		log.Println("[DEBUG] Executing SQL", createSql)
+
+		rowsAffected, result, err := execSql(ctx, db, d, createSql)
+		if err != nil {
+			return sqlErrorDiag("couldn't exec SQL", createSql, err)
+		}
+		d.Set("rows_affected", int(rowsAffected))
+		setSqlResult(d, result)
+	} else {
+		log.Printf("[INFO] only_if SQL of %s returned no rows; skipping create SQL", name)
 	}
 
 	d.SetId(name)
@@ -54,7 +490,89 @@ func CreateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	return nil
 }
 
+func UpdateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// A changed delete_sql only needs to be stored for the eventual destroy, and the statements
+	// of an imported resource only need to be adopted.
+	updateSql := d.Get("update_sql").(string)
+	if updateSql == "" || !d.HasChanges(sqlUpdateKeys...) || sqlImported(d.GetChange) {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Println("[DEBUG] Executing SQL:", updateSql)
+
+	rowsAffected, result, err := execSql(ctx, db, d, updateSql)
+	if err != nil {
+		return sqlErrorDiag("failed to run update SQL", updateSql, err)
+	}
+	d.Set("rows_affected", int(rowsAffected))
+	setSqlResult(d, result)
+
+	return nil
+}
+
+// setSqlResult sets result to the first row returned by the executed statements, unless read_sql
+// is set, whose result is kept up to date on each refresh instead.
+func setSqlResult(d *schema.ResourceData, result map[string]string) {
+	if d.Get("read_sql").(string) == "" {
+		d.Set("result", result)
+	}
+}
+
+// ReadSql detects drift with read_sql: no rows means the object is gone, and a first column
+// of the first row other than expected_result shows up as a change to expected_result.
 func ReadSql(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	readSql := d.Get("read_sql").(string)
+	if readSql == "" {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Println("[DEBUG] Executing SQL:", readSql)
+
+	query, args, err := bindSqlVariables(readSql, d.Get("variables").(map[string]interface{}))
+	if err != nil {
+		return diag.Errorf("failed to run read SQL: %v", err)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		if errNum := mysqlErrorNumber(err); errNum == unknownDatabaseErrCode || errNum == unknownTableErrCode {
+			// The object read_sql looks at was dropped along with its database or table.
+			log.Printf("[WARN] read SQL of %s failed with %v; removing from state", d.Id(), err)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("failed to run read SQL: %v", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return diag.Errorf("failed to run read SQL: %v", err)
+		}
+		log.Printf("[WARN] read SQL of %s returned no rows; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	values, result, err := scanSqlRow(rows)
+	if err != nil {
+		return diag.Errorf("failed scanning result of read SQL: %v", err)
+	}
+	d.Set("result", result)
+
+	if _, ok := d.GetOk("expected_result"); ok {
+		d.Set("expected_result", values[0].String)
+	}
+
 	return nil
 }
 
@@ -63,15 +581,46 @@ func DeleteSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	deleteSql := d.Get("delete_sql").(string)
-
-	log.Println("[DEBUG] Executing SQL:", deleteSql)
+	deleteSql, err := getSqlFromData(d, "delete_sql")
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	_, err = db.ExecContext(ctx, deleteSql)
+	met, err := sqlConditionMet(ctx, db, d)
 	if err != nil {
-		return diag.Errorf("failed to run delete SQL: %v", err)
+		return diag.FromErr(err)
+	}
+	if met {
+		log.Println("[DEBUG] Executing SQL:", deleteSql)
+
+		_, _, err = execSql(ctx, db, d, deleteSql)
+		if err != nil {
+			return sqlErrorDiag("failed to run delete SQL", deleteSql, err)
+		}
+	} else {
+		log.Printf("[INFO] only_if SQL of %s returned no rows; skipping delete SQL", d.Id())
 	}
 
 	d.SetId("")
 	return nil
 }
+
+// ImportSql imports a resource by its name, optionally followed by # and the SHA-256 hash of the
+// create statement, which the configuration must then match. Nothing is executed: the statements
+// in the configuration are adopted on the next apply.
+func ImportSql(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name, hash := d.Id(), ""
+	if i := strings.LastIndex(name, "#"); i >= 0 && kSqlStatementHashRegex.MatchString(name[i+1:]) {
+		name, hash = name[:i], strings.ToLower(name[i+1:])
+	}
+	if name == "" {
+		return nil, fmt.Errorf("wrong ID format %s (expected NAME or NAME#SHA256)", d.Id())
+	}
+
+	d.SetId(name)
+	d.Set("name", name)
+	d.Set("source_hash", hash)
+	return []*schema.ResourceData{d}, nil
+}
+
+var kSqlStatementHashRegex = regexp.MustCompile(`^[0-9A-Fa-f]{64}$`)
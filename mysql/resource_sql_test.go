@@ -0,0 +1,548 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSql_updateSql(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfigUpdateSql(1),
+				Check:  testAccSqlCheckTableCount(0),
+			},
+			{
+				// The table is altered by update_sql instead of being recreated, which would lose the row.
+				Config: testAccSqlConfigUpdateSql(2),
+				Check:  testAccSqlCheckTableCount(1),
+			},
+		},
+	})
+}
+
+func TestAccSql_readSql(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfigReadSql(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlCheckTableCount(1),
+					resource.TestCheckResourceAttr("mysql_sql.row", "rows_affected", "1"),
+					resource.TestCheckResourceAttr("mysql_sql.row", "result.%", "1"),
+					resource.TestCheckResourceAttr("mysql_sql.row", "result.id", "1"),
+				),
+			},
+			{
+				PreConfig: func() {
+					testAccSqlExec(t, "UPDATE tf_sql_test.t SET id = 2")
+				},
+				Config:             testAccSqlConfigReadSql(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig: func() {
+					testAccSqlExec(t, "DELETE FROM tf_sql_test.t")
+				},
+				Config:             testAccSqlConfigReadSql(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccSqlConfigReadSql(),
+				Check:  testAccSqlCheckTableCount(1),
+			},
+			{
+				// read_sql failing on the dropped table removes the table and row from state.
+				PreConfig: func() {
+					testAccSqlExec(t, "DROP TABLE tf_sql_test.t")
+				},
+				Config:             testAccSqlConfigReadSql(),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccSqlConfigReadSql(),
+				Check:  testAccSqlCheckTableCount(1),
+			},
+		},
+	})
+}
+
+func TestAccSql_files(t *testing.T) {
+	dir := t.TempDir()
+	createFile := filepath.Join(dir, "create.sql")
+	deleteFile := filepath.Join(dir, "delete.sql")
+	writeFile := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(createFile, "CREATE TABLE tf_sql_test.t (id INT PRIMARY KEY)")
+	writeFile(deleteFile, "DROP TABLE tf_sql_test.t")
+
+	var firstHash string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfigFiles(createFile, deleteFile),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlCheckTableCount(0),
+					resource.TestCheckResourceAttrWith("mysql_sql.table", "source_hash", func(value string) error {
+						firstHash = value
+						return nil
+					}),
+				),
+			},
+			{
+				// Editing the file replaces the resource, which recreates the table empty.
+				PreConfig: func() {
+					testAccSqlExec(t, "INSERT INTO tf_sql_test.t VALUES (1)")
+					writeFile(createFile, "CREATE TABLE tf_sql_test.t (id BIGINT PRIMARY KEY)")
+				},
+				Config: testAccSqlConfigFiles(createFile, deleteFile),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlCheckTableCount(0),
+					resource.TestCheckResourceAttrWith("mysql_sql.table", "source_hash", func(value string) error {
+						if value == firstHash {
+							return fmt.Errorf("source_hash didn't change")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSql_triggers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfigTriggers("1"),
+				Check:  testAccSqlCheckTableCount(0),
+			},
+			{
+				// The changed trigger replaces the table, which loses the row.
+				PreConfig: func() {
+					testAccSqlExec(t, "INSERT INTO tf_sql_test.t VALUES (1)")
+				},
+				Config: testAccSqlConfigTriggers("2"),
+				Check:  testAccSqlCheckTableCount(0),
+			},
+		},
+	})
+}
+
+func TestAccSql_onlyIf(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfigOnlyIf(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlCheckTableCount(1),
+					resource.TestCheckResourceAttr("mysql_sql.skipped", "id", "tf_sql_test_skipped"),
+				),
+			},
+		},
+	})
+}
+
+func TestSplitSqlStatements(t *testing.T) {
+	cases := []struct {
+		sql      string
+		expected []string
+	}{
+		{"SELECT 1", []string{"SELECT 1"}},
+		{"SELECT 1; SELECT 2;\n", []string{"SELECT 1", "SELECT 2"}},
+		{"INSERT INTO t VALUES ('a;b', \"c;d\", 'e\\';f'); SELECT `x;y` FROM t", []string{"INSERT INTO t VALUES ('a;b', \"c;d\", 'e\\';f')", "SELECT `x;y` FROM t"}},
+		{"SELECT 1 -- one; two\n; SELECT 2 # three;\n", []string{"SELECT 1 -- one; two", "SELECT 2 # three;"}},
+		{"SELECT /*+ MAX_EXECUTION_TIME(1); */ 1; SELECT 2--1", []string{"SELECT /*+ MAX_EXECUTION_TIME(1); */ 1", "SELECT 2--1"}},
+		{" ; ;", nil},
+	}
+
+	for _, c := range cases {
+		if got := splitSqlStatements(c.sql); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("splitSqlStatements(%q) = %q, expected %q", c.sql, got, c.expected)
+		}
+	}
+}
+
+func TestExecSqlStatementReturnsFirstRow(t *testing.T) {
+	ctx := context.Background()
+	statements := []string{
+		"-- the seeded id\nSELECT MAX(id) FROM app.t",
+		"/* the seeded id */ SELECT MAX(id) FROM app.t",
+		"WITH ids AS (SELECT id FROM app.t) SELECT MAX(id) FROM ids",
+	}
+	variables := map[string]string{}
+	for _, stmt := range statements {
+		variables[stmt] = "3"
+	}
+	db := sql.OpenDB(&variablesConnector{variables: variables})
+	defer db.Close()
+
+	for _, stmt := range statements {
+		_, row, err := execSqlStatement(ctx, db, stmt, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := map[string]string{"value": "3"}; !reflect.DeepEqual(row, expected) {
+			t.Errorf("execSqlStatement(%q) returned %v, expected %v", stmt, row, expected)
+		}
+	}
+}
+
+func TestBindSqlVariables(t *testing.T) {
+	variables := map[string]interface{}{"name": "o'brien", "pw": "secret"}
+	cases := []struct {
+		sql      string
+		expected string
+		args     []interface{}
+	}{
+		{"SELECT 1", "SELECT 1", nil},
+		{"CREATE USER :name IDENTIFIED BY :pw", "CREATE USER ? IDENTIFIED BY ?", []interface{}{"o'brien", "secret"}},
+		{"SELECT ':pw', `:pw` -- :pw\n, @a:=1, x:pw", "SELECT ':pw', `:pw` -- :pw\n, @a:=1, x:pw", nil},
+	}
+
+	for _, c := range cases {
+		got, args, err := bindSqlVariables(c.sql, variables)
+		if err != nil {
+			t.Fatalf("bindSqlVariables(%q) failed: %v", c.sql, err)
+		}
+		if got != c.expected || !reflect.DeepEqual(args, c.args) {
+			t.Errorf("bindSqlVariables(%q) = %q, %v, expected %q, %v", c.sql, got, args, c.expected, c.args)
+		}
+	}
+
+	if _, _, err := bindSqlVariables("SELECT :missing", variables); err == nil {
+		t.Error("expected an error for an unset variable")
+	}
+	if got, _, _ := bindSqlVariables("SELECT :missing", nil); got != "SELECT :missing" {
+		t.Errorf("expected SQL to be unchanged without variables, got %q", got)
+	}
+}
+
+func TestAccSql_variables(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfigVariables("it's secret"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlCheckTableCount(1),
+					resource.TestCheckResourceAttr("mysql_sql.row", "result.v", "it's secret"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSql_multiStatement(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfigMultiStatement("SELECT 1"),
+				Check:  testAccSqlCheckTableCount(2),
+			},
+			{
+				// The duplicate key fails the second statement and rolls back the first.
+				Config:      testAccSqlConfigMultiStatement("INSERT INTO tf_sql_test.t VALUES (3); INSERT INTO tf_sql_test.t VALUES (1)"),
+				ExpectError: regexp.MustCompile("statement 2 failed, transaction rolled back"),
+			},
+			{
+				Config: testAccSqlConfigMultiStatement("INSERT INTO tf_sql_test.t VALUES (3); SELECT MAX(id) AS id FROM tf_sql_test.t"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlCheckTableCount(3),
+					resource.TestCheckResourceAttr("mysql_sql.test", "rows_affected", "1"),
+					resource.TestCheckResourceAttr("mysql_sql.test", "result.%", "1"),
+					resource.TestCheckResourceAttr("mysql_sql.test", "result.id", "3"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSqlExec(t *testing.T, stmt string) {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, stmt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testAccSqlCheckTableCount checks the number of rows in tf_sql_test.t, or that the database
+// is gone for a negative count.
+func testAccSqlCheckTableCount(expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tf_sql_test.t").Scan(&count)
+		if expected < 0 {
+			if err == nil {
+				return fmt.Errorf("table tf_sql_test.t still exists")
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if count != expected {
+			return fmt.Errorf("expected %d rows in tf_sql_test.t, got %d", expected, count)
+		}
+		return nil
+	}
+}
+
+func testAccSqlConfigUpdateSql(rows int) string {
+	return fmt.Sprintf(`
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = "CREATE DATABASE tf_sql_test"
+  delete_sql = "DROP DATABASE tf_sql_test"
+}
+
+resource "mysql_sql" "table" {
+  name       = "tf_sql_test_table"
+  create_sql = "CREATE TABLE tf_sql_test.t (id INT PRIMARY KEY) COMMENT 'version %[1]d'"
+  update_sql = "INSERT INTO tf_sql_test.t VALUES (%[1]d)"
+  delete_sql = "DROP TABLE tf_sql_test.t"
+
+  depends_on = [mysql_sql.test]
+}
+`, rows)
+}
+
+func testAccSqlConfigReadSql() string {
+	return `
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = "CREATE DATABASE tf_sql_test"
+  delete_sql = "DROP DATABASE tf_sql_test"
+}
+
+resource "mysql_sql" "table" {
+  name            = "tf_sql_test_table"
+  create_sql      = "CREATE TABLE tf_sql_test.t (id INT PRIMARY KEY)"
+  delete_sql      = "DROP TABLE tf_sql_test.t"
+  read_sql        = "SELECT COUNT(*) FROM tf_sql_test.t"
+  expected_result = "1"
+
+  depends_on = [mysql_sql.test]
+}
+
+resource "mysql_sql" "row" {
+  name       = "tf_sql_test_row"
+  create_sql = "INSERT INTO tf_sql_test.t VALUES (1)"
+  delete_sql = "DELETE FROM tf_sql_test.t WHERE id = 1"
+  read_sql   = "SELECT id FROM tf_sql_test.t WHERE id = 1"
+
+  depends_on = [mysql_sql.table]
+}
+`
+}
+
+func testAccSqlConfigFiles(createFile, deleteFile string) string {
+	return fmt.Sprintf(`
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = "CREATE DATABASE tf_sql_test"
+  delete_sql = "DROP DATABASE tf_sql_test"
+}
+
+resource "mysql_sql" "table" {
+  name            = "tf_sql_test_table"
+  create_sql_file = %q
+  delete_sql_file = %q
+
+  depends_on = [mysql_sql.test]
+}
+`, createFile, deleteFile)
+}
+
+func testAccSqlConfigMultiStatement(updateSql string) string {
+	return fmt.Sprintf(`
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = <<-EOT
+    CREATE DATABASE tf_sql_test;
+    CREATE TABLE tf_sql_test.t (id INT PRIMARY KEY) ENGINE=InnoDB;
+    INSERT INTO tf_sql_test.t VALUES (1), (2);
+  EOT
+  update_sql = %q
+  delete_sql = "DROP DATABASE tf_sql_test"
+
+  multi_statement = true
+}
+`, updateSql)
+}
+
+func testAccSqlConfigOnlyIf() string {
+	return `
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = "CREATE DATABASE tf_sql_test; CREATE TABLE tf_sql_test.t (id INT)"
+  delete_sql = "DROP DATABASE tf_sql_test"
+
+  multi_statement = true
+}
+
+resource "mysql_sql" "executed" {
+  name       = "tf_sql_test_executed"
+  only_if    = "SELECT 1 FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = 'tf_sql_test' AND TABLE_NAME = 't' AND COLUMN_NAME = 'id'"
+  create_sql = "INSERT INTO tf_sql_test.t VALUES (1)"
+  delete_sql = "DELETE FROM tf_sql_test.t WHERE id = 1"
+
+  depends_on = [mysql_sql.test]
+}
+
+resource "mysql_sql" "skipped" {
+  name       = "tf_sql_test_skipped"
+  only_if    = "SELECT 1 FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = 'tf_sql_test' AND TABLE_NAME = 't' AND COLUMN_NAME = 'missing'"
+  create_sql = "INSERT INTO tf_sql_test.t VALUES (2)"
+  delete_sql = "SELECT missing FROM tf_sql_test.t"
+
+  depends_on = [mysql_sql.test]
+}
+`
+}
+
+func testAccSqlConfigTriggers(version string) string {
+	return fmt.Sprintf(`
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = "CREATE DATABASE tf_sql_test"
+  delete_sql = "DROP DATABASE tf_sql_test"
+}
+
+resource "mysql_sql" "table" {
+  name       = "tf_sql_test_table"
+  create_sql = "CREATE TABLE tf_sql_test.t (id INT PRIMARY KEY)"
+  delete_sql = "DROP TABLE tf_sql_test.t"
+
+  triggers = {
+    version = "%s"
+  }
+
+  depends_on = [mysql_sql.test]
+}
+`, version)
+}
+
+func testAccSqlConfigVariables(value string) string {
+	return fmt.Sprintf(`
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = "CREATE DATABASE tf_sql_test; CREATE TABLE tf_sql_test.t (v VARCHAR(64))"
+  delete_sql = "DROP DATABASE tf_sql_test"
+
+  multi_statement = true
+}
+
+resource "mysql_sql" "row" {
+  name       = "tf_sql_test_row"
+  create_sql = "INSERT INTO tf_sql_test.t VALUES (:value)"
+  delete_sql = "DELETE FROM tf_sql_test.t WHERE v = :value"
+  read_sql   = "SELECT v FROM tf_sql_test.t WHERE v = :value"
+
+  variables = {
+    value = %q
+  }
+
+  depends_on = [mysql_sql.test]
+}
+`, value)
+}
+
+func TestAccSql_import(t *testing.T) {
+	config := `
+resource "mysql_sql" "test" {
+  name       = "tf_sql_test"
+  create_sql = "CREATE DATABASE tf_sql_test"
+  delete_sql = "DROP DATABASE tf_sql_test"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSqlCheckTableCount(-1),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					testAccSqlExec(t, "CREATE DATABASE tf_sql_test")
+				},
+				Config:             config,
+				ResourceName:       "mysql_sql.test",
+				ImportState:        true,
+				ImportStateId:      "tf_sql_test#" + sqlStatementHash("CREATE DATABASE tf_sql_test"),
+				ImportStatePersist: true,
+			},
+			{
+				// Running create_sql again would fail, as the database exists.
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("mysql_sql.test", "create_sql", "CREATE DATABASE tf_sql_test"),
+			},
+		},
+	})
+}
+
+func TestImportSqlID(t *testing.T) {
+	hash := sqlStatementHash("CREATE DATABASE app")
+	for _, tc := range []struct {
+		id, name, hash string
+	}{
+		{"app", "app", ""},
+		{"app#" + hash, "app", hash},
+		{"app#" + strings.ToUpper(hash), "app", hash},
+		{"seed#1", "seed#1", ""},
+	} {
+		d := resourceSql().TestResourceData()
+		d.SetId(tc.id)
+		if _, err := ImportSql(context.Background(), d, nil); err != nil {
+			t.Errorf("ImportSql(%q): %v", tc.id, err)
+			continue
+		}
+		if d.Id() != tc.name || d.Get("name") != tc.name || d.Get("source_hash") != tc.hash {
+			t.Errorf("ImportSql(%q) = %s %s %s, expected %s %s", tc.id, d.Id(), d.Get("name"), d.Get("source_hash"), tc.name, tc.hash)
+		}
+	}
+}
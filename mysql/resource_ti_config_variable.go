@@ -17,12 +17,18 @@ import (
 	"github.com/tidwall/gjson"
 )
 
+// tiConfigTypes are the types of servers SET CONFIG can configure.
+var tiConfigTypes = []string{"pd", "tikv", "tidb"}
+
+var TiDBServerConfigTiDBMinVersion = "6.5.0"
+
 func resourceTiConfigVariable() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateOrUpdateConfigVariable,
 		ReadContext:   ReadConfigVariable,
 		UpdateContext: CreateOrUpdateConfigVariable,
 		DeleteContext: DeleteConfigVariable,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -48,12 +54,16 @@ func resourceTiConfigVariable() *schema.Resource {
 				Type:         schema.TypeString,
 				Required:     true,
 				ForceNew:     true,
-				ValidateFunc: validation.StringInSlice([]string{"pd", "tikv"}, true),
+				ValidateFunc: validation.StringInSlice(tiConfigTypes, true),
 			},
 			"instance": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"original_value": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -72,13 +82,21 @@ func CreateOrUpdateConfigVariable(ctx context.Context, d *schema.ResourceData, m
 	var warnLevel, warnMessage string
 	var warnCode int = 0
 
-	configQuery := fmt.Sprintf("SET CONFIG %s %s=", varInstanceType, quoteIdentifier(varName))
+	if err := checkTiConfigTypeSupport(ctx, db, varInstanceType); err != nil {
+		return diag.Errorf("cannot set %s: %v", varName, err)
+	}
 
-	if varInstance != "" {
-		configQuery = fmt.Sprintf("SET CONFIG \"%s\" %s=", varInstance, quoteIdentifier(varName))
+	// The value before the first apply is restored on destroy, as the static defaults can differ
+	// from the defaults of the running TiDB release.
+	if d.IsNewResource() {
+		values, err := readTiConfigs(ctx, db, varInstanceType, varInstance, map[string]interface{}{varName: varValue})
+		if err != nil {
+			return diag.Errorf("error reading original value of %s: %s", varName, err)
+		}
+		d.Set("original_value", values[varName])
 	}
 
-	configQuery = fmt.Sprintf("%s'%s'", configQuery, varValue)
+	configQuery := setTiConfigSQL(varInstanceType, varInstance, varName, varValue)
 
 	log.Printf("[DEBUG] SQL: %s\n", configQuery)
 
@@ -111,25 +129,31 @@ func ReadConfigVariable(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.FromErr(err)
 	}
 
-	match, _ := regexp.MatchString("^(pd|tikv)#(.*)$", d.Id())
+	match, _ := regexp.MatchString("^(pd|tikv|tidb)#(.*)$", d.Id())
 	if !match {
-		return diag.Errorf("error parsing TiDB component (tikv or pd) type from ID.  \n Acceptable format is <pd|tikv>#<config_variable>#<optional_instance>")
+		return diag.Errorf("error parsing TiDB component (tikv, pd or tidb) type from ID.  \n Acceptable format is <pd|tikv|tidb>#<config_variable>#<optional_instance>")
 	}
 
 	indexParts := strings.Split(d.Id(), "#")
 	splitedResType := indexParts[0]
 	splitedResName := indexParts[1]
 
-	configQuery := fmt.Sprintf("SHOW CONFIG WHERE type = '%s' AND name = '%s'", splitedResType, splitedResName)
+	configQuery := "SHOW CONFIG WHERE type = ? AND name = ?"
+	args := []interface{}{splitedResType, splitedResName}
 	if len(indexParts) > 2 {
-		configQuery = configQuery + fmt.Sprintf(" AND instance = '%s'", indexParts[2])
+		configQuery += " AND instance = ?"
+		args = append(args, indexParts[2])
 	}
 
 	log.Printf("[DEBUG] SQL: %s\n", configQuery)
 
-	err = db.QueryRow(configQuery).Scan(&resType, &resInstance, &resName, &resValue)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+	err = db.QueryRowContext(ctx, configQuery, args...).Scan(&resType, &resInstance, &resName, &resValue)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The config key or the instance is gone, e.g. after scaling in the cluster.
+		log.Printf("[WARN] Config variable (%s) not found; removing from state", d.Id())
 		d.SetId("")
+		return nil
+	} else if err != nil {
 		return diag.Errorf("error during show config variables: %s", err)
 	}
 
@@ -146,38 +170,80 @@ func ReadConfigVariable(ctx context.Context, d *schema.ResourceData, meta interf
 func DeleteConfigVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	varName := d.Get("name").(string)
 	varInstanceType := d.Get("type").(string)
+
+	if originalValue := d.Get("original_value").(string); originalValue != "" {
+		d.Set("value", originalValue)
+		return CreateOrUpdateConfigVariable(ctx, d, meta)
+	}
+
+	// Resources created before original_value was recorded fall back to the static defaults.
+	defaultValue, ok, err := tiConfigDefaultValue(varInstanceType, varName)
+	if err != nil {
+		return diag.Errorf("error during destroy config variables: %s", err)
+	}
+	if !ok {
+		log.Printf("[WARN] Variable_name (%s) dont have default values; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("value", defaultValue)
+
+	return CreateOrUpdateConfigVariable(ctx, d, meta)
+}
+
+// setTiConfigSQL returns the statement setting a config key of all servers of the type, or of
+// a single instance.
+func setTiConfigSQL(instanceType, instance, name, value string) string {
+	target := instanceType
+	if instance != "" {
+		target = quoteLiteral(instance)
+	}
+	return fmt.Sprintf("SET CONFIG %s %s=%s", target, quoteIdentifier(name), quoteLiteral(value))
+}
+
+// checkTiConfigTypeSupport checks that SET CONFIG supports the type of servers on this cluster.
+func checkTiConfigTypeSupport(ctx context.Context, db *sql.DB, instanceType string) error {
+	if strings.EqualFold(instanceType, "tidb") {
+		return checkTiDBFeatureSupport(ctx, db, "config keys of tidb servers", TiDBServerConfigTiDBMinVersion)
+	}
+	return nil
+}
+
+// tiConfigDefaultValue returns the value a config key is restored to on destroy, and false if
+// it has no default to restore.
+func tiConfigDefaultValue(instanceType, name string) (string, bool, error) {
 	defCfg := &defaultConfig{}
 	var jsonCfg []byte
 	var err error
 
 	if err := defaults.Set(defCfg); err != nil {
-		return diag.Errorf("error during destroy config variables: %s", err)
+		return "", false, err
 	}
 
-	switch varInstanceType {
+	switch instanceType {
 	case "pd":
 		jsonCfg, err = json.MarshalIndent(&defCfg.Pd, "", "    ")
 	case "tikv":
 		jsonCfg, err = json.MarshalIndent(&defCfg.TiKv, "", "    ")
+	case "tidb":
+		// There are no static defaults for tidb servers, only the recorded original value.
+		return "", false, nil
 	default:
-		return diag.Errorf("error during destory config variables: %s is not allowed type", varInstanceType)
+		return "", false, fmt.Errorf("%s is not allowed type", instanceType)
 	}
 
 	if err != nil {
-		return diag.Errorf("error during destroy config variables: %s", err)
+		return "", false, err
 	}
 
 	log.Printf("[DEBUG] JSON CFG: %s", jsonCfg)
-	defaultValue := gjson.Get(string(jsonCfg), varName)
-	log.Printf("[DEBUG]: DESTROY %s %s->%s\n", varInstanceType, varName, defaultValue)
+	defaultValue := gjson.Get(string(jsonCfg), name)
+	log.Printf("[DEBUG]: DESTROY %s %s->%s\n", instanceType, name, defaultValue)
 	match, _ := regexp.MatchString("^(IGNOREONDESTROY)#(.*)$", defaultValue.String())
 	if match {
-		log.Printf("[WARN] Variable_name (%s) dont have default values; removing from state", d.Id())
-		d.SetId("")
-		return nil
+		return "", false, nil
 	}
 
-	d.Set("value", defaultValue.String())
-
-	return CreateOrUpdateConfigVariable(ctx, d, meta)
+	return defaultValue.String(), true, nil
 }
@@ -34,6 +34,7 @@ func TestPdConfigVar_basic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccConfigVarExists(varName, varValue, varType),
 					resource.TestCheckResourceAttr(resourceName, "name", varName),
+					resource.TestCheckResourceAttrSet(resourceName, "original_value"),
 				),
 			},
 			{
@@ -226,3 +227,19 @@ resource "mysql_ti_config" "test" {
 }
 `, varName, varValue, varType, varInstance)
 }
+
+func TestTiConfigDefaultValue(t *testing.T) {
+	value, ok, err := tiConfigDefaultValue("pd", "log.level")
+	if err != nil || !ok || value != "info" {
+		t.Errorf("tiConfigDefaultValue(pd, log.level) = %q, %t, %v, expected info", value, ok, err)
+	}
+
+	// tidb servers have no static defaults, only the recorded original value.
+	if _, ok, err := tiConfigDefaultValue("tidb", "log.level"); err != nil || ok {
+		t.Errorf("tiConfigDefaultValue(tidb, log.level) = %t, %v, expected no default", ok, err)
+	}
+
+	if _, _, err := tiConfigDefaultValue("tiflash", "log.level"); err == nil {
+		t.Error("tiConfigDefaultValue(tiflash, log.level) succeeded, expected an error")
+	}
+}
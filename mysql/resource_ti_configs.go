@@ -0,0 +1,227 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceTiConfigs sets many config keys of a TiDB component at once, unlike mysql_ti_config,
+// which sets a single key.
+func resourceTiConfigs() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateOrUpdateConfigs,
+		ReadContext:   ReadConfigs,
+		UpdateContext: CreateOrUpdateConfigs,
+		DeleteContext: DeleteConfigs,
+		Timeouts:      defaultResourceTimeouts(true),
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(tiConfigTypes, true),
+			},
+			"instance": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"settings": {
+				Type:     schema.TypeMap,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func tiConfigsID(instanceType, instance string) string {
+	if instance != "" {
+		return fmt.Sprintf("%s#%s", instanceType, instance)
+	}
+	return instanceType
+}
+
+func setTiConfigs(ctx context.Context, db *sql.DB, instanceType, instance string, settings map[string]string) diag.Diagnostics {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		configQuery := setTiConfigSQL(instanceType, instance, name, settings[name])
+		log.Printf("[DEBUG] SQL: %s\n", configQuery)
+
+		if _, err := db.ExecContext(ctx, configQuery); err != nil {
+			return sqlErrorDiag(fmt.Sprintf("error setting %s", name), configQuery, err)
+		}
+
+		var warnLevel, warnMessage string
+		var warnCode int = 0
+		db.QueryRowContext(ctx, "SHOW WARNINGS").Scan(&warnLevel, &warnCode, &warnMessage)
+		if warnCode != 0 {
+			return diag.Errorf("error setting value: %s -> %s Error: %s", name, settings[name], warnMessage)
+		}
+	}
+	return nil
+}
+
+// tiConfigDefaults returns the values the keys are restored to, skipping keys without defaults.
+func tiConfigDefaults(instanceType string, names []string) (map[string]string, error) {
+	settings := map[string]string{}
+	for _, name := range names {
+		defaultValue, ok, err := tiConfigDefaultValue(instanceType, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			log.Printf("[WARN] Config %s has no default value; leaving it as it is", name)
+			continue
+		}
+		settings[name] = defaultValue
+	}
+	return settings, nil
+}
+
+func CreateOrUpdateConfigs(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	instanceType := d.Get("type").(string)
+	instance := d.Get("instance").(string)
+
+	if err := checkTiConfigTypeSupport(ctx, db, instanceType); err != nil {
+		return diag.Errorf("cannot set config: %v", err)
+	}
+
+	settings := map[string]string{}
+	for name, value := range d.Get("settings").(map[string]interface{}) {
+		settings[name] = value.(string)
+	}
+
+	// Only changed keys are set, and removed keys are restored to their defaults.
+	oldRaw, _ := d.GetChange("settings")
+	old := oldRaw.(map[string]interface{})
+
+	var removed []string
+	for name := range old {
+		if _, ok := settings[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	restored, err := tiConfigDefaults(instanceType, removed)
+	if err != nil {
+		return diag.Errorf("error restoring removed config: %s", err)
+	}
+	if diags := setTiConfigs(ctx, db, instanceType, instance, restored); diags != nil {
+		return diags
+	}
+
+	changed := map[string]string{}
+	for name, value := range settings {
+		if previous, ok := old[name]; !ok || previous.(string) != value {
+			changed[name] = value
+		}
+	}
+	if diags := setTiConfigs(ctx, db, instanceType, instance, changed); diags != nil {
+		return diags
+	}
+
+	d.SetId(tiConfigsID(instanceType, instance))
+
+	return ReadConfigs(ctx, d, meta)
+}
+
+func ReadConfigs(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	instanceType := d.Get("type").(string)
+	instance := d.Get("instance").(string)
+	settings := d.Get("settings").(map[string]interface{})
+	if len(settings) == 0 {
+		return nil
+	}
+
+	values, err := readTiConfigs(ctx, db, instanceType, instance, settings)
+	if err != nil {
+		return diag.Errorf("error during show config variables: %s", err)
+	}
+
+	d.Set("settings", values)
+
+	return nil
+}
+
+// readTiConfigs reads the configured keys in one query. Without an instance every server of
+// the type returns a row; a server that differs from the configured value wins, so the drift
+// shows in the plan.
+func readTiConfigs(ctx context.Context, db *sql.DB, instanceType, instance string, settings map[string]interface{}) (map[string]string, error) {
+	names := make([]string, 0, len(settings))
+	args := []interface{}{instanceType}
+	for name := range settings {
+		names = append(names, "?")
+		args = append(args, name)
+	}
+
+	configQuery := fmt.Sprintf("SHOW CONFIG WHERE type = ? AND name IN (%s)", strings.Join(names, ", "))
+	if instance != "" {
+		configQuery += " AND instance = ?"
+		args = append(args, instance)
+	}
+	log.Printf("[DEBUG] SQL: %s\n", configQuery)
+
+	rows, err := db.QueryContext(ctx, configQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := map[string]string{}
+	for rows.Next() {
+		var resType, resInstance, resName, resValue string
+		if err := rows.Scan(&resType, &resInstance, &resName, &resValue); err != nil {
+			return nil, err
+		}
+		if current, ok := values[resName]; ok && current != settings[resName] {
+			continue
+		}
+		values[resName] = resValue
+	}
+
+	return values, rows.Err()
+}
+
+func DeleteConfigs(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	instanceType := d.Get("type").(string)
+
+	var names []string
+	for name := range d.Get("settings").(map[string]interface{}) {
+		names = append(names, name)
+	}
+
+	restored, err := tiConfigDefaults(instanceType, names)
+	if err != nil {
+		return diag.Errorf("error during destroy config variables: %s", err)
+	}
+
+	return setTiConfigs(ctx, db, instanceType, d.Get("instance").(string), restored)
+}
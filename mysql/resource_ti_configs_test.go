@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSetTiConfigSQL(t *testing.T) {
+	if got, expected := setTiConfigSQL("tikv", "", "split.qps-threshold", "1000"), "SET CONFIG tikv `split.qps-threshold`='1000'"; got != expected {
+		t.Errorf("setTiConfigSQL = %q, expected %q", got, expected)
+	}
+	if got, expected := setTiConfigSQL("pd", "127.0.0.1:2379", "log.level", "warn"), "SET CONFIG '127.0.0.1:2379' `log.level`='warn'"; got != expected {
+		t.Errorf("setTiConfigSQL = %q, expected %q", got, expected)
+	}
+}
+
+func TestAccTiConfigs_basic(t *testing.T) {
+	resourceName := "mysql_ti_configs.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTiConfigsConfig(`"split.qps-threshold" = "1000"
+		"split.byte-threshold" = "31457280"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTiConfigsValue("tikv", "split.qps-threshold", "1000"),
+					resource.TestCheckResourceAttr(resourceName, "settings.%", "2"),
+				),
+			},
+			{
+				Config: testAccTiConfigsConfig(`"split.qps-threshold" = "2000"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTiConfigsValue("tikv", "split.qps-threshold", "2000"),
+					testAccTiConfigsValue("tikv", "split.byte-threshold", "31457280"),
+					resource.TestCheckResourceAttr(resourceName, "settings.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTiConfigsValue(varType, varName, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		_, value, err := testAccGetConfigVar(varName, varType, db)
+		if err != nil {
+			return err
+		}
+		if value != expected {
+			return fmt.Errorf("config %s is %s, expected %s", varName, value, expected)
+		}
+		return nil
+	}
+}
+
+func testAccTiConfigsConfig(settings string) string {
+	return fmt.Sprintf(`
+resource "mysql_ti_configs" "test" {
+	type = "tikv"
+	settings = {
+		%s
+	}
+}
+`, settings)
+}
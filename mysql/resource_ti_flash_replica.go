@@ -0,0 +1,239 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var FlashReplicaTiDBMinVersion = "4.0.0"
+
+// FlashReplica is a row of information_schema.tiflash_replica.
+type FlashReplica struct {
+	ReplicaCount   int
+	LocationLabels []string
+	Available      bool
+	Progress       float64
+}
+
+func resourceTiFlashReplica() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateFlashReplica,
+		ReadContext:   ReadFlashReplica,
+		UpdateContext: UpdateFlashReplica,
+		DeleteContext: DeleteFlashReplica,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportFlashReplica,
+		},
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"table": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"replica_count": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"location_labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"wait_for_available": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"available": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"progress": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func flashReplicaID(database, table string) string {
+	return fmt.Sprintf("%s.%s", database, table)
+}
+
+// setFlashReplica sets the number of TiFlash replicas of the table, returning the statement.
+func setFlashReplica(ctx context.Context, db *sql.DB, database, table string, count int, labels []string) (string, error) {
+	stmtSQL := fmt.Sprintf("ALTER TABLE %s.%s SET TIFLASH REPLICA %d", quoteIdentifier(database), quoteIdentifier(table), count)
+	if len(labels) > 0 {
+		quoted := make([]string, len(labels))
+		for i, label := range labels {
+			quoted[i] = quoteLiteral(label)
+		}
+		stmtSQL += " LOCATION LABELS " + strings.Join(quoted, ", ")
+	}
+
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	_, err := db.ExecContext(ctx, stmtSQL)
+	return stmtSQL, err
+}
+
+func applyFlashReplica(ctx context.Context, d *schema.ResourceData, meta interface{}, timeoutKey string) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	var labels []string
+	for _, label := range d.Get("location_labels").([]interface{}) {
+		labels = append(labels, label.(string))
+	}
+
+	if stmtSQL, err := setFlashReplica(ctx, db, database, table, d.Get("replica_count").(int), labels); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("failed setting TiFlash replica of %s.%s", database, table), stmtSQL, err)
+	}
+
+	d.SetId(flashReplicaID(database, table))
+
+	if d.Get("wait_for_available").(bool) {
+		if err := waitForFlashReplica(ctx, db, database, table, d.Timeout(timeoutKey)); err != nil {
+			return diag.Errorf("TiFlash replica of %s.%s didn't become available: %v", database, table, err)
+		}
+	}
+
+	return ReadFlashReplica(ctx, d, meta)
+}
+
+// waitForFlashReplica polls until TiFlash has replicated the table, which takes a while for
+// large tables.
+func waitForFlashReplica(ctx context.Context, db *sql.DB, database, table string, timeout time.Duration) error {
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		replica, err := getFlashReplicaFromDB(ctx, db, database, table)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		if replica == nil {
+			return retry.NonRetryableError(errors.New("the table has no TiFlash replica"))
+		}
+		if !replica.Available {
+			return retry.RetryableError(fmt.Errorf("replication progress is %.0f%%", replica.Progress*100))
+		}
+		return nil
+	})
+}
+
+func CreateFlashReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "TiFlash replicas", FlashReplicaTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot create TiFlash replica: %v", err)
+	}
+
+	return applyFlashReplica(ctx, d, meta, schema.TimeoutCreate)
+}
+
+func UpdateFlashReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return applyFlashReplica(ctx, d, meta, schema.TimeoutUpdate)
+}
+
+func ReadFlashReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	replica, err := getFlashReplicaFromDB(ctx, db, database, table)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if replica == nil {
+		log.Printf("[WARN] TiFlash replica of %s.%s not found; removing from state", database, table)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("replica_count", replica.ReplicaCount)
+	d.Set("location_labels", replica.LocationLabels)
+	d.Set("available", replica.Available)
+	d.Set("progress", replica.Progress)
+
+	return nil
+}
+
+func DeleteFlashReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	if stmtSQL, err := setFlashReplica(ctx, db, database, table, 0, nil); err != nil {
+		// The replica is gone with the table or database.
+		if errNum := mysqlErrorNumber(err); errNum == unknownDatabaseErrCode || errNum == unknownTableErrCode {
+			return nil
+		}
+		return sqlErrorDiag(fmt.Sprintf("failed removing TiFlash replica of %s.%s", database, table), stmtSQL, err)
+	}
+
+	return nil
+}
+
+func ImportFlashReplica(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, table, ok := strings.Cut(d.Id(), ".")
+	if !ok || database == "" || table == "" {
+		return nil, fmt.Errorf("wrong ID format %s - expected database.table", d.Id())
+	}
+
+	d.Set("database", database)
+	d.Set("table", table)
+	d.Set("wait_for_available", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func getFlashReplicaFromDB(ctx context.Context, db *sql.DB, database, table string) (*FlashReplica, error) {
+	query := `SELECT REPLICA_COUNT, IFNULL(LOCATION_LABELS, ''), AVAILABLE, PROGRESS
+		FROM information_schema.tiflash_replica WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`
+	log.Println("[DEBUG] Executing query:", query)
+
+	var replica FlashReplica
+	var labels string
+	err := db.QueryRowContext(ctx, query, database, table).Scan(&replica.ReplicaCount, &labels, &replica.Available, &replica.Progress)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error during get TiFlash replica of %s.%s: %w", database, table, err)
+	}
+
+	if labels != "" {
+		replica.LocationLabels = strings.Split(labels, ",")
+	}
+	return &replica, nil
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTiFlashReplica_basic(t *testing.T) {
+	dbName := "tf_flash_replica"
+	resourceName := "mysql_ti_flash_replica.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, FlashReplicaTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccFlashReplicaCheckDestroy(dbName, "t"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFlashReplicaConfig(dbName, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccFlashReplicaCount(dbName, "t", 1),
+					resource.TestCheckResourceAttr(resourceName, "replica_count", "1"),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".t"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"available", "progress"},
+			},
+		},
+	})
+}
+
+func testAccFlashReplicaCount(database, table string, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		replica, err := getFlashReplicaFromDB(ctx, db, database, table)
+		if err != nil {
+			return err
+		}
+		if replica == nil {
+			return fmt.Errorf("TiFlash replica of %s.%s does not exist", database, table)
+		}
+		if replica.ReplicaCount != expected {
+			return fmt.Errorf("TiFlash replica count of %s.%s is %d, expected %d", database, table, replica.ReplicaCount, expected)
+		}
+		return nil
+	}
+}
+
+func testAccFlashReplicaCheckDestroy(database, table string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		replica, err := getFlashReplicaFromDB(ctx, db, database, table)
+		if err != nil {
+			return err
+		}
+		if replica != nil {
+			return fmt.Errorf("TiFlash replica of %s.%s still exists", database, table)
+		}
+		return nil
+	}
+}
+
+func testAccFlashReplicaConfig(dbName string, count int) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+	name = "%s"
+}
+
+resource "mysql_sql" "table" {
+	name       = "table"
+	create_sql = "CREATE TABLE ${mysql_database.test.name}.t (a INT)"
+	delete_sql = "DROP TABLE ${mysql_database.test.name}.t"
+}
+
+resource "mysql_ti_flash_replica" "test" {
+	database      = mysql_database.test.name
+	table         = "t"
+	replica_count = %d
+
+	depends_on = [mysql_sql.table]
+}
+`, dbName, count)
+}
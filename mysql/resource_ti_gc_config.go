@@ -0,0 +1,235 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// gcVariables maps the attributes of mysql_ti_gc_config to the TiDB system variables.
+var gcVariables = []struct {
+	attribute string
+	variable  string
+}{
+	{"life_time", "tidb_gc_life_time"},
+	{"run_interval", "tidb_gc_run_interval"},
+	{"concurrency", "tidb_gc_concurrency"},
+}
+
+// gcMinDuration is the minimum life time and run interval TiDB accepts.
+const gcMinDuration = 10 * time.Minute
+
+func resourceTiGCConfig() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateOrUpdateGCConfig,
+		ReadContext:   ReadGCConfig,
+		UpdateContext: CreateOrUpdateGCConfig,
+		DeleteContext: DeleteGCConfig,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"life_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				AtLeastOneOf:     []string{"life_time", "run_interval", "concurrency"},
+				ValidateFunc:     validateGCDuration,
+				DiffSuppressFunc: durationSuppressFunc,
+			},
+			"run_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateGCDuration,
+				DiffSuppressFunc: durationSuppressFunc,
+			},
+			"concurrency": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.All(validation.IntBetween(-1, 256), validation.IntNotInSlice([]int{0})),
+			},
+			"original_values": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func validateGCDuration(val interface{}, key string) (warns []string, errs []error) {
+	duration, err := time.ParseDuration(val.(string))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a duration like 10m or 24h, got %q", key, val))
+	} else if duration < gcMinDuration {
+		errs = append(errs, fmt.Errorf("%q must be at least %s, got %q", key, gcMinDuration, val))
+	}
+	return
+}
+
+// gcConfigured returns the configured variables and their values. Unset attributes aren't
+// managed.
+func gcConfigured(d *schema.ResourceData) map[string]string {
+	configured := map[string]string{}
+	for _, v := range gcVariables {
+		switch value := d.Get(v.attribute).(type) {
+		case string:
+			if value != "" {
+				configured[v.variable] = value
+			}
+		case int:
+			if value != 0 {
+				configured[v.variable] = strconv.Itoa(value)
+			}
+		}
+	}
+	return configured
+}
+
+func readGCVariables(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	placeholders := make([]string, len(gcVariables))
+	args := make([]interface{}, len(gcVariables))
+	for i, v := range gcVariables {
+		placeholders[i] = "?"
+		args[i] = v.variable
+	}
+
+	query := fmt.Sprintf("SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME IN (%s)", strings.Join(placeholders, ", "))
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading GC variables: %w", err)
+	}
+	defer rows.Close()
+
+	values := map[string]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("error reading GC variables: %w", err)
+		}
+		values[name] = value
+	}
+	return values, rows.Err()
+}
+
+func setGCVariables(ctx context.Context, db *sql.DB, values map[string]string) diag.Diagnostics {
+	for _, v := range gcVariables {
+		value, ok := values[v.variable]
+		if !ok {
+			continue
+		}
+
+		sqlCommand := setGlobalVariableSQL(v.variable, value)
+		log.Printf("[DEBUG] SQL: %s", sqlCommand)
+		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+			return sqlErrorDiag(fmt.Sprintf("error setting %s", v.variable), sqlCommand, err)
+		}
+	}
+	return nil
+}
+
+func CreateOrUpdateGCConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if isTiDB, _, _, err := serverTiDB(ctx, db); err != nil {
+		return diag.FromErr(err)
+	} else if !isTiDB {
+		return diag.Errorf("mysql_ti_gc_config is only available on TiDB")
+	}
+
+	current, err := readGCVariables(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The value of each variable before it's first managed is restored on destroy, or when the
+	// attribute is removed.
+	originals := map[string]string{}
+	for name, value := range d.Get("original_values").(map[string]interface{}) {
+		originals[name] = value.(string)
+	}
+
+	configured := gcConfigured(d)
+	restored := map[string]string{}
+	for name, original := range originals {
+		if _, ok := configured[name]; !ok {
+			if original != "" {
+				restored[name] = original
+			}
+			delete(originals, name)
+		}
+	}
+	for name := range configured {
+		if _, ok := originals[name]; !ok {
+			originals[name] = current[name]
+		}
+	}
+
+	if diags := setGCVariables(ctx, db, restored); diags != nil {
+		return diags
+	}
+	if diags := setGCVariables(ctx, db, configured); diags != nil {
+		return diags
+	}
+
+	d.Set("original_values", originals)
+	d.SetId("tidb_gc")
+
+	return ReadGCConfig(ctx, d, meta)
+}
+
+func ReadGCConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values, err := readGCVariables(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	configured := gcConfigured(d)
+	for _, v := range gcVariables {
+		if _, ok := configured[v.variable]; !ok {
+			continue
+		}
+		if v.attribute == "concurrency" {
+			concurrency, _ := strconv.Atoi(values[v.variable])
+			d.Set(v.attribute, concurrency)
+		} else {
+			d.Set(v.attribute, values[v.variable])
+		}
+	}
+
+	return nil
+}
+
+func DeleteGCConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	originals := map[string]string{}
+	for name, value := range d.Get("original_values").(map[string]interface{}) {
+		if value.(string) != "" {
+			originals[name] = value.(string)
+		}
+	}
+
+	return setGCVariables(ctx, db, originals)
+}
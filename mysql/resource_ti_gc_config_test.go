@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateGCDuration(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"10m":    true,
+		"24h":    true,
+		"10m0s":  true,
+		"9m59s":  false,
+		"1h30":   false,
+		"tenmin": false,
+	} {
+		_, errs := validateGCDuration(value, "life_time")
+		if valid != (len(errs) == 0) {
+			t.Errorf("validateGCDuration(%q) = %v, expected valid %t", value, errs, valid)
+		}
+	}
+}
+
+func TestAccTiGCConfig_basic(t *testing.T) {
+	resourceName := "mysql_ti_gc_config.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGCVariable("tidb_gc_life_time", "10m0s"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGCConfig(`life_time = "30m"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGCVariable("tidb_gc_life_time", "30m0s"),
+					resource.TestCheckResourceAttr(resourceName, "original_values.tidb_gc_life_time", "10m0s"),
+				),
+			},
+			{
+				Config: testAccGCConfig(`life_time = "1h"
+	run_interval = "20m"
+	concurrency  = 4`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGCVariable("tidb_gc_life_time", "1h0m0s"),
+					testAccGCVariable("tidb_gc_run_interval", "20m0s"),
+					testAccGCVariable("tidb_gc_concurrency", "4"),
+				),
+			},
+			{
+				Config: testAccGCConfig(`life_time = "1h"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGCVariable("tidb_gc_run_interval", "10m0s"),
+					resource.TestCheckNoResourceAttr(resourceName, "original_values.tidb_gc_run_interval"),
+				),
+			},
+		},
+	})
+}
+
+func testAccGCVariable(name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		values, err := readGCVariables(ctx, db)
+		if err != nil {
+			return err
+		}
+		if values[name] != expected {
+			return fmt.Errorf("%s is %s, expected %s", name, values[name], expected)
+		}
+		return nil
+	}
+}
+
+func testAccGCConfig(settings string) string {
+	return fmt.Sprintf(`
+resource "mysql_ti_gc_config" "test" {
+	%s
+}
+`, settings)
+}
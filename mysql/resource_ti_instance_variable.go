@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceTiInstanceVariable sets a TiDB variable of instance scope, which SET GLOBAL only
+// applies to the server it's executed on, so it connects to the instance directly.
+func resourceTiInstanceVariable() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateOrUpdateInstanceVariable,
+		ReadContext:   ReadInstanceVariable,
+		UpdateContext: CreateOrUpdateInstanceVariable,
+		DeleteContext: DeleteInstanceVariable,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportInstanceVariable,
+		},
+		Schema: map[string]*schema.Schema{
+			"instance": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+func instanceVariableID(name, instance string) string {
+	return fmt.Sprintf("%s@%s", name, instance)
+}
+
+func CreateOrUpdateInstanceVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instance := d.Get("instance").(string)
+	db, err := getInstanceDatabaseFromMeta(ctx, meta, instance)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if isTiDB, _, _, err := serverTiDB(ctx, db); err != nil {
+		return diag.FromErr(err)
+	} else if !isTiDB {
+		return diag.Errorf("instance %s isn't a TiDB server", instance)
+	}
+
+	name := d.Get("name").(string)
+	sqlCommand := setGlobalVariableSQL(name, d.Get("value").(string))
+	log.Printf("[DEBUG] SQL: %s", sqlCommand)
+
+	if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error setting value on instance %s", instance), sqlCommand, err)
+	}
+
+	d.SetId(instanceVariableID(name, instance))
+
+	return ReadInstanceVariable(ctx, d, meta)
+}
+
+func ReadInstanceVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instance := d.Get("instance").(string)
+	db, err := getInstanceDatabaseFromMeta(ctx, meta, instance)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var name, value string
+	err = db.QueryRowContext(ctx, "SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME = ?", d.Get("name").(string)).Scan(&name, &value)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Printf("[WARN] Variable (%s) not found on instance %s; removing from state", d.Get("name").(string), instance)
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.Errorf("error during show global variables on instance %s: %s", instance, err)
+	}
+
+	d.Set("value", value)
+
+	return nil
+}
+
+func DeleteInstanceVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	instance := d.Get("instance").(string)
+	db, err := getInstanceDatabaseFromMeta(ctx, meta, instance)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sqlCommand := fmt.Sprintf("SET GLOBAL %s = DEFAULT", quoteIdentifier(d.Get("name").(string)))
+	log.Printf("[DEBUG] SQL: %s", sqlCommand)
+
+	if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error resetting value on instance %s", instance), sqlCommand, err)
+	}
+
+	return nil
+}
+
+func ImportInstanceVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name, instance, ok := strings.Cut(d.Id(), "@")
+	if !ok || name == "" || instance == "" {
+		return nil, fmt.Errorf("wrong ID format %s - expected name@host:port", d.Id())
+	}
+
+	d.Set("name", name)
+	d.Set("instance", instance)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccTiInstanceVariable_basic(t *testing.T) {
+	instance := os.Getenv("MYSQL_ENDPOINT")
+	resourceName := "mysql_ti_instance_variable.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceVariableConfig(instance, "tidb_slow_log_threshold", "500"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "value", "500"),
+					resource.TestCheckResourceAttr(resourceName, "id", "tidb_slow_log_threshold@"+instance),
+				),
+			},
+			{
+				Config: testAccInstanceVariableConfig(instance, "tidb_slow_log_threshold", "1000"),
+				Check:  resource.TestCheckResourceAttr(resourceName, "value", "1000"),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccInstanceVariableConfig(instance, name, value string) string {
+	return fmt.Sprintf(`
+resource "mysql_ti_instance_variable" "test" {
+	instance = "%s"
+	name     = "%s"
+	value    = "%s"
+}
+`, instance, name, value)
+}
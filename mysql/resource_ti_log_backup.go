@@ -0,0 +1,188 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var LogBackupTiDBMinVersion = "6.2.0"
+
+// logBackupID is the ID of mysql_ti_log_backup; a cluster has a single log backup task.
+const logBackupID = "log_backup"
+
+func resourceTiLogBackup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateLogBackup,
+		ReadContext:   ReadLogBackup,
+		UpdateContext: UpdateLogBackup,
+		DeleteContext: DeleteLogBackup,
+		Timeouts:      defaultResourceTimeouts(true),
+		Schema: map[string]*schema.Schema{
+			"storage": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				Sensitive: true,
+			},
+			"start_ts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"paused": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// execLogBackupStatement executes a statement managing the log backup task.
+func execLogBackupStatement(ctx context.Context, db *sql.DB, summary, stmtSQL string) diag.Diagnostics {
+	log.Printf("[DEBUG] SQL: %s", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag(summary, stmtSQL, err)
+	}
+	return nil
+}
+
+func CreateLogBackup(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "log backups", LogBackupTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot start log backup: %v", err)
+	}
+
+	options := ""
+	if startTS := d.Get("start_ts").(int); startTS != 0 {
+		options = fmt.Sprintf(" START_TS = %d", startTS)
+	}
+
+	// The storage may hold credentials, so it's redacted from the logged statement.
+	redactedSQL := "BACKUP LOGS TO '<storage>'" + options
+	log.Printf("[DEBUG] SQL: %s", redactedSQL)
+
+	stmtSQL := fmt.Sprintf("BACKUP LOGS TO %s%s", quoteLiteral(d.Get("storage").(string)), options)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag("error starting log backup", redactedSQL, err)
+	}
+
+	d.SetId(logBackupID)
+
+	if d.Get("paused").(bool) {
+		if diags := execLogBackupStatement(ctx, db, "error pausing log backup", "PAUSE BACKUP LOGS"); diags != nil {
+			return diags
+		}
+	}
+
+	return ReadLogBackup(ctx, d, meta)
+}
+
+func UpdateLogBackup(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("paused") {
+		var diags diag.Diagnostics
+		if d.Get("paused").(bool) {
+			diags = execLogBackupStatement(ctx, db, "error pausing log backup", "PAUSE BACKUP LOGS")
+		} else {
+			diags = execLogBackupStatement(ctx, db, "error resuming log backup", "RESUME BACKUP LOGS")
+		}
+		if diags != nil {
+			return diags
+		}
+	}
+
+	return ReadLogBackup(ctx, d, meta)
+}
+
+func ReadLogBackup(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	status, err := getLogBackupStatus(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if status == "" {
+		log.Printf("[WARN] Log backup task not found; removing from state")
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("status", status)
+	d.Set("paused", status == "paused")
+
+	return nil
+}
+
+func DeleteLogBackup(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if diags := execLogBackupStatement(ctx, db, "error stopping log backup", "STOP BACKUP LOGS"); diags != nil {
+		return diags
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// getLogBackupStatus returns the lowercased status of the log backup task, or an empty string
+// without a task. The columns of SHOW BACKUP LOGS STATUS differ between TiDB versions, so
+// they're matched by name.
+func getLogBackupStatus(ctx context.Context, db *sql.DB) (string, error) {
+	query := "SHOW BACKUP LOGS STATUS"
+	log.Printf("[DEBUG] SQL: %s", query)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("error reading log backup status: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	status := ""
+	for rows.Next() {
+		row, err := scanRowMap(rows, columns)
+		if err != nil {
+			return "", fmt.Errorf("error scanning log backup status: %w", err)
+		}
+		if status = row["status"]; status == "" {
+			status = row["state"]
+		}
+	}
+	return normalizeLogBackupStatus(status), rows.Err()
+}
+
+// normalizeLogBackupStatus lowercases a status like "● NORMAL" as printed by BR.
+func normalizeLogBackupStatus(status string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimLeft(status, "●○ ")))
+}
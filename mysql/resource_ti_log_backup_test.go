@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestNormalizeLogBackupStatus(t *testing.T) {
+	for in, expected := range map[string]string{
+		"● NORMAL": "normal",
+		"○ paused": "paused",
+		"ERROR":    "error",
+		"":         "",
+	} {
+		if got := normalizeLogBackupStatus(in); got != expected {
+			t.Errorf("normalizeLogBackupStatus(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
+
+func TestAccTiLogBackup_basic(t *testing.T) {
+	storage := os.Getenv("MYSQL_TI_LOG_BACKUP_STORAGE")
+	resourceName := "mysql_ti_log_backup.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, LogBackupTiDBMinVersion)
+			if storage == "" {
+				t.Skip("MYSQL_TI_LOG_BACKUP_STORAGE must be set to a storage URI the TiKV stores can write to")
+			}
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccTiLogBackupCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTiLogBackupConfig(storage, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", logBackupID),
+					resource.TestCheckResourceAttr(resourceName, "paused", "false"),
+					resource.TestCheckResourceAttrSet(resourceName, "status"),
+				),
+			},
+			{
+				Config: testAccTiLogBackupConfig(storage, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "paused", "true"),
+					resource.TestCheckResourceAttr(resourceName, "status", "paused"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTiLogBackupCheckDestroy(s *terraform.State) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+
+	status, err := getLogBackupStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	if status != "" {
+		return fmt.Errorf("log backup task still exists with status %s", status)
+	}
+	return nil
+}
+
+func testAccTiLogBackupConfig(storage string, paused bool) string {
+	return fmt.Sprintf(`
+resource "mysql_ti_log_backup" "test" {
+	storage = "%s"
+	paused  = %t
+}
+`, storage, paused)
+}
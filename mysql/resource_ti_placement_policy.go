@@ -0,0 +1,262 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+type PlacementPolicy struct {
+	Name                string
+	PrimaryRegion       string
+	Regions             string
+	Schedule            string
+	Followers           int
+	Learners            int
+	Constraints         string
+	LeaderConstraints   string
+	FollowerConstraints string
+	LearnerConstraints  string
+}
+
+// placementPolicyOptions are the options of a policy in the order they are written, mapping
+// the option names to the attributes. Followers and learners are numbers, the rest strings.
+var placementPolicyOptions = []struct {
+	option    string
+	attribute string
+}{
+	{"PRIMARY_REGION", "primary_region"},
+	{"REGIONS", "regions"},
+	{"SCHEDULE", "schedule"},
+	{"FOLLOWERS", "followers"},
+	{"LEARNERS", "learners"},
+	{"CONSTRAINTS", "constraints"},
+	{"LEADER_CONSTRAINTS", "leader_constraints"},
+	{"FOLLOWER_CONSTRAINTS", "follower_constraints"},
+	{"LEARNER_CONSTRAINTS", "learner_constraints"},
+}
+
+func (p *PlacementPolicy) values() map[string]interface{} {
+	return map[string]interface{}{
+		"primary_region":       p.PrimaryRegion,
+		"regions":              p.Regions,
+		"schedule":             p.Schedule,
+		"followers":            p.Followers,
+		"learners":             p.Learners,
+		"constraints":          p.Constraints,
+		"leader_constraints":   p.LeaderConstraints,
+		"follower_constraints": p.FollowerConstraints,
+		"learner_constraints":  p.LearnerConstraints,
+	}
+}
+
+// buildSQLQuery builds the CREATE or ALTER statement of the policy. ALTER PLACEMENT POLICY
+// replaces all options, so both list every option that is set.
+func (p *PlacementPolicy) buildSQLQuery(prefix string) string {
+	query := []string{prefix, quoteIdentifier(p.Name)}
+
+	values := p.values()
+	for _, o := range placementPolicyOptions {
+		switch v := values[o.attribute].(type) {
+		case string:
+			if v != "" {
+				query = append(query, fmt.Sprintf("%s=%s", o.option, quoteLiteral(v)))
+			}
+		case int:
+			if v != 0 {
+				query = append(query, fmt.Sprintf("%s=%d", o.option, v))
+			}
+		}
+	}
+
+	return strings.Join(query, " ")
+}
+
+func resourceTiPlacementPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreatePlacementPolicy,
+		ReadContext:   ReadPlacementPolicy,
+		UpdateContext: UpdatePlacementPolicy,
+		DeleteContext: DeletePlacementPolicy,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"primary_region": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"regions": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"schedule": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"EVEN", "MAJORITY_IN_PRIMARY"}, false),
+			},
+			"followers": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"learners": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"constraints": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"leader_constraints": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"follower_constraints": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"learner_constraints": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func CreatePlacementPolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkPlacementPolicySupport(ctx, db); err != nil {
+		return diag.Errorf("cannot create placement policy: %v", err)
+	}
+
+	policy := NewPlacementPolicyFromResourceData(d)
+	query := policy.buildSQLQuery("CREATE PLACEMENT POLICY")
+
+	tflog.SetField(ctx, "query", query)
+	tflog.Debug(ctx, "SQL")
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error creating placement policy (%s)", policy.Name), query, err)
+	}
+
+	d.SetId(policy.Name)
+
+	return ReadPlacementPolicy(ctx, d, meta)
+}
+
+func UpdatePlacementPolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	policy := NewPlacementPolicyFromResourceData(d)
+	query := policy.buildSQLQuery("ALTER PLACEMENT POLICY")
+
+	tflog.SetField(ctx, "query", query)
+	tflog.Debug(ctx, "SQL")
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error altering placement policy (%s)", policy.Name), query, err)
+	}
+
+	return ReadPlacementPolicy(ctx, d, meta)
+}
+
+func ReadPlacementPolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	policy, err := getPlacementPolicyFromDB(ctx, db, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if policy == nil {
+		log.Printf("[WARN] Placement policy (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", policy.Name)
+	for attribute, value := range policy.values() {
+		d.Set(attribute, value)
+	}
+
+	return nil
+}
+
+func DeletePlacementPolicy(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	query := fmt.Sprintf("DROP PLACEMENT POLICY IF EXISTS %s", quoteIdentifier(d.Id()))
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error dropping placement policy (%s)", d.Id()), query, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func getPlacementPolicyFromDB(ctx context.Context, db *sql.DB, name string) (*PlacementPolicy, error) {
+	policy := PlacementPolicy{}
+
+	query := `SELECT POLICY_NAME, PRIMARY_REGION, REGIONS, SCHEDULE, FOLLOWERS, LEARNERS,
+		CONSTRAINTS, LEADER_CONSTRAINTS, FOLLOWER_CONSTRAINTS, LEARNER_CONSTRAINTS
+		FROM information_schema.placement_policies WHERE POLICY_NAME = ?`
+
+	ctx = tflog.SetField(ctx, "query", query)
+	tflog.Debug(ctx, "getPlacementPolicyFromDB")
+
+	err := db.QueryRowContext(ctx, query, name).Scan(
+		&policy.Name, &policy.PrimaryRegion, &policy.Regions, &policy.Schedule, &policy.Followers, &policy.Learners,
+		&policy.Constraints, &policy.LeaderConstraints, &policy.FollowerConstraints, &policy.LearnerConstraints,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error during get placement policy (%s): %s", name, err)
+	}
+
+	return &policy, nil
+}
+
+func NewPlacementPolicyFromResourceData(d *schema.ResourceData) PlacementPolicy {
+	return PlacementPolicy{
+		Name:                d.Get("name").(string),
+		PrimaryRegion:       d.Get("primary_region").(string),
+		Regions:             d.Get("regions").(string),
+		Schedule:            d.Get("schedule").(string),
+		Followers:           d.Get("followers").(int),
+		Learners:            d.Get("learners").(int),
+		Constraints:         d.Get("constraints").(string),
+		LeaderConstraints:   d.Get("leader_constraints").(string),
+		FollowerConstraints: d.Get("follower_constraints").(string),
+		LearnerConstraints:  d.Get("learner_constraints").(string),
+	}
+}
@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestPlacementPolicyBuildSQLQuery(t *testing.T) {
+	policy := PlacementPolicy{
+		Name:          "eu`west",
+		PrimaryRegion: "eu-west-1",
+		Regions:       "eu-west-1,eu-west-2",
+		Followers:     4,
+		Constraints:   "[+disk=ssd]",
+	}
+	expected := "CREATE PLACEMENT POLICY `eu``west` PRIMARY_REGION='eu-west-1' REGIONS='eu-west-1,eu-west-2' FOLLOWERS=4 CONSTRAINTS='[+disk=ssd]'"
+	if got := policy.buildSQLQuery("CREATE PLACEMENT POLICY"); got != expected {
+		t.Errorf("buildSQLQuery = %q, expected %q", got, expected)
+	}
+}
+
+func TestAccTiPlacementPolicy_basic(t *testing.T) {
+	varName := "tf_placement_policy"
+	resourceName := "mysql_ti_placement_policy.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, PlacementPolicyTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccPlacementPolicyCheckDestroy(varName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPlacementPolicyConfig(varName, `followers = 2`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPlacementPolicyExists(varName),
+					resource.TestCheckResourceAttr(resourceName, "followers", "2"),
+					resource.TestCheckResourceAttr(resourceName, "constraints", ""),
+				),
+			},
+			{
+				Config: testAccPlacementPolicyConfig(varName, `followers = 4
+	constraints = "[+disk=ssd]"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPlacementPolicyExists(varName),
+					resource.TestCheckResourceAttr(resourceName, "followers", "4"),
+					resource.TestCheckResourceAttr(resourceName, "constraints", "[+disk=ssd]"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccPlacementPolicyExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		policy, err := getPlacementPolicyFromDB(ctx, db, name)
+		if err != nil {
+			return err
+		}
+		if policy == nil {
+			return fmt.Errorf("placement policy (%s) does not exist", name)
+		}
+		return nil
+	}
+}
+
+func testAccPlacementPolicyCheckDestroy(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		policy, err := getPlacementPolicyFromDB(ctx, db, name)
+		if err != nil {
+			return err
+		}
+		if policy != nil {
+			return fmt.Errorf("placement policy (%s) still exists", name)
+		}
+		return nil
+	}
+}
+
+func testAccPlacementPolicyConfig(name string, options string) string {
+	return fmt.Sprintf(`
+resource "mysql_ti_placement_policy" "test" {
+	name = "%s"
+	%s
+}
+`, name, options)
+}
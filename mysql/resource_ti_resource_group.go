@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
@@ -28,13 +30,15 @@ var UpdateResourceGroupSQLPrefix = "ALTER RESOURCE GROUP"
 
 func (rg *ResourceGroup) buildSQLQuery(prefix string) string {
 	var query []string
-	baseQuery := fmt.Sprintf("%s %s RU_PER_SEC = %d", prefix, rg.Name, rg.ResourceUnits)
+	baseQuery := fmt.Sprintf("%s %s RU_PER_SEC = %d", prefix, quoteIdentifier(rg.Name), rg.ResourceUnits)
 	query = append(query, baseQuery)
 
 	query = append(query, fmt.Sprintf(`PRIORITY = %s`, rg.Priority))
 
 	if rg.QueryLimit != DefaultResourceGroup.QueryLimit {
 		query = append(query, fmt.Sprintf(`QUERY_LIMIT=(%s)`, rg.QueryLimit))
+	} else if prefix == UpdateResourceGroupSQLPrefix {
+		query = append(query, `QUERY_LIMIT=NULL`)
 	}
 
 	query = append(query, fmt.Sprintf(`BURSTABLE = %t`, rg.Burstable))
@@ -55,12 +59,144 @@ var DefaultResourceGroup = ResourceGroup{
 
 var ResourceGroupTiDBMinVersion = "7.5.0"
 
+// QueryLimit is the runaway query handling of a resource group, e.g.
+// QUERY_LIMIT=(EXEC_ELAPSED='60s', ACTION=KILL, WATCH=EXACT DURATION='10m')
+type QueryLimit struct {
+	ExecElapsed string
+	Action      string
+	Watch       string
+	Duration    string
+}
+
+var (
+	queryLimitExecElapsedRe = regexp.MustCompile(`(?i)EXEC_ELAPSED\s*=\s*'([^']*)'`)
+	queryLimitActionRe      = regexp.MustCompile(`(?i)ACTION\s*=\s*(\w+)`)
+	queryLimitWatchRe       = regexp.MustCompile(`(?i)WATCH\s*=\s*(\w+)`)
+	queryLimitDurationRe    = regexp.MustCompile(`(?i)DURATION\s*=\s*'([^']*)'`)
+)
+
+// parseQueryLimit parses the QUERY_LIMIT of information_schema.resource_groups, or an empty
+// string for none.
+func parseQueryLimit(in string) *QueryLimit {
+	if strings.TrimSpace(in) == "" {
+		return nil
+	}
+
+	submatch := func(re *regexp.Regexp) string {
+		if m := re.FindStringSubmatch(in); m != nil {
+			return m[1]
+		}
+		return ""
+	}
+
+	return &QueryLimit{
+		ExecElapsed: normalizeDuration(submatch(queryLimitExecElapsedRe)),
+		Action:      strings.ToUpper(submatch(queryLimitActionRe)),
+		Watch:       strings.ToUpper(submatch(queryLimitWatchRe)),
+		Duration:    normalizeDuration(submatch(queryLimitDurationRe)),
+	}
+}
+
+// normalizeDuration formats a duration like TiDB does, e.g. 10m as 10m0s.
+func normalizeDuration(in string) string {
+	if d, err := time.ParseDuration(in); err == nil {
+		return d.String()
+	}
+	return in
+}
+
+// String serializes the options of the query limit in a fixed order. The duration of the
+// watch is only valid with a watch.
+func (ql *QueryLimit) String() string {
+	options := []string{
+		fmt.Sprintf("EXEC_ELAPSED='%s'", normalizeDuration(ql.ExecElapsed)),
+		fmt.Sprintf("ACTION=%s", strings.ToUpper(ql.Action)),
+	}
+	if ql.Watch != "" {
+		watch := fmt.Sprintf("WATCH=%s", strings.ToUpper(ql.Watch))
+		if ql.Duration != "" {
+			watch += fmt.Sprintf(" DURATION='%s'", normalizeDuration(ql.Duration))
+		}
+		options = append(options, watch)
+	}
+	return strings.Join(options, ", ")
+}
+
+func queryLimitToList(ql *QueryLimit) []interface{} {
+	if ql == nil {
+		return []interface{}{}
+	}
+	return []interface{}{map[string]interface{}{
+		"exec_elapsed": ql.ExecElapsed,
+		"action":       ql.Action,
+		"watch":        ql.Watch,
+		"duration":     ql.Duration,
+	}}
+}
+
+func queryLimitFromList(list []interface{}) *QueryLimit {
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	m := list[0].(map[string]interface{})
+	return &QueryLimit{
+		ExecElapsed: m["exec_elapsed"].(string),
+		Action:      m["action"].(string),
+		Watch:       m["watch"].(string),
+		Duration:    m["duration"].(string),
+	}
+}
+
+func durationSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeDuration(old) == normalizeDuration(new)
+}
+
+func validateDuration(val interface{}, key string) (warns []string, errs []error) {
+	if _, err := time.ParseDuration(val.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a duration like 60s or 10m, got %q", key, val))
+	}
+	return
+}
+
+func queryLimitSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"exec_elapsed": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationSuppressFunc,
+			},
+			"action": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"DRYRUN", "COOLDOWN", "KILL"}, true),
+				DiffSuppressFunc: caseInsensitiveSuppressFunc,
+			},
+			"watch": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"EXACT", "SIMILAR", "PLAN"}, true),
+				DiffSuppressFunc: caseInsensitiveSuppressFunc,
+			},
+			"duration": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationSuppressFunc,
+			},
+		},
+	}
+}
+
 func resourceTiResourceGroup() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateResourceGroup,
 		ReadContext:   ReadResourceGroup,
 		UpdateContext: UpdateResourceGroup,
 		DeleteContext: DeleteResourceGroup,
+		CustomizeDiff: customizeDiffResourceGroup,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -70,11 +206,20 @@ func resourceTiResourceGroup() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
-			// TODO: allow a centralized way to check if there's capacity remaining to use
 			"resource_units": {
 				Type:     schema.TypeInt,
 				Required: true,
 			},
+			"check_capacity": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"resource_units_budget": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"priority": {
 				Type:         schema.TypeString,
 				Default:      DefaultResourceGroup.Priority,
@@ -88,19 +233,97 @@ func resourceTiResourceGroup() *schema.Resource {
 				ForceNew: false,
 				Optional: true,
 			},
-			/*
-				QUERY_LIMIT=(EXEC_ELAPSED='60s', ACTION=KILL, WATCH=EXACT DURATION='10m')
-			*/
 			"query_limit": {
-				Type:     schema.TypeString,
-				Default:  DefaultResourceGroup.QueryLimit,
-				ForceNew: false,
+				Type:     schema.TypeList,
 				Optional: true,
+				MaxItems: 1,
+				Elem:     queryLimitSchema(),
+			},
+		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceTiResourceGroupV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceTiResourceGroupStateUpgradeV0,
+				Version: 0,
 			},
 		},
 	}
 }
 
+// resourceTiResourceGroupV0 is the schema of version 0, in which query_limit was a string.
+func resourceTiResourceGroupV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":           {Type: schema.TypeString, Required: true},
+			"resource_units": {Type: schema.TypeInt, Required: true},
+			"priority":       {Type: schema.TypeString, Optional: true},
+			"burstable":      {Type: schema.TypeBool, Optional: true},
+			"query_limit":    {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// resourceTiResourceGroupStateUpgradeV0 converts the query_limit string to a block.
+func resourceTiResourceGroupStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	queryLimit, _ := rawState["query_limit"].(string)
+	rawState["query_limit"] = queryLimitToList(parseQueryLimit(queryLimit))
+	return rawState, nil
+}
+
+// customizeDiffResourceGroup fails the plan when the RU quotas of all groups would exceed the
+// budget, or the capacity of the cluster estimated by CALIBRATE RESOURCE.
+func customizeDiffResourceGroup(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	budget := d.Get("resource_units_budget").(int)
+	if budget == 0 && !d.Get("check_capacity").(bool) {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChanges("resource_units", "resource_units_budget", "check_capacity") {
+		return nil
+	}
+	if !d.NewValueKnown("resource_units") || !d.NewValueKnown("name") {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	groups, err := listResourceGroupsFromDB(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	resourceUnits := d.Get("resource_units").(int)
+	var others []ResourceGroup
+	for _, rg := range groups {
+		if !strings.EqualFold(rg.Name, name) {
+			others = append(others, rg)
+		}
+	}
+	total := totalResourceUnits(others) + resourceUnits
+
+	limit, limitName := budget, "resource_units_budget"
+	if budget == 0 {
+		limit, limitName = calibrateResourceUnits(ctx, db), "estimated RU capacity of the cluster"
+		if limit == 0 {
+			return fmt.Errorf("cannot check the capacity for resource group %s: TiDB could not estimate the RU capacity of the cluster; set resource_units_budget instead", name)
+		}
+	}
+
+	if total > limit {
+		return fmt.Errorf("resource group %s would oversubscribe the cluster: the RU_PER_SEC of all groups would be %d, over the %s of %d (%d RU/s available for this group)",
+			name, total, limitName, limit, max(limit-total+resourceUnits, 0))
+	}
+	return nil
+}
+
 func CreateResourceGroup(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -118,7 +341,7 @@ func CreateResourceGroup(ctx context.Context, d *schema.ResourceData, meta inter
 
 	_, err = db.ExecContext(ctx, query)
 	if err != nil {
-		return diag.Errorf("error creating resource group (%s): %s", rg.Name, err)
+		return sqlErrorDiag(fmt.Sprintf("error creating resource group (%s)", rg.Name), query, err)
 	}
 
 	db.QueryRowContext(ctx, "SHOW WARNINGS").Scan(&warnLevel, &warnCode, &warnMessage)
@@ -149,7 +372,7 @@ func UpdateResourceGroup(ctx context.Context, d *schema.ResourceData, meta inter
 
 	_, err = db.ExecContext(ctx, query)
 	if err != nil {
-		return diag.Errorf("error altering resource group (%s): %s", rg.Name, err)
+		return sqlErrorDiag(fmt.Sprintf("error altering resource group (%s)", rg.Name), query, err)
 	}
 
 	db.QueryRowContext(ctx, "SHOW WARNINGS").Scan(&warnLevel, &warnCode, &warnMessage)
@@ -168,15 +391,15 @@ func ReadResourceGroup(ctx context.Context, d *schema.ResourceData, meta interfa
 		return diag.FromErr(err)
 	}
 
-	rg, err := getResourceGroupFromDB(db, d.Id())
+	rg, err := getResourceGroupFromDB(ctx, db, d.Id())
 	if err != nil {
-		d.SetId("")
 		return diag.Errorf("error during get resource group (%s): %s", d.Id(), err)
 	}
 
 	// If we're not able to find the resource group, assume that there's terraform
 	// diff and allow terraform to recreate it instead of throwing an error.
 	if rg == nil {
+		log.Printf("[WARN] Resource group (%s) not found; removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
@@ -193,8 +416,8 @@ func DeleteResourceGroup(ctx context.Context, d *schema.ResourceData, meta inter
 		return diag.FromErr(err)
 	}
 
-	deleteQuery := fmt.Sprintf("DROP RESOURCE GROUP IF EXISTS %s", name)
-	_, err = db.Exec(deleteQuery)
+	deleteQuery := fmt.Sprintf("DROP RESOURCE GROUP IF EXISTS %s", quoteIdentifier(name))
+	_, err = db.ExecContext(ctx, deleteQuery)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return diag.Errorf("error during drop resource group (%s): %s", d.Id(), err)
 	}
@@ -203,7 +426,7 @@ func DeleteResourceGroup(ctx context.Context, d *schema.ResourceData, meta inter
 	return nil
 }
 
-func getResourceGroupFromDB(db *sql.DB, name string) (*ResourceGroup, error) {
+func getResourceGroupFromDB(ctx context.Context, db *sql.DB, name string) (*ResourceGroup, error) {
 	rg := ResourceGroup{Name: name}
 
 	/*
@@ -212,13 +435,12 @@ func getResourceGroupFromDB(db *sql.DB, name string) (*ResourceGroup, error) {
 		QUERY_LIMIT is nullable in DB, but we coerce to standard "empty" string type of ""
 		Lowercase priority for less configuration variability
 	*/
-	query := `SELECT NAME, RU_PER_SEC, LOWER(PRIORITY), BURSTABLE = 'YES' as BURSTABLE, IFNULL(QUERY_LIMIT,"") FROM information_schema.resource_groups WHERE NAME = ?`
+	query := `SELECT NAME, RU_PER_SEC, LOWER(PRIORITY), BURSTABLE = 'YES' as BURSTABLE, IFNULL(QUERY_LIMIT,'') FROM information_schema.resource_groups WHERE NAME = ?`
 
-	ctx := context.Background()
-	tflog.SetField(ctx, "query", query)
+	ctx = tflog.SetField(ctx, "query", query)
 	tflog.Debug(ctx, "getResourceGroupFromDB")
 
-	err := db.QueryRow(query, name).Scan(&rg.Name, &rg.ResourceUnits, &rg.Priority, &rg.Burstable, &rg.QueryLimit)
+	err := db.QueryRowContext(ctx, query, name).Scan(&rg.Name, &rg.ResourceUnits, &rg.Priority, &rg.Burstable, &rg.QueryLimit)
 	if errors.Is(err, sql.ErrNoRows) {
 		log.Printf("[DEBUG] resource group doesn't exist (%s): %s", name, err)
 		return nil, nil
@@ -229,13 +451,40 @@ func getResourceGroupFromDB(db *sql.DB, name string) (*ResourceGroup, error) {
 	return &rg, nil
 }
 
+// listResourceGroupsFromDB returns all resource groups ordered by name. The query limit isn't
+// read.
+func listResourceGroupsFromDB(ctx context.Context, db *sql.DB) ([]ResourceGroup, error) {
+	query := `SELECT NAME, RU_PER_SEC, LOWER(PRIORITY), BURSTABLE = 'YES' FROM information_schema.resource_groups ORDER BY NAME`
+
+	ctx = tflog.SetField(ctx, "query", query)
+	tflog.Debug(ctx, "listResourceGroupsFromDB")
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing resource groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []ResourceGroup
+	for rows.Next() {
+		var rg ResourceGroup
+		var ruPerSec string
+		if err := rows.Scan(&rg.Name, &ruPerSec, &rg.Priority, &rg.Burstable); err != nil {
+			return nil, fmt.Errorf("error scanning resource groups: %w", err)
+		}
+		rg.ResourceUnits = parseResourceUnits(ruPerSec)
+		groups = append(groups, rg)
+	}
+	return groups, rows.Err()
+}
+
 func NewResourceGroupFromResourceData(d *schema.ResourceData) ResourceGroup {
 	return ResourceGroup{
 		Name:          d.Get("name").(string),
 		ResourceUnits: d.Get("resource_units").(int),
 		Priority:      strings.ToUpper(d.Get("priority").(string)),
 		Burstable:     d.Get("burstable").(bool),
-		QueryLimit:    d.Get("query_limit").(string),
+		QueryLimit:    queryLimitString(d.Get("query_limit").([]interface{})),
 	}
 }
 
@@ -244,5 +493,12 @@ func setResourceGroupOnResourceData(rg ResourceGroup, d *schema.ResourceData) {
 	d.Set("resource_units", rg.ResourceUnits)
 	d.Set("priority", rg.Priority)
 	d.Set("burstable", rg.Burstable)
-	d.Set("query_limit", rg.QueryLimit)
+	d.Set("query_limit", queryLimitToList(parseQueryLimit(rg.QueryLimit)))
+}
+
+func queryLimitString(list []interface{}) string {
+	if ql := queryLimitFromList(list); ql != nil {
+		return ql.String()
+	}
+	return ""
 }
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -17,7 +19,12 @@ func TestTIDBResourceGroup_basic(t *testing.T) {
 	varResourceUnits := 100
 	varNewResourceUnits := 1000
 	varQueryLimit := ""
-	varNewQueryLimit := "EXEC_ELAPSED='15s', ACTION=COOLDOWN, WATCH=SIMILAR DURATION='10m0s'"
+	varNewQueryLimit := `query_limit {
+		exec_elapsed = "15s"
+		action       = "cooldown"
+		watch        = "SIMILAR"
+		duration     = "10m"
+	}`
 	varBurstable := true
 	varPriority := "low"
 	resourceName := "mysql_ti_resource_group.test"
@@ -36,7 +43,7 @@ func TestTIDBResourceGroup_basic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccResourceGroupExists(varName),
 					resource.TestCheckResourceAttr(resourceName, "name", varName),
-					resource.TestCheckResourceAttr(resourceName, "query_limit", varQueryLimit),
+					resource.TestCheckResourceAttr(resourceName, "query_limit.#", "0"),
 				),
 			},
 			{
@@ -44,7 +51,10 @@ func TestTIDBResourceGroup_basic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccResourceGroupExists(varName),
 					resource.TestCheckResourceAttr(resourceName, "name", varName),
-					resource.TestCheckResourceAttr(resourceName, "query_limit", varNewQueryLimit),
+					resource.TestCheckResourceAttr(resourceName, "query_limit.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "query_limit.0.exec_elapsed", "15s"),
+					resource.TestCheckResourceAttr(resourceName, "query_limit.0.action", "COOLDOWN"),
+					resource.TestCheckResourceAttr(resourceName, "query_limit.0.duration", "10m0s"),
 					resource.TestCheckResourceAttr(resourceName, "burstable", fmt.Sprintf("%t", varBurstable)),
 					resource.TestCheckResourceAttr(resourceName, "priority", varPriority),
 				),
@@ -53,6 +63,74 @@ func TestTIDBResourceGroup_basic(t *testing.T) {
 	})
 }
 
+func TestTIDBResourceGroup_budget(t *testing.T) {
+	varName := "tf-test-rg-budget"
+	resourceName := "mysql_ti_resource_group.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, ResourceGroupTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccResourceGroupCheckDestroy(varName),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourceGroupConfigBasic(varName, 100, "resource_units_budget = 50"),
+				ExpectError: regexp.MustCompile("would oversubscribe the cluster"),
+			},
+			{
+				Config: testAccResourceGroupConfigBasic(varName, 100, "resource_units_budget = 100000000"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceGroupExists(varName),
+					resource.TestCheckResourceAttr(resourceName, "resource_units_budget", "100000000"),
+				),
+			},
+		},
+	})
+}
+
+func TestQueryLimit(t *testing.T) {
+	for _, tt := range []struct {
+		in       string
+		expected string
+	}{
+		{"EXEC_ELAPSED='15s', ACTION=COOLDOWN, WATCH=SIMILAR DURATION='10m0s'", "EXEC_ELAPSED='15s', ACTION=COOLDOWN, WATCH=SIMILAR DURATION='10m0s'"},
+		{"action=kill,  exec_elapsed = '60s'", "EXEC_ELAPSED='1m0s', ACTION=KILL"},
+		{"EXEC_ELAPSED='1m', ACTION=DRYRUN, WATCH=EXACT", "EXEC_ELAPSED='1m0s', ACTION=DRYRUN, WATCH=EXACT"},
+	} {
+		if got := parseQueryLimit(tt.in).String(); got != tt.expected {
+			t.Errorf("parseQueryLimit(%q) = %q, expected %q", tt.in, got, tt.expected)
+		}
+	}
+
+	if ql := parseQueryLimit(""); ql != nil {
+		t.Errorf("parseQueryLimit(\"\") = %+v, expected nil", ql)
+	}
+}
+
+func TestResourceTiResourceGroupStateUpgradeV0(t *testing.T) {
+	state, err := resourceTiResourceGroupStateUpgradeV0(context.Background(), map[string]interface{}{
+		"id":          "rg",
+		"name":        "rg",
+		"query_limit": "EXEC_ELAPSED='15s', ACTION=KILL",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{map[string]interface{}{
+		"exec_elapsed": "15s",
+		"action":       "KILL",
+		"watch":        "",
+		"duration":     "",
+	}}
+	if !reflect.DeepEqual(state["query_limit"], expected) {
+		t.Errorf("query_limit = %#v, expected %#v", state["query_limit"], expected)
+	}
+}
+
 func testAccResourceGroupExists(varName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rg, err := getResourceGroup(varName)
@@ -109,7 +187,7 @@ func testAccResourceGroupConfigBasic(varName string, varResourceUnits int, varQu
 resource "mysql_ti_resource_group" "test" {
 		name = "%s"
 		resource_units = %d
-		query_limit = "%s"
+		%s
 }
 `, varName, varResourceUnits, varQueryLimit)
 }
@@ -121,7 +199,7 @@ resource "mysql_ti_resource_group" "test" {
 		resource_units = %d
 		priority = "%s"
 		burstable = %t
-		query_limit = "%s"
+		%s
 }
 `, varName, varResourceUnits, varPriority, varBurstable, varQueryLimit)
 }
@@ -18,6 +18,7 @@ func resourceTiResourceGroupUserAssignment() *schema.Resource {
 		ReadContext:   ReadResourceGroupUser,
 		UpdateContext: CreateOrUpdateResourceGroupUser,
 		DeleteContext: DeleteResourceGroupUser,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -48,7 +49,7 @@ func CreateOrUpdateResourceGroupUser(ctx context.Context, d *schema.ResourceData
 	var warnLevel, warnMessage string
 	var warnCode int = 0
 
-	currentUser, _, err := readUserFromDB(db, user)
+	currentUser, _, err := readUserFromDB(ctx, db, user)
 	if err != nil {
 		d.SetId("")
 		return diag.Errorf(`error during get user (%s): %s`, user, err)
@@ -59,13 +60,13 @@ func CreateOrUpdateResourceGroupUser(ctx context.Context, d *schema.ResourceData
 		return diag.Errorf(`must create user first before assigning to resource group | getting user %s | error %s`, currentUser, err)
 	}
 
-	sql := fmt.Sprintf("ALTER USER `%s` RESOURCE GROUP `%s`", user, resourceGroup)
+	sql := fmt.Sprintf("ALTER USER %s RESOURCE GROUP %s", quoteLiteral(user), quoteIdentifier(resourceGroup))
 	log.Printf("[DEBUG] SQL: %s\n", sql)
 
 	_, err = db.ExecContext(ctx, sql)
 	if err != nil {
 		d.SetId("")
-		return diag.Errorf("error attaching user (%s) to resource group (%s): %s", user, resourceGroup, err)
+		return sqlErrorDiag(fmt.Sprintf("error attaching user (%s) to resource group (%s)", user, resourceGroup), sql, err)
 	}
 
 	db.QueryRowContext(ctx, "SHOW WARNINGS").Scan(&warnLevel, &warnCode, &warnMessage)
@@ -86,15 +87,15 @@ func ReadResourceGroupUser(ctx context.Context, d *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 
-	user, resourceGroup, err = readUserFromDB(db, d.Id())
+	user, resourceGroup, err = readUserFromDB(ctx, db, d.Id())
 	if err != nil {
-		d.SetId("")
 		return diag.Errorf(`error getting user %s`, err)
 	}
 
 	// If the user doesn't exist, instead of erroring, recognize that there's
 	// terraform drift and attempt to create the assignment again.
 	if user == "" {
+		log.Printf("[WARN] User (%s) not found; removing resource group assignment from state", d.Id())
 		d.SetId("")
 		return nil
 	}
@@ -113,8 +114,8 @@ func DeleteResourceGroupUser(ctx context.Context, d *schema.ResourceData, meta i
 		return diag.FromErr(err)
 	}
 
-	deleteQuery := fmt.Sprintf("ALTER USER `%s` RESOURCE GROUP `default`", user)
-	_, err = db.Exec(deleteQuery)
+	deleteQuery := fmt.Sprintf("ALTER USER %s RESOURCE GROUP `default`", quoteLiteral(user))
+	_, err = db.ExecContext(ctx, deleteQuery)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return diag.Errorf("error during drop resource group (%s): %s", d.Id(), err)
 	}
@@ -123,9 +124,9 @@ func DeleteResourceGroupUser(ctx context.Context, d *schema.ResourceData, meta i
 	return nil
 }
 
-func readUserFromDB(db *sql.DB, name string) (string, string, error) {
-	selectUsersQuery := `SELECT USER, JSON_UNQUOTE(IFNULL(JSON_EXTRACT(User_attributes, "$.resource_group"), "")) as resource_group FROM mysql.user WHERE USER = ?`
-	row := db.QueryRow(selectUsersQuery, name)
+func readUserFromDB(ctx context.Context, db *sql.DB, name string) (string, string, error) {
+	selectUsersQuery := `SELECT USER, JSON_UNQUOTE(IFNULL(JSON_EXTRACT(User_attributes, '$.resource_group'), '')) as resource_group FROM mysql.user WHERE USER = ?`
+	row := db.QueryRowContext(ctx, selectUsersQuery, name)
 
 	var user, resourceGroup string
 
@@ -45,7 +45,7 @@ func testAccResourceGroupUserAssignmentExists(username string, resourceGroupName
 			return err
 		}
 
-		user, resourceGroup, err := readUserFromDB(db, username)
+		user, resourceGroup, err := readUserFromDB(ctx, db, username)
 		if err != nil {
 			return err
 		}
@@ -78,7 +78,7 @@ resource "mysql_user" "test" {
 resource "mysql_ti_resource_group" "test" {
 	name = "%s"
 	resource_units = %d
-	query_limit = "%s"
+	%s
 }
 
 resource "mysql_ti_resource_group_user_assignment" "test" {
@@ -0,0 +1,183 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceTiResourceGroupUsers manages the complete set of users of a resource group, unlike
+// mysql_ti_resource_group_user_assignment, which assigns a single user.
+func resourceTiResourceGroupUsers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateOrUpdateResourceGroupUsers,
+		ReadContext:   ReadResourceGroupUsers,
+		UpdateContext: CreateOrUpdateResourceGroupUsers,
+		DeleteContext: DeleteResourceGroupUsers,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportResourceGroupUsers,
+		},
+		Schema: map[string]*schema.Schema{
+			"resource_group": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"users": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func assignResourceGroupUser(ctx context.Context, db *sql.DB, user, resourceGroup string) diag.Diagnostics {
+	stmtSQL := fmt.Sprintf("ALTER USER %s RESOURCE GROUP %s", quoteLiteral(user), quoteIdentifier(resourceGroup))
+	log.Printf("[DEBUG] SQL: %s\n", stmtSQL)
+
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error attaching user (%s) to resource group (%s)", user, resourceGroup), stmtSQL, err)
+	}
+	return nil
+}
+
+func CreateOrUpdateResourceGroupUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resourceGroup := d.Get("resource_group").(string)
+
+	current, err := readResourceGroupUsersFromDB(ctx, db, resourceGroup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	desired := map[string]bool{}
+	for _, user := range d.Get("users").(*schema.Set).List() {
+		desired[user.(string)] = true
+	}
+
+	for _, user := range sortedKeys(desired) {
+		if current[user] {
+			continue
+		}
+		if existing, _, err := readUserFromDB(ctx, db, user); err != nil {
+			return diag.Errorf("error during get user (%s): %s", user, err)
+		} else if existing == "" {
+			return diag.Errorf("must create user %s first before assigning to resource group", user)
+		}
+		if diags := assignResourceGroupUser(ctx, db, user, resourceGroup); diags != nil {
+			return diags
+		}
+	}
+
+	for _, user := range sortedKeys(current) {
+		if desired[user] {
+			continue
+		}
+		if diags := assignResourceGroupUser(ctx, db, user, "default"); diags != nil {
+			return diags
+		}
+	}
+
+	d.SetId(resourceGroup)
+
+	return ReadResourceGroupUsers(ctx, d, meta)
+}
+
+func ReadResourceGroupUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rg, err := getResourceGroupFromDB(ctx, db, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if rg == nil {
+		log.Printf("[WARN] Resource group (%s) not found; removing users from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	users, err := readResourceGroupUsersFromDB(ctx, db, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("resource_group", d.Id())
+	d.Set("users", sortedKeys(users))
+
+	return nil
+}
+
+// DeleteResourceGroupUsers moves all users of the resource group back to the default group.
+func DeleteResourceGroupUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	users, err := readResourceGroupUsersFromDB(ctx, db, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, user := range sortedKeys(users) {
+		if diags := assignResourceGroupUser(ctx, db, user, "default"); diags != nil {
+			return diags
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportResourceGroupUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("resource_group", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+// readResourceGroupUsersFromDB returns the users assigned to the resource group. TiDB stores
+// resource group names in lowercase.
+func readResourceGroupUsersFromDB(ctx context.Context, db *sql.DB, resourceGroup string) (map[string]bool, error) {
+	query := `SELECT DISTINCT USER FROM mysql.user WHERE LOWER(JSON_UNQUOTE(JSON_EXTRACT(User_attributes, '$.resource_group'))) = LOWER(?)`
+	log.Printf("[DEBUG] SQL: %s\n", query)
+
+	rows, err := db.QueryContext(ctx, query, resourceGroup)
+	if err != nil {
+		return nil, fmt.Errorf("error reading users of resource group (%s): %w", resourceGroup, err)
+	}
+	defer rows.Close()
+
+	users := map[string]bool{}
+	for rows.Next() {
+		var user string
+		if err := rows.Scan(&user); err != nil {
+			return nil, fmt.Errorf("error reading users of resource group (%s): %w", resourceGroup, err)
+		}
+		users[user] = true
+	}
+
+	return users, rows.Err()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
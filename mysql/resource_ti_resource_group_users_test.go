@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceGroupUsers_basic(t *testing.T) {
+	rgName := "tf-test-rg-users"
+	resourceName := "mysql_ti_resource_group_users.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, ResourceGroupTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceGroupUsersConfig(rgName, `[mysql_user.a.user, mysql_user.b.user]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceGroupUsers(rgName, "jdoe-rg-a", "jdoe-rg-b"),
+					resource.TestCheckResourceAttr(resourceName, "users.#", "2"),
+				),
+			},
+			{
+				Config: testAccResourceGroupUsersConfig(rgName, `[mysql_user.b.user]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceGroupUsers(rgName, "jdoe-rg-b"),
+					resource.TestCheckResourceAttr(resourceName, "users.#", "1"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceGroupUsers(resourceGroup string, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		users, err := readResourceGroupUsersFromDB(ctx, db, resourceGroup)
+		if err != nil {
+			return err
+		}
+		if len(users) != len(expected) {
+			return fmt.Errorf("resource group (%s) has users %v, expected %v", resourceGroup, sortedKeys(users), expected)
+		}
+		for _, user := range expected {
+			if !users[user] {
+				return fmt.Errorf("user (%s) isn't assigned to resource group (%s)", user, resourceGroup)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccResourceGroupUsersConfig(rgName string, users string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "a" {
+	user = "jdoe-rg-a"
+	host = "%%"
+}
+
+resource "mysql_user" "b" {
+	user = "jdoe-rg-b"
+	host = "%%"
+}
+
+resource "mysql_ti_resource_group" "test" {
+	name           = "%s"
+	resource_units = 100
+}
+
+resource "mysql_ti_resource_group_users" "test" {
+	resource_group = mysql_ti_resource_group.test.name
+	users          = %s
+}
+`, rgName, users)
+}
@@ -0,0 +1,237 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var SequenceTiDBMinVersion = "4.0.0"
+
+// Sequence is a row of information_schema.sequences on TiDB.
+type Sequence struct {
+	Increment int64
+	MinValue  int64
+	MaxValue  int64
+	Start     int64
+	Cache     int64
+	Cycle     bool
+	Comment   string
+}
+
+func resourceTiSequence() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateSequence,
+		ReadContext:   ReadSequence,
+		UpdateContext: UpdateSequence,
+		DeleteContext: DeleteSequence,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportSequence,
+		},
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"increment": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntNotInSlice([]int{0}),
+			},
+			"min_value": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"max_value": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"start": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"cache": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"cycle": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// sequenceOptions returns the options of the sequence for CREATE and ALTER SEQUENCE. Unset
+// bounds and start are left to the server defaults.
+func sequenceOptions(d *schema.ResourceData) string {
+	options := []string{fmt.Sprintf("INCREMENT BY %d", d.Get("increment").(int))}
+
+	if v, ok := d.GetOk("min_value"); ok {
+		options = append(options, fmt.Sprintf("MINVALUE %d", v.(int)))
+	}
+	if v, ok := d.GetOk("max_value"); ok {
+		options = append(options, fmt.Sprintf("MAXVALUE %d", v.(int)))
+	}
+	if v, ok := d.GetOk("start"); ok {
+		options = append(options, fmt.Sprintf("START WITH %d", v.(int)))
+	}
+
+	if cache := d.Get("cache").(int); cache > 0 {
+		options = append(options, fmt.Sprintf("CACHE %d", cache))
+	} else {
+		options = append(options, "NOCACHE")
+	}
+
+	if d.Get("cycle").(bool) {
+		options = append(options, "CYCLE")
+	} else {
+		options = append(options, "NOCYCLE")
+	}
+
+	options = append(options, "COMMENT = "+quoteLiteral(d.Get("comment").(string)))
+
+	return strings.Join(options, " ")
+}
+
+func sequenceName(d *schema.ResourceData) string {
+	return fmt.Sprintf("%s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("name").(string)))
+}
+
+func CreateSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "sequences", SequenceTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot create sequence: %v", err)
+	}
+
+	stmtSQL := fmt.Sprintf("CREATE SEQUENCE %s %s", sequenceName(d), sequenceOptions(d))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag("error creating sequence", stmtSQL, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s.%s", d.Get("database").(string), d.Get("name").(string)))
+
+	return ReadSequence(ctx, d, meta)
+}
+
+func UpdateSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("ALTER SEQUENCE %s %s", sequenceName(d), sequenceOptions(d))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error altering sequence (%s)", d.Id()), stmtSQL, err)
+	}
+
+	return ReadSequence(ctx, d, meta)
+}
+
+func ReadSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database := d.Get("database").(string)
+	name := d.Get("name").(string)
+
+	sequence, err := getSequenceFromDB(ctx, db, database, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if sequence == nil {
+		log.Printf("[WARN] Sequence (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("increment", sequence.Increment)
+	d.Set("min_value", sequence.MinValue)
+	d.Set("max_value", sequence.MaxValue)
+	d.Set("start", sequence.Start)
+	d.Set("cache", sequence.Cache)
+	d.Set("cycle", sequence.Cycle)
+	d.Set("comment", sequence.Comment)
+
+	return nil
+}
+
+func DeleteSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP SEQUENCE IF EXISTS %s", sequenceName(d))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error dropping sequence (%s)", d.Id()), stmtSQL, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func ImportSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, name, ok := strings.Cut(d.Id(), ".")
+	if !ok || database == "" || name == "" {
+		return nil, fmt.Errorf("wrong ID format %s - expected database.name", d.Id())
+	}
+
+	d.Set("database", database)
+	d.Set("name", name)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func getSequenceFromDB(ctx context.Context, db *sql.DB, database, name string) (*Sequence, error) {
+	query := `SELECT INCREMENT, MIN_VALUE, MAX_VALUE, START, IF(CACHE, CACHE_VALUE, 0), CYCLE, IFNULL(COMMENT, '')
+		FROM information_schema.sequences WHERE SEQUENCE_SCHEMA = ? AND SEQUENCE_NAME = ?`
+	log.Println("[DEBUG] Executing query:", query)
+
+	var sequence Sequence
+	err := db.QueryRowContext(ctx, query, database, name).Scan(
+		&sequence.Increment, &sequence.MinValue, &sequence.MaxValue, &sequence.Start, &sequence.Cache, &sequence.Cycle, &sequence.Comment,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error during get sequence %s.%s: %w", database, name, err)
+	}
+
+	return &sequence, nil
+}
@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTiSequence_basic(t *testing.T) {
+	dbName := "tf_sequence"
+	resourceName := "mysql_ti_sequence.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, SequenceTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSequenceCheckDestroy(dbName, "seq"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSequenceConfig(dbName, `increment = 2`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSequenceExists(dbName, "seq"),
+					resource.TestCheckResourceAttr(resourceName, "increment", "2"),
+					resource.TestCheckResourceAttr(resourceName, "min_value", "1"),
+					resource.TestCheckResourceAttr(resourceName, "cache", "1000"),
+					resource.TestCheckResourceAttr(resourceName, "cycle", "false"),
+				),
+			},
+			{
+				Config: testAccSequenceConfig(dbName, `start = 10
+	min_value = 10
+	max_value = 100
+	cache     = 0
+	cycle     = true
+	comment   = "order numbers"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSequenceExists(dbName, "seq"),
+					resource.TestCheckResourceAttr(resourceName, "max_value", "100"),
+					resource.TestCheckResourceAttr(resourceName, "cache", "0"),
+					resource.TestCheckResourceAttr(resourceName, "cycle", "true"),
+					resource.TestCheckResourceAttr(resourceName, "comment", "order numbers"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccSequenceExists(database, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		sequence, err := getSequenceFromDB(ctx, db, database, name)
+		if err != nil {
+			return err
+		}
+		if sequence == nil {
+			return fmt.Errorf("sequence %s.%s does not exist", database, name)
+		}
+		return nil
+	}
+}
+
+func testAccSequenceCheckDestroy(database, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		sequence, err := getSequenceFromDB(ctx, db, database, name)
+		if err != nil {
+			return err
+		}
+		if sequence != nil {
+			return fmt.Errorf("sequence %s.%s still exists", database, name)
+		}
+		return nil
+	}
+}
+
+func testAccSequenceConfig(dbName string, options string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+	name = "%s"
+}
+
+resource "mysql_ti_sequence" "test" {
+	database = mysql_database.test.name
+	name     = "seq"
+	%s
+}
+`, dbName, options)
+}
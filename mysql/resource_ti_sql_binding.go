@@ -0,0 +1,239 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var SQLBindingTiDBMinVersion = "6.0.0"
+
+// SQLBinding is a row of SHOW GLOBAL BINDINGS.
+type SQLBinding struct {
+	OriginalSQL string
+	BindSQL     string
+	DefaultDB   string
+	Status      string
+	UpdateTime  string
+	SQLDigest   string
+}
+
+func resourceTiSQLBinding() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateSQLBinding,
+		ReadContext:   ReadSQLBinding,
+		DeleteContext: DeleteSQLBinding,
+		Timeouts:      defaultResourceTimeouts(false),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportSQLBinding,
+		},
+		Schema: map[string]*schema.Schema{
+			"original_sql": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"bind_sql": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"sql_digest": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"normalized_sql": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// sqlBindingStatement returns the CREATE or DROP statement of a binding.
+func sqlBindingStatement(prefix, originalSQL, bindSQL string) string {
+	return fmt.Sprintf("%s GLOBAL BINDING FOR %s USING %s",
+		prefix, strings.TrimRight(strings.TrimSpace(originalSQL), ";"), strings.TrimRight(strings.TrimSpace(bindSQL), ";"))
+}
+
+// execSQLBindingStatement executes a binding statement with the database as the default
+// database, which the tables of the statements are resolved in.
+func execSQLBindingStatement(ctx context.Context, db *sql.DB, database, stmtSQL string) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if database != "" {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("USE %s", quoteIdentifier(database))); err != nil {
+			return err
+		}
+	}
+
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	_, err = conn.ExecContext(ctx, stmtSQL)
+	return err
+}
+
+func CreateSQLBinding(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkTiDBFeatureSupport(ctx, db, "SQL bindings", SQLBindingTiDBMinVersion); err != nil {
+		return diag.Errorf("cannot create SQL binding: %v", err)
+	}
+
+	// TiDB doesn't return the digest of a new binding, so it's found by comparing the bindings
+	// before and after. Creating a binding for a statement that has one replaces it, which
+	// changes its update time.
+	before, err := listSQLBindings(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := sqlBindingStatement("CREATE", d.Get("original_sql").(string), d.Get("bind_sql").(string))
+	if err := execSQLBindingStatement(ctx, db, d.Get("database").(string), stmtSQL); err != nil {
+		return sqlErrorDiag("error creating SQL binding", stmtSQL, err)
+	}
+
+	after, err := listSQLBindings(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for digest, binding := range after {
+		if previous, ok := before[digest]; !ok || previous.UpdateTime != binding.UpdateTime {
+			d.SetId(digest)
+			return ReadSQLBinding(ctx, d, meta)
+		}
+	}
+
+	return diag.Errorf("SQL binding was created, but not found in SHOW GLOBAL BINDINGS")
+}
+
+func ReadSQLBinding(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	bindings, err := listSQLBindings(ctx, db)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	binding, ok := bindings[d.Id()]
+	if !ok {
+		log.Printf("[WARN] SQL binding (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// The original and bind SQL are kept as configured, since TiDB stores them reformatted.
+	d.Set("sql_digest", binding.SQLDigest)
+	d.Set("normalized_sql", binding.OriginalSQL)
+	d.Set("status", binding.Status)
+
+	return nil
+}
+
+func DeleteSQLBinding(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := sqlBindingStatement("DROP", d.Get("original_sql").(string), d.Get("bind_sql").(string))
+	if err := execSQLBindingStatement(ctx, db, d.Get("database").(string), stmtSQL); err != nil {
+		return sqlErrorDiag(fmt.Sprintf("error dropping SQL binding (%s)", d.Id()), stmtSQL, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// ImportSQLBinding imports a binding by its SQL digest, taking the SQL as TiDB stores it.
+func ImportSQLBinding(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := listSQLBindings(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	binding, ok := bindings[d.Id()]
+	if !ok {
+		return nil, fmt.Errorf("SQL binding with digest %s not found", d.Id())
+	}
+
+	d.Set("original_sql", binding.OriginalSQL)
+	d.Set("bind_sql", binding.BindSQL)
+	d.Set("database", binding.DefaultDB)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// listSQLBindings returns the enabled and disabled global bindings by their SQL digest. The
+// columns of SHOW GLOBAL BINDINGS differ between TiDB versions, so they're matched by name.
+func listSQLBindings(ctx context.Context, db *sql.DB) (map[string]SQLBinding, error) {
+	query := "SHOW GLOBAL BINDINGS"
+
+	ctx = tflog.SetField(ctx, "query", query)
+	tflog.Debug(ctx, "listSQLBindings")
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing SQL bindings: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := map[string]SQLBinding{}
+	for rows.Next() {
+		row, err := scanRowMap(rows, columns)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning SQL bindings: %w", err)
+		}
+
+		binding := SQLBinding{
+			OriginalSQL: row["original_sql"],
+			BindSQL:     row["bind_sql"],
+			DefaultDB:   row["default_db"],
+			Status:      row["status"],
+			UpdateTime:  row["update_time"],
+			SQLDigest:   row["sql_digest"],
+		}
+
+		if binding.SQLDigest == "" || binding.Status == "deleted" {
+			continue
+		}
+		bindings[binding.SQLDigest] = binding
+	}
+
+	return bindings, rows.Err()
+}
@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSQLBindingStatement(t *testing.T) {
+	got := sqlBindingStatement("CREATE", " SELECT * FROM t WHERE a = 1;\n", "SELECT * FROM t USE INDEX (idx_a) WHERE a = 1;")
+	expected := "CREATE GLOBAL BINDING FOR SELECT * FROM t WHERE a = 1 USING SELECT * FROM t USE INDEX (idx_a) WHERE a = 1"
+	if got != expected {
+		t.Errorf("sqlBindingStatement = %q, expected %q", got, expected)
+	}
+}
+
+func TestAccTiSQLBinding_basic(t *testing.T) {
+	dbName := "tf_sql_binding"
+	resourceName := "mysql_ti_sql_binding.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, SQLBindingTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSQLBindingCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSQLBindingConfig(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSQLBindingExists(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "sql_digest"),
+					resource.TestCheckResourceAttr(resourceName, "status", "enabled"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"original_sql", "bind_sql"},
+			},
+		},
+	})
+}
+
+func testAccSQLBindingExists(rn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		bindings, err := listSQLBindings(ctx, db)
+		if err != nil {
+			return err
+		}
+		if _, ok := bindings[rs.Primary.ID]; !ok {
+			return fmt.Errorf("SQL binding (%s) does not exist", rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testAccSQLBindingCheckDestroy(s *terraform.State) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+
+	bindings, err := listSQLBindings(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mysql_ti_sql_binding" {
+			continue
+		}
+		if _, ok := bindings[rs.Primary.ID]; ok {
+			return fmt.Errorf("SQL binding (%s) still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccSQLBindingConfig(dbName string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+	name = "%s"
+}
+
+resource "mysql_sql" "table" {
+	name       = "table"
+	create_sql = "CREATE TABLE ${mysql_database.test.name}.t (a INT, b INT, KEY idx_a (a))"
+	delete_sql = "DROP TABLE ${mysql_database.test.name}.t"
+}
+
+resource "mysql_ti_sql_binding" "test" {
+	database     = mysql_database.test.name
+	original_sql = "SELECT * FROM t WHERE a = 1"
+	bind_sql     = "SELECT * FROM t USE INDEX (idx_a) WHERE a = 1"
+
+	depends_on = [mysql_sql.table]
+}
+`, dbName)
+}
@@ -2,13 +2,19 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
-	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -20,22 +26,36 @@ func resourceUser() *schema.Resource {
 		UpdateContext: UpdateUser,
 		ReadContext:   ReadUser,
 		DeleteContext: DeleteUser,
+		Timeouts:      defaultResourceTimeouts(true),
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportUser,
 		},
+		CustomizeDiff: customizeDiffUser,
 
 		Schema: map[string]*schema.Schema{
 			"user": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateUserName,
 			},
 
 			"host": {
-				Type:     schema.TypeString,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "localhost",
+				ValidateFunc: validateUserHost,
+			},
+
+			"rename_on_change": {
+				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
-				Default:  "localhost",
+				Default:  false,
+			},
+
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 
 			"plaintext_password": {
@@ -53,12 +73,26 @@ func resourceUser() *schema.Resource {
 				Deprecated:    "Please use plaintext_password instead",
 			},
 
+			"plaintext_password_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"plaintext_password", "password", "random_password"},
+			},
+
+			"plaintext_password_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"plaintext_password_wo"},
+			},
+
 			"auth_plugin": {
 				Type:             schema.TypeString,
 				Optional:         true,
-				ForceNew:         true,
 				DiffSuppressFunc: NewEmptyStringSuppressFunc,
-				ConflictsWith:    []string{"plaintext_password", "password"},
+				ValidateFunc:     validateAuthPlugin,
+				ConflictsWith:    []string{"plaintext_password", "password", "plaintext_password_wo"},
 			},
 
 			"aad_identity": {
@@ -93,29 +127,429 @@ func resourceUser() *schema.Resource {
 				Optional:         true,
 				Sensitive:        true,
 				DiffSuppressFunc: NewEmptyStringSuppressFunc,
-				ConflictsWith:    []string{"plaintext_password", "password"},
+				ConflictsWith:    []string{"plaintext_password", "password", "plaintext_password_wo"},
 			},
 
 			"tls_option": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "NONE",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "NONE",
+				Deprecated:       "Please use tls_requirements instead",
+				DiffSuppressFunc: tlsOptionDiffSuppressFunc,
+				ValidateFunc:     validateTLSOption,
+				ConflictsWith:    []string{"tls_requirements"},
+			},
+
+			"tls_requirements": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "TLS requirements of the account, rendered as the REQUIRE clause",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ssl": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"x509": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"cipher": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"issuer": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"subject": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
 			},
 
 			"retain_old_password": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+
+			"discard_old_password": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"account_locked": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"password_history": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "DEFAULT",
+				ValidateFunc:     validateDefaultOrNonNegativeInt,
+				DiffSuppressFunc: caseInsensitiveSuppressFunc,
+			},
+
+			"password_reuse_interval": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "DEFAULT",
+				ValidateFunc:     validateDefaultOrNonNegativeInt,
+				DiffSuppressFunc: caseInsensitiveSuppressFunc,
+			},
+
+			"password_expire": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "DEFAULT",
+				ValidateFunc:     validatePasswordExpire,
+				DiffSuppressFunc: caseInsensitiveSuppressFunc,
+			},
+
+			"random_password": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"plaintext_password", "password", "auth_string_hashed"},
+			},
+
+			"generated_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"auth_factor": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 2,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"plugin": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateAuthPlugin,
+						},
+						"plaintext_password": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"auth_string_hashed": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							DiffSuppressFunc: NewEmptyStringSuppressFunc,
+						},
+					},
+				},
+			},
+
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"attributes": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: func(val any, key string) (warns []string, errs []error) {
+					if _, ok := val.(map[string]interface{})["comment"]; ok {
+						errs = append(errs, fmt.Errorf("%q can't contain the key comment, use the comment attribute instead", key))
+					}
+					return
+				},
+			},
+
+			"failed_login_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 32767),
+			},
+
+			"on_exists": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "fail",
+				ValidateFunc: validation.StringInSlice([]string{"fail", "adopt", "replace"}, false),
+			},
+
+			"default_roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set: schema.HashString,
+			},
+
+			"resource_group": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"password_require_current": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "DEFAULT",
+				ValidateFunc:     validation.StringInSlice([]string{"DEFAULT", "OPTIONAL", "REQUIRED"}, true),
+				DiffSuppressFunc: caseInsensitiveSuppressFunc,
+			},
+
+			"password_lock_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "0",
+				ValidateFunc:     validatePasswordLockTime,
+				DiffSuppressFunc: caseInsensitiveSuppressFunc,
+			},
 		},
 	}
 }
 
-func checkRetainCurrentPasswordSupport(ctx context.Context, meta interface{}) error {
-	ver, _ := version.NewVersion("8.0.14")
-	if getVersionFromMeta(ctx, meta).LessThan(ver) {
-		return errors.New("MySQL version must be at least 8.0.14")
+// userPasswordOption is a password management clause of CREATE USER / ALTER USER
+// mapped to a single string attribute of mysql_user.
+type userPasswordOption struct {
+	Attribute  string
+	Default    string
+	Capability capability
+	// IsInt is set for attributes declared as schema.TypeInt.
+	IsInt  bool
+	Clause func(value string) string
+	// Regex extracts the value from SHOW CREATE USER output.
+	Regex *regexp.Regexp
+	// FromMatch maps the value extracted by Regex to the attribute value, if they differ.
+	FromMatch func(match string) string
+}
+
+func (o userPasswordOption) get(d *schema.ResourceData) string {
+	return fmt.Sprint(d.Get(o.Attribute))
+}
+
+func (o userPasswordOption) set(d *schema.ResourceData, value string) {
+	if o.IsInt {
+		n, _ := strconv.Atoi(value)
+		d.Set(o.Attribute, n)
+		return
 	}
-	return nil
+	d.Set(o.Attribute, value)
+}
+
+func (o userPasswordOption) checkSupport(ctx context.Context, meta interface{}) error {
+	return checkCapability(ctx, meta, o.Capability)
+}
+
+var userPasswordOptions = []userPasswordOption{
+	{
+		Attribute:  "password_expire",
+		Default:    "DEFAULT",
+		Capability: capabilityPasswordExpire,
+		Clause: func(value string) string {
+			if _, err := strconv.Atoi(value); err == nil {
+				return fmt.Sprintf("PASSWORD EXPIRE INTERVAL %s DAY", value)
+			}
+			return "PASSWORD EXPIRE " + strings.ToUpper(value)
+		},
+		Regex: regexp.MustCompile(`\bPASSWORD EXPIRE (DEFAULT|NEVER|INTERVAL \d+ DAY)\b`),
+		FromMatch: func(match string) string {
+			return strings.TrimSuffix(strings.TrimPrefix(match, "INTERVAL "), " DAY")
+		},
+	},
+	{
+		Attribute:  "password_history",
+		Default:    "DEFAULT",
+		Capability: capabilityPasswordReuse,
+		Clause: func(value string) string {
+			return "PASSWORD HISTORY " + strings.ToUpper(value)
+		},
+		Regex: regexp.MustCompile(`\bPASSWORD HISTORY (DEFAULT|\d+)\b`),
+	},
+	{
+		Attribute:  "password_reuse_interval",
+		Default:    "DEFAULT",
+		Capability: capabilityPasswordReuse,
+		Clause: func(value string) string {
+			if strings.EqualFold(value, "DEFAULT") {
+				return "PASSWORD REUSE INTERVAL DEFAULT"
+			}
+			return fmt.Sprintf("PASSWORD REUSE INTERVAL %s DAY", value)
+		},
+		Regex: regexp.MustCompile(`\bPASSWORD REUSE INTERVAL (DEFAULT|\d+)\b`),
+	},
+	{
+		Attribute:  "failed_login_attempts",
+		Default:    "0",
+		Capability: capabilityFailedLoginTracking,
+		IsInt:      true,
+		Clause: func(value string) string {
+			return "FAILED_LOGIN_ATTEMPTS " + value
+		},
+		Regex: regexp.MustCompile(`\bFAILED_LOGIN_ATTEMPTS (\d+)\b`),
+	},
+	{
+		Attribute:  "password_lock_time",
+		Default:    "0",
+		Capability: capabilityFailedLoginTracking,
+		Clause: func(value string) string {
+			return "PASSWORD_LOCK_TIME " + strings.ToUpper(value)
+		},
+		Regex: regexp.MustCompile(`\bPASSWORD_LOCK_TIME (UNBOUNDED|\d+)\b`),
+	},
+	{
+		Attribute:  "password_require_current",
+		Default:    "DEFAULT",
+		Capability: capabilityPasswordRequireCurrent,
+		Clause: func(value string) string {
+			if strings.EqualFold(value, "REQUIRED") {
+				return "PASSWORD REQUIRE CURRENT"
+			}
+			return "PASSWORD REQUIRE CURRENT " + strings.ToUpper(value)
+		},
+		Regex: regexp.MustCompile(`\bPASSWORD REQUIRE CURRENT( DEFAULT| OPTIONAL)?\b`),
+		FromMatch: func(match string) string {
+			if match == "" {
+				return "REQUIRED"
+			}
+			return strings.TrimSpace(match)
+		},
+	},
+}
+
+const maxUserNameLength = 32
+const maxHostNameLength = 255
+
+var kUserHostRegex = regexp.MustCompile(`^[A-Za-z0-9._%:/-]*$`)
+
+func validateUserName(val any, key string) (warns []string, errs []error) {
+	value := val.(string)
+	if value == "" {
+		errs = append(errs, fmt.Errorf("%q must not be empty", key))
+		return
+	}
+	if n := utf8.RuneCountInString(value); n > maxUserNameLength {
+		errs = append(errs, fmt.Errorf("%q must be at most %d characters long, got %d: %s", key, maxUserNameLength, n, value))
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			errs = append(errs, fmt.Errorf("%q must not contain control characters, got %q in: %s", key, r, value))
+			break
+		}
+	}
+	return
+}
+
+// validateUserHost accepts the host formats of account names: host names and IP
+// addresses with % and _ wildcards, and IPv4 addresses with a netmask or prefix length.
+func validateUserHost(val any, key string) (warns []string, errs []error) {
+	value := val.(string)
+	if len(value) > maxHostNameLength {
+		errs = append(errs, fmt.Errorf("%q must be at most %d characters long, got %d", key, maxHostNameLength, len(value)))
+		return
+	}
+	if !kUserHostRegex.MatchString(value) {
+		errs = append(errs, fmt.Errorf("%q may only contain letters, digits and . _ %% : / -, got: %s", key, value))
+		return
+	}
+
+	address, mask, hasMask := strings.Cut(value, "/")
+	if !hasMask {
+		return
+	}
+	if ip := net.ParseIP(address); ip == nil || ip.To4() == nil {
+		errs = append(errs, fmt.Errorf("%q must have an IPv4 address before the netmask, got: %s", key, value))
+		return
+	}
+	if prefix, err := strconv.Atoi(mask); err == nil {
+		if prefix < 0 || prefix > 32 {
+			errs = append(errs, fmt.Errorf("%q must have a prefix length between 0 and 32, got: %s", key, value))
+		}
+		return
+	}
+	if ip := net.ParseIP(mask); ip == nil || ip.To4() == nil {
+		errs = append(errs, fmt.Errorf("%q must have an IPv4 netmask or a prefix length after /, got: %s", key, value))
+	}
+	return
+}
+
+func validatePasswordLockTime(val any, key string) (warns []string, errs []error) {
+	value := val.(string)
+	if strings.EqualFold(value, "UNBOUNDED") {
+		return
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < 0 || n > 32767 {
+		errs = append(errs, fmt.Errorf("%q must be UNBOUNDED or an integer between 0 and 32767, got: %s", key, value))
+	}
+	return
+}
+
+// kAuthPluginRegex matches plugin names, which are rendered unquoted in IDENTIFIED WITH.
+var kAuthPluginRegex = regexp.MustCompile(`^[A-Za-z0-9_]*$`)
+
+func validateAuthPlugin(val any, key string) (warns []string, errs []error) {
+	if value := val.(string); !kAuthPluginRegex.MatchString(value) {
+		errs = append(errs, fmt.Errorf("%q must be the name of an authentication plugin, got: %s", key, value))
+	}
+	return
+}
+
+func validatePasswordExpire(val any, key string) (warns []string, errs []error) {
+	value := val.(string)
+	if strings.EqualFold(value, "DEFAULT") || strings.EqualFold(value, "NEVER") {
+		return
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+		errs = append(errs, fmt.Errorf("%q must be DEFAULT, NEVER or a number of days between 1 and 65535, got: %s", key, value))
+	}
+	return
+}
+
+func validateDefaultOrNonNegativeInt(val any, key string) (warns []string, errs []error) {
+	value := val.(string)
+	if strings.EqualFold(value, "DEFAULT") {
+		return
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < 0 {
+		errs = append(errs, fmt.Errorf("%q must be DEFAULT or a non-negative integer, got: %s", key, value))
+	}
+	return
+}
+
+func checkUserResourceGroupSupport(ctx context.Context, db *sql.DB) error {
+	return checkTiDBFeatureSupport(ctx, db, "resource groups", ResourceGroupTiDBMinVersion)
+}
+
+func checkAccountLockSupport(ctx context.Context, meta interface{}) error {
+	return checkCapability(ctx, meta, capabilityAccountLock)
+}
+
+func accountLockClause(locked bool) string {
+	if locked {
+		return "ACCOUNT LOCK"
+	}
+	return "ACCOUNT UNLOCK"
+}
+
+// accountOptions orders the lock and password options of CREATE USER. MySQL accepts them in
+// any order, but MariaDB only accepts the lock option before the password options.
+func accountOptions(flavor serverFlavor, lockOption string, passwordOptions []string) []string {
+	if lockOption == "" {
+		return passwordOptions
+	}
+	if flavor == flavorMariaDB {
+		return append([]string{lockOption}, passwordOptions...)
+	}
+	return append(passwordOptions, lockOption)
+}
+
+func checkRetainCurrentPasswordSupport(ctx context.Context, meta interface{}) error {
+	return checkCapability(ctx, meta, capabilityRetainCurrentPassword)
 }
 
 func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -124,121 +558,831 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.FromErr(err)
 	}
 
-	var authStm string
-	var auth string
-	var createObj = "USER"
+	account := UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}
+	objectLocks.Lock(accountLockKey(account))
+	defer objectLocks.Unlock(accountLockKey(account))
+
+	if onExists := d.Get("on_exists").(string); onExists != "fail" {
+		exists, err := userExists(ctx, db, d.Get("user").(string), d.Get("host").(string))
+		if err != nil {
+			return diag.Errorf("failed checking whether user exists: %v", err)
+		}
+		if exists && onExists == "adopt" {
+			// Take over the existing account and bring it in line with the configuration.
+			d.SetId(fmt.Sprintf("%s@%s", d.Get("user").(string), d.Get("host").(string)))
+			return updateUser(ctx, d, meta, true)
+		}
+		if exists && onExists == "replace" {
+			stmtSQL := "DROP USER ?@?"
+			log.Println("[DEBUG] Executing statement:", stmtSQL)
+			if _, err := db.ExecContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string)); err != nil {
+				return sqlErrorDiag("failed dropping existing user", stmtSQL, err)
+			}
+		}
+	}
+
+	var authStm string
+	var auth string
+	var createObj = "USER"
+
+	if v, ok := d.GetOk("auth_plugin"); ok {
+		auth = v.(string)
+	}
+
+	if len(auth) > 0 {
+		if auth == "aad_auth" {
+			// aad_auth is plugin but Microsoft uses another statement to create this kind of users
+			createObj = "AADUSER"
+			if _, ok := d.GetOk("aad_identity"); !ok {
+				return diag.Errorf("aad_identity is required for aad_auth")
+			}
+		} else {
+			// AWSAuthenticationPlugin, mysql_no_login, auth_pam, ...
+			authStm = authPluginSQL(auth, "")
+		}
+	}
+	if v, ok := d.GetOk("auth_string_hashed"); ok {
+		hashed := v.(string)
+		if hashed != "" {
+			if authStm == "" {
+				return diag.Errorf("auth_string_hashed is not supported for auth plugin %s", auth)
+			}
+			authStm = fmt.Sprintf("%s AS %s", authStm, quoteLiteral(hashed))
+		}
+	}
+
+	var stmtSQL string
+
+	if createObj == "AADUSER" {
+		var aadIdentity = d.Get("aad_identity").(*schema.Set).List()[0].(map[string]interface{})
+
+		if aadIdentity["type"].(string) == "service_principal" {
+			// CREATE AADUSER 'mysqlProtocolLoginName"@"mysqlHostRestriction' IDENTIFIED BY 'identityId'
+			stmtSQL = fmt.Sprintf("CREATE AADUSER %s IDENTIFIED BY %s",
+				UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}.SQLString(),
+				quoteLiteral(aadIdentity["identity"].(string)))
+		} else {
+			// CREATE AADUSER 'identityName"@"mysqlHostRestriction' AS 'mysqlProtocolLoginName'
+			stmtSQL = fmt.Sprintf("CREATE AADUSER %s AS %s",
+				UserOrRole{Name: aadIdentity["identity"].(string), Host: d.Get("host").(string)}.SQLString(),
+				quoteLiteral(d.Get("user").(string)))
+		}
+	} else {
+		stmtSQL = fmt.Sprintf("CREATE USER %s",
+			UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}.SQLString())
+	}
+
+	var password string
+	if v, ok := d.GetOk("plaintext_password"); ok {
+		password = v.(string)
+	} else {
+		password = d.Get("password").(string)
+	}
+	if password == "" {
+		writeOnlyPassword, diags := getRawConfigString(d, "plaintext_password_wo")
+		if diags.HasError() {
+			return diags
+		}
+		password = writeOnlyPassword
+	}
+
+	if auth == "AWSAuthenticationPlugin" && d.Get("host").(string) == "localhost" {
+		return diag.Errorf("cannot use IAM auth against localhost")
+	}
+
+	if auth == "ed25519" {
+		if err := checkEd25519Support(ctx, db); err != nil {
+			return diag.Errorf("cannot use auth_plugin ed25519: %v", err)
+		}
+	}
+
+	randomPassword := d.Get("random_password").(bool)
+	if randomPassword {
+		if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use random_password: %v", err)
+		}
+		if createObj == "AADUSER" || auth == "AWSAuthenticationPlugin" {
+			return diag.Errorf("random_password is not supported for auth plugin %s", auth)
+		}
+		if authStm == "" {
+			authStm = " IDENTIFIED"
+		}
+		authStm += " BY RANDOM PASSWORD"
+	}
+
+	if authStm != "" {
+		stmtSQL = stmtSQL + authStm
+	} else if password != "" {
+		stmtSQL = stmtSQL + fmt.Sprintf(" IDENTIFIED BY %s", quoteLiteral(password))
+	}
+
+	authFactors := authFactorsFromData(d)
+	if len(authFactors) > 0 {
+		if err := checkAuthFactorSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use auth_factor: %v", err)
+		}
+		if createObj == "AADUSER" || (authStm == "" && password == "") {
+			return diag.Errorf("auth_factor requires the first factor to be set with plaintext_password, auth_plugin or auth_string_hashed")
+		}
+		for _, factor := range authFactors {
+			stmtSQL += " AND " + factor.identifiedSQL()
+		}
+	}
+
+	var userOptions []string
+
+	tlsRequire, err := userTLSRequireSQL(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if serverSupports(ctx, meta, capabilityAlterUser) {
+		userOptions = append(userOptions, fmt.Sprintf("REQUIRE %s", tlsRequire))
+	}
+
+	retainPassword := d.Get("retain_old_password").(bool)
+	if retainPassword {
+		err := checkRetainCurrentPasswordSupport(ctx, meta)
+		if err != nil {
+			return diag.Errorf("cannot use retain_current_password: %v", err)
+		}
+	}
+
+	var passwordOptions []string
+	for _, option := range userPasswordOptions {
+		value := option.get(d)
+		if strings.EqualFold(value, option.Default) {
+			continue
+		}
+		if err := option.checkSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use %s: %v", option.Attribute, err)
+		}
+		passwordOptions = append(passwordOptions, option.Clause(value))
+	}
+
+	lockOption := ""
+	if d.Get("account_locked").(bool) {
+		if err := checkAccountLockSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use account_locked: %v", err)
+		}
+		lockOption = accountLockClause(true)
+	}
+
+	flavor, _, err := getFlavorFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userOptions = append(userOptions, accountOptions(flavor, lockOption, passwordOptions)...)
+
+	if resourceGroup := d.Get("resource_group").(string); resourceGroup != "" {
+		if err := checkUserResourceGroupSupport(ctx, db); err != nil {
+			return diag.Errorf("cannot use resource_group: %v", err)
+		}
+		userOptions = append(userOptions, "RESOURCE GROUP "+quoteIdentifier(resourceGroup))
+	}
+
+	var updateStmtSql = ""
+	if len(userOptions) > 0 {
+		if createObj == "AADUSER" {
+			// CREATE AADUSER doesn't accept any options, so they are applied afterwards.
+			updateStmtSql = fmt.Sprintf("ALTER USER %s %s",
+				UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}.SQLString(),
+				strings.Join(userOptions, " "))
+		} else {
+			stmtSQL += " " + strings.Join(userOptions, " ")
+		}
+	}
+
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if randomPassword {
+		generatedPassword, err := queryGeneratedPassword(ctx, db, stmtSQL)
+		if err != nil {
+			return rdsErrorDiag(ctx, meta, "failed creating user", stmtSQL, err, rdsCreateUserHint)
+		}
+		d.Set("generated_password", generatedPassword)
+	} else {
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return rdsErrorDiag(ctx, meta, "failed creating user", stmtSQL, err, rdsCreateUserHint)
+		}
+	}
+
+	user := fmt.Sprintf("%s@%s", d.Get("user").(string), d.Get("host").(string))
+	d.SetId(user)
+
+	if updateStmtSql != "" {
+		log.Println("[DEBUG] Executing statement:", updateStmtSql)
+		_, err = db.ExecContext(ctx, updateStmtSql)
+		if err != nil {
+			d.Set("tls_option", "")
+			d.Set("tls_requirements", nil)
+			d.Set("account_locked", false)
+			for _, option := range userPasswordOptions {
+				option.set(d, option.Default)
+			}
+			return sqlErrorDiag("failed setting user options", updateStmtSql, err)
+		}
+	}
+
+	_, hasComment := d.GetOk("comment")
+	_, hasAttributes := d.GetOk("attributes")
+	if hasComment || hasAttributes {
+		if err := checkUserAttributesSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use comment or attributes: %v", err)
+		}
+
+		patch := userAttributesPatch(nil, d.Get("attributes").(map[string]interface{}))
+		if hasComment {
+			patch["comment"] = d.Get("comment").(string)
+		}
+		if err := alterUserAttributes(ctx, db, d, patch); err != nil {
+			d.Set("comment", "")
+			d.Set("attributes", nil)
+			return sqlErrorDiag("failed setting user attributes", "", err)
+		}
+	}
+
+	if roles := d.Get("default_roles").(*schema.Set); roles.Len() > 0 {
+		if err := setUserDefaultRoles(ctx, db, d, meta, roles); err != nil {
+			d.Set("default_roles", nil)
+			return sqlErrorDiag("failed setting default roles", "", err)
+		}
+	}
+
+	return nil
+}
+
+// setUserDefaultRoles grants the roles which weren't default roles before, as only
+// granted roles can become default roles, and then makes them the default roles.
+func setUserDefaultRoles(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, roles *schema.Set) error {
+	if err := checkDefaultRolesSupport(ctx, meta); err != nil {
+		return err
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	oldRoles, _ := d.GetChange("default_roles")
+	for _, role := range setToArray(roles.Difference(oldRoles.(*schema.Set))) {
+		stmtSQL := fmt.Sprintf("GRANT %s TO %s", parseRoleReference(role).SQLString(), UserOrRole{Name: user, Host: host}.SQLString())
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("failed granting role %s: %w", role, err)
+		}
+	}
+
+	return alterUserDefaultRoles(ctx, db, user, host, setToArray(roles))
+}
+
+// authFactor is the 2nd or 3rd authentication factor of a user.
+type authFactor struct {
+	Plugin            string
+	PlaintextPassword string
+	AuthStringHashed  string
+}
+
+func (f authFactor) identifiedSQL() string {
+	stmtSQL := "IDENTIFIED WITH " + f.Plugin
+	if f.PlaintextPassword != "" {
+		stmtSQL += " BY " + quoteLiteral(f.PlaintextPassword)
+	} else if f.AuthStringHashed != "" {
+		stmtSQL += " AS " + quoteLiteral(f.AuthStringHashed)
+	}
+	return stmtSQL
+}
+
+func checkAuthFactorSupport(ctx context.Context, meta interface{}) error {
+	return checkCapability(ctx, meta, capabilityAuthFactors)
+}
+
+func authFactorsFromList(factors []interface{}) []authFactor {
+	result := make([]authFactor, 0, len(factors))
+	for _, f := range factors {
+		factor := f.(map[string]interface{})
+		result = append(result, authFactor{
+			Plugin:            factor["plugin"].(string),
+			PlaintextPassword: factor["plaintext_password"].(string),
+			AuthStringHashed:  factor["auth_string_hashed"].(string),
+		})
+	}
+	return result
+}
+
+func authFactorsFromData(d *schema.ResourceData) []authFactor {
+	return authFactorsFromList(d.Get("auth_factor").([]interface{}))
+}
+
+// authFactorChangeSQL returns ALTER USER statements (with user and host placeholders)
+// moving the auth factors from the old to the new configuration. Factors whose
+// plugin changed are dropped and re-added, as MODIFY can't switch plugins.
+func authFactorChangeSQL(d *schema.ResourceData) []string {
+	oldRaw, newRaw := d.GetChange("auth_factor")
+	oldFactors := authFactorsFromList(oldRaw.([]interface{}))
+	newFactors := authFactorsFromList(newRaw.([]interface{}))
+
+	rebuildFrom := len(oldFactors)
+	for i := range oldFactors {
+		if i >= len(newFactors) || !strings.EqualFold(oldFactors[i].Plugin, newFactors[i].Plugin) {
+			rebuildFrom = i
+			break
+		}
+	}
+
+	var stmts []string
+	// Factor 3 has to go before factor 2 can be dropped.
+	for i := len(oldFactors) - 1; i >= rebuildFrom; i-- {
+		stmts = append(stmts, fmt.Sprintf("ALTER USER ?@? DROP %d FACTOR", i+2))
+	}
+	for i := 0; i < rebuildFrom; i++ {
+		if oldFactors[i] != newFactors[i] {
+			stmts = append(stmts, fmt.Sprintf("ALTER USER ?@? MODIFY %d FACTOR %s", i+2, newFactors[i].identifiedSQL()))
+		}
+	}
+	for i := rebuildFrom; i < len(newFactors); i++ {
+		stmts = append(stmts, fmt.Sprintf("ALTER USER ?@? ADD %d FACTOR %s", i+2, newFactors[i].identifiedSQL()))
+	}
+	return stmts
+}
+
+var kAuthFactorRegex = regexp.MustCompile(" AND IDENTIFIED WITH ['`\"]([^'`\"]*)['`\"](?: AS '((?:.*?[^\\\\])?)')?")
+
+// extractAuthFactors removes the 2nd and 3rd factor clauses from SHOW CREATE USER output
+// and returns them separately, so that the rest can be parsed as a single-factor user.
+func extractAuthFactors(createUserStmt string) (string, []authFactor) {
+	var factors []authFactor
+	for _, m := range kAuthFactorRegex.FindAllStringSubmatch(createUserStmt, -1) {
+		factors = append(factors, authFactor{
+			Plugin:           m[1],
+			AuthStringHashed: m[2],
+		})
+	}
+	return kAuthFactorRegex.ReplaceAllString(createUserStmt, ""), factors
+}
+
+func setAuthFactorsOnData(d *schema.ResourceData, dbFactors []authFactor) {
+	// Passwords can't be read back, so keep the configured ones.
+	configured := authFactorsFromData(d)
+	factors := make([]map[string]interface{}, 0, len(dbFactors))
+	for i, factor := range dbFactors {
+		password := ""
+		if i < len(configured) && strings.EqualFold(configured[i].Plugin, factor.Plugin) {
+			password = configured[i].PlaintextPassword
+		}
+		factors = append(factors, map[string]interface{}{
+			"plugin":             factor.Plugin,
+			"plaintext_password": password,
+			"auth_string_hashed": factor.AuthStringHashed,
+		})
+	}
+	d.Set("auth_factor", factors)
+}
+
+func checkUserAttributesSupport(ctx context.Context, meta interface{}) error {
+	return checkCapability(ctx, meta, capabilityUserAttributes)
+}
+
+// userAttributesPatch returns a JSON merge patch turning oldAttrs into newAttrs.
+// Removed keys are set to null, which makes MySQL delete them.
+func userAttributesPatch(oldAttrs, newAttrs map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for k := range oldAttrs {
+		if _, ok := newAttrs[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, v := range newAttrs {
+		patch[k] = v
+	}
+	return patch
+}
+
+func alterUserAttributes(ctx context.Context, db *sql.DB, d *schema.ResourceData, patch map[string]interface{}) error {
+	if len(patch) == 0 {
+		return nil
+	}
+
+	attributes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	stmtSQL := "ALTER USER ?@? ATTRIBUTE ?"
+	log.Println("[DEBUG] Executing statement:", stmtSQL, string(attributes))
+	_, err = db.ExecContext(ctx, stmtSQL,
+		d.Get("user").(string),
+		d.Get("host").(string),
+		string(attributes))
+	return err
+}
+
+func readUserAttributes(ctx context.Context, db *sql.DB, d *schema.ResourceData) error {
+	stmtSQL := "SELECT ATTRIBUTE FROM INFORMATION_SCHEMA.USER_ATTRIBUTES WHERE USER = ? AND HOST = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var rawAttributes sql.NullString
+	err := db.QueryRowContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string)).Scan(&rawAttributes)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	comment := ""
+	attributes := make(map[string]interface{})
+	if rawAttributes.Valid && rawAttributes.String != "" {
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(rawAttributes.String), &parsed); err != nil {
+			return fmt.Errorf("failed parsing user attributes %s: %w", rawAttributes.String, err)
+		}
+		for k, v := range parsed {
+			value, ok := v.(string)
+			if !ok {
+				// Attributes set outside of Terraform may hold any JSON value.
+				encoded, _ := json.Marshal(v)
+				value = string(encoded)
+			}
+			if k == "comment" {
+				comment = value
+			} else {
+				attributes[k] = value
+			}
+		}
+	}
+
+	d.Set("comment", comment)
+	d.Set("attributes", attributes)
+	return nil
+}
+
+// parseAADIdentity maps the auth string of an aad_auth user to the type and identity
+// of the aad_identity block it was created from:
+// AADUser:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:little.johny@does.onmicrosoft.com
+// AADGroup:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:Doe_Family_Group
+// AADSP:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:mysqlUserName - for MySQL Flexible Server
+// AADApp:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:mysqlUserName - for MySQL Single Server
+func parseAADIdentity(authString string) (string, string, error) {
+	parts := strings.SplitN(authString, ":", 4)
+	switch {
+	case len(parts) >= 2 && (parts[0] == "AADSP" || parts[0] == "AADApp") && parts[1] != "":
+		// Service principals are created from their client ID on both servers.
+		return "service_principal", parts[1], nil
+	case len(parts) == 4 && parts[0] == "AADUser" && parts[3] != "":
+		// Users are referenced by UPN, which may contain colons itself.
+		return "user", parts[3], nil
+	case len(parts) == 4 && parts[0] == "AADGroup" && parts[3] != "":
+		return "group", parts[3], nil
+	}
+	return "", "", fmt.Errorf("AAD identity couldn't be parsed - it is %s", authString)
+}
+
+func checkEd25519Support(ctx context.Context, db *sql.DB) error {
+	isMariaDB, err := serverMariaDB(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !isMariaDB {
+		return fmt.Errorf("ed25519 is only available on MariaDB")
+	}
+	return nil
+}
+
+// defaultAuthPlugin returns the plugin the server uses for accounts created with a password.
+func defaultAuthPlugin(ctx context.Context, db *sql.DB, meta interface{}) string {
+	var plugin string
+	// default_authentication_plugin is gone in MySQL 8.4.
+	err := db.QueryRowContext(ctx, "SELECT @@default_authentication_plugin").Scan(&plugin)
+	if err == nil && plugin != "" {
+		return plugin
+	}
+
+	if !serverSupports(ctx, meta, capabilityCachingSHA2Default) {
+		return "mysql_native_password"
+	}
+	return "caching_sha2_password"
+}
+
+// authPluginSQL returns the IDENTIFIED WITH clause for the given plugin,
+// optionally with an already hashed auth string.
+func authPluginSQL(auth string, hashed string) string {
+	if auth == "AWSAuthenticationPlugin" {
+		// IAM auth always needs the RDS auth string.
+		return " IDENTIFIED WITH AWSAuthenticationPlugin as 'RDS'"
+	}
+
+	stmtSQL := " IDENTIFIED WITH " + auth
+	if hashed != "" {
+		stmtSQL += " AS " + quoteLiteral(hashed)
+	}
+	return stmtSQL
+}
+
+func customizeDiffUser(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if tlsReqs := tlsRequirementsFromList(d.Get("tls_requirements").([]interface{})); tlsReqs != nil {
+		if err := tlsReqs.validate(); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() != "" && !d.Get("rename_on_change").(bool) {
+		// Without RENAME USER, a new name means a new account.
+		for _, key := range []string{"user", "host"} {
+			if d.HasChange(key) {
+				if err := d.ForceNew(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if d.Id() == "" || !d.HasChange("auth_plugin") {
+		return nil
+	}
+
+	oldPlugin, newPlugin := d.GetChange("auth_plugin")
+	if oldPlugin.(string) == "aad_auth" || newPlugin.(string) == "aad_auth" {
+		// AAD users are created with CREATE AADUSER, so they can't be converted in place.
+		return d.ForceNew("auth_plugin")
+	}
+	return nil
+}
+
+// rdsCreateUserHint explains which options of CREATE USER need privileges RDS doesn't grant.
+func rdsCreateUserHint(aurora bool) string {
+	return "The master user of RDS lacks SUPER, and RDS only supports the mysql_native_password, caching_sha2_password, sha256_password and AWSAuthenticationPlugin authentication plugins; create the user with one of them."
+}
+
+// tlsRequirements is the REQUIRE clause of an account.
+type tlsRequirements struct {
+	SSL     bool
+	X509    bool
+	Cipher  string
+	Issuer  string
+	Subject string
+}
+
+func tlsRequirementsFromList(list []interface{}) *tlsRequirements {
+	if len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	m := list[0].(map[string]interface{})
+	return &tlsRequirements{
+		SSL:     m["ssl"].(bool),
+		X509:    m["x509"].(bool),
+		Cipher:  m["cipher"].(string),
+		Issuer:  m["issuer"].(string),
+		Subject: m["subject"].(string),
+	}
+}
+
+func (t tlsRequirements) toList() []interface{} {
+	return []interface{}{map[string]interface{}{
+		"ssl":     t.SSL,
+		"x509":    t.X509,
+		"cipher":  t.Cipher,
+		"issuer":  t.Issuer,
+		"subject": t.Subject,
+	}}
+}
+
+func (t tlsRequirements) validate() error {
+	specified := t.Cipher != "" || t.Issuer != "" || t.Subject != ""
+	if (t.SSL && t.X509) || ((t.SSL || t.X509) && specified) {
+		return fmt.Errorf("tls_requirements: ssl and x509 can't be combined with each other or with cipher, issuer or subject")
+	}
+	return nil
+}
+
+// SQL renders the requirements as the body of a REQUIRE clause.
+func (t tlsRequirements) SQL() string {
+	switch {
+	case t.SSL:
+		return "SSL"
+	case t.X509:
+		return "X509"
+	}
+
+	var parts []string
+	if t.Subject != "" {
+		parts = append(parts, "SUBJECT "+quoteLiteral(t.Subject))
+	}
+	if t.Issuer != "" {
+		parts = append(parts, "ISSUER "+quoteLiteral(t.Issuer))
+	}
+	if t.Cipher != "" {
+		parts = append(parts, "CIPHER "+quoteLiteral(t.Cipher))
+	}
+	if len(parts) == 0 {
+		return "NONE"
+	}
+	return strings.Join(parts, " AND ")
+}
+
+var kTLSRequireSpecifiedRegex = regexp.MustCompile(`^ ?(?:(?i:AND) )?((?i:SUBJECT|ISSUER|CIPHER)) '((?:[^'\\]|\\.|'')*)'`)
+
+// parseTLSRequirements parses the REQUIRE clause at the start of clause and returns
+// the requirements along with the remainder of the statement.
+func parseTLSRequirements(clause string) (tlsRequirements, string) {
+	var t tlsRequirements
+	for _, keyword := range []string{"NONE", "SSL", "X509"} {
+		if strings.EqualFold(clause, keyword) || (len(clause) > len(keyword) && strings.EqualFold(clause[:len(keyword)+1], keyword+" ")) {
+			t.SSL = keyword == "SSL"
+			t.X509 = keyword == "X509"
+			return t, clause[len(keyword):]
+		}
+	}
+
+	rest := clause
+	for {
+		m := kTLSRequireSpecifiedRegex.FindStringSubmatch(rest)
+		if m == nil {
+			break
+		}
+		value := unquoteLiteral(m[2])
+		switch strings.ToUpper(m[1]) {
+		case "SUBJECT":
+			t.Subject = value
+		case "ISSUER":
+			t.Issuer = value
+		case "CIPHER":
+			t.Cipher = value
+		}
+		rest = rest[len(m[0]):]
+	}
+	return t, rest
+}
+
+var mariaDBTLSRequireKeywords = []string{"ISSUER", "SUBJECT", "CIPHER"}
 
-	if v, ok := d.GetOk("auth_plugin"); ok {
-		auth = v.(string)
+// mariaDBTLSRequireTerminators can follow the REQUIRE clause in SHOW CREATE USER of MariaDB.
+var mariaDBTLSRequireTerminators = []string{" WITH ", " ACCOUNT ", " PASSWORD "}
+
+// parseMariaDBTLSRequirements is parseTLSRequirements for MariaDB, which prints ISSUER, SUBJECT
+// and CIPHER in that order separated by spaces, and doesn't escape quotes or backslashes in
+// their values. A value therefore ends at the quote followed by the next requirement or by
+// whatever follows the clause.
+func parseMariaDBTLSRequirements(clause string) (tlsRequirements, string) {
+	for _, keyword := range []string{"NONE", "SSL", "X509"} {
+		if clause == keyword || strings.HasPrefix(clause, keyword+" ") {
+			return parseTLSRequirements(clause)
+		}
 	}
 
-	if len(auth) > 0 {
-		if auth == "aad_auth" {
-			// aad_auth is plugin but Microsoft uses another statement to create this kind of users
-			createObj = "AADUSER"
-			if _, ok := d.GetOk("aad_identity"); !ok {
-				return diag.Errorf("aad_identity is required for aad_auth")
-			}
-		} else if auth == "AWSAuthenticationPlugin" {
-			authStm = " IDENTIFIED WITH AWSAuthenticationPlugin as 'RDS'"
-		} else {
-			// mysql_no_login, auth_pam, ...
-			authStm = " IDENTIFIED WITH " + auth
+	var t tlsRequirements
+	rest := clause
+	for i, keyword := range mariaDBTLSRequireKeywords {
+		prefix := keyword + " '"
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(rest, " "), "AND ")
+		if !strings.HasPrefix(trimmed, prefix) {
+			continue
+		}
+		value, remainder, ok := cutMariaDBTLSRequireValue(trimmed[len(prefix):], mariaDBTLSRequireKeywords[i+1:])
+		if !ok {
+			break
 		}
+		switch keyword {
+		case "ISSUER":
+			t.Issuer = value
+		case "SUBJECT":
+			t.Subject = value
+		case "CIPHER":
+			t.Cipher = value
+		}
+		rest = remainder
 	}
-	if v, ok := d.GetOk("auth_string_hashed"); ok {
-		hashed := v.(string)
-		if hashed != "" {
-			if authStm == "" {
-				return diag.Errorf("auth_string_hashed is not supported for auth plugin %s", auth)
+	return t, rest
+}
+
+// cutMariaDBTLSRequireValue splits an unescaped value from what follows its closing quote.
+func cutMariaDBTLSRequireValue(s string, next []string) (value, rest string, ok bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		after := s[i+1:]
+		if after == "" {
+			return s[:i], after, true
+		}
+		for _, terminator := range mariaDBTLSRequireTerminators {
+			if strings.HasPrefix(after, terminator) {
+				return s[:i], after, true
+			}
+		}
+		for _, keyword := range next {
+			if strings.HasPrefix(after, " "+keyword+" '") || strings.HasPrefix(after, " AND "+keyword+" '") {
+				return s[:i], after, true
 			}
-			authStm = fmt.Sprintf("%s AS '%s'", authStm, hashed)
 		}
 	}
+	return "", s, false
+}
 
-	var stmtSQL string
-
-	if createObj == "AADUSER" {
-		var aadIdentity = d.Get("aad_identity").(*schema.Set).List()[0].(map[string]interface{})
-
-		if aadIdentity["type"].(string) == "service_principal" {
-			// CREATE AADUSER 'mysqlProtocolLoginName"@"mysqlHostRestriction' IDENTIFIED BY 'identityId'
-			stmtSQL = fmt.Sprintf("CREATE AADUSER '%s'@'%s' IDENTIFIED BY '%s'",
-				d.Get("user").(string),
-				d.Get("host").(string),
-				aadIdentity["identity"].(string))
-		} else {
-			// CREATE AADUSER 'identityName"@"mysqlHostRestriction' AS 'mysqlProtocolLoginName'
-			stmtSQL = fmt.Sprintf("CREATE AADUSER '%s'@'%s' AS '%s'",
-				aadIdentity["identity"].(string),
-				d.Get("host").(string),
-				d.Get("user").(string))
-		}
-	} else {
-		stmtSQL = fmt.Sprintf("CREATE USER '%s'@'%s'",
-			d.Get("user").(string),
-			d.Get("host").(string))
+// parseTLSOption parses a complete REQUIRE clause as configured in tls_option, e.g.
+// SUBJECT '/CN=app' AND ISSUER '/CN=ca', so it can be rendered with its values escaped.
+func parseTLSOption(option string) (tlsRequirements, error) {
+	tlsReqs, rest := parseTLSRequirements(strings.TrimSpace(option))
+	if strings.TrimSpace(rest) != "" {
+		return tlsReqs, fmt.Errorf("expected NONE, SSL, X509 or SUBJECT, ISSUER and CIPHER with single quoted values joined by AND, got: %s", option)
 	}
+	return tlsReqs, nil
+}
 
-	var password string
-	if v, ok := d.GetOk("plaintext_password"); ok {
-		password = v.(string)
-	} else {
-		password = d.Get("password").(string)
+func validateTLSOption(val any, key string) (warns []string, errs []error) {
+	if _, err := parseTLSOption(val.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q: %w", key, err))
 	}
+	return
+}
 
-	if auth == "AWSAuthenticationPlugin" && d.Get("host").(string) == "localhost" {
-		return diag.Errorf("cannot use IAM auth against localhost")
+// userTLSRequireSQL returns the REQUIRE clause for the configured TLS requirements,
+// falling back to the deprecated tls_option.
+func userTLSRequireSQL(d *schema.ResourceData) (string, error) {
+	if tlsReqs := tlsRequirementsFromList(d.Get("tls_requirements").([]interface{})); tlsReqs != nil {
+		return tlsReqs.SQL(), nil
 	}
+	tlsReqs, err := parseTLSOption(d.Get("tls_option").(string))
+	if err != nil {
+		return "", fmt.Errorf("invalid tls_option: %w", err)
+	}
+	return tlsReqs.SQL(), nil
+}
 
-	if authStm != "" {
-		stmtSQL = stmtSQL + authStm
-	} else if password != "" {
-		stmtSQL = stmtSQL + fmt.Sprintf(" IDENTIFIED BY '%s'", password)
+// tlsOptionDiffSuppressFunc ignores tls_option while tls_requirements is in use, as
+// tls_option always reflects the REQUIRE clause read back from the server.
+func tlsOptionDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	if len(d.Get("tls_requirements").([]interface{})) > 0 {
+		return true
 	}
+	return old == new
+}
 
-	requiredVersion, _ := version.NewVersion("5.7.0")
+func userExists(ctx context.Context, db *sql.DB, user, host string) (bool, error) {
+	stmtSQL := "SELECT COUNT(*) FROM mysql.user WHERE User = ? AND Host = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
 
-	var updateStmtSql = ""
+	var count int
+	if err := db.QueryRowContext(ctx, stmtSQL, user, host).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
 
-	if getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) && d.Get("tls_option").(string) != "" {
-		if createObj == "AADUSER" {
-			updateStmtSql = fmt.Sprintf("ALTER USER '%s'@'%s' REQUIRE %s",
-				d.Get("user").(string),
-				d.Get("host").(string),
-				d.Get("tls_option").(string))
-		} else {
-			stmtSQL += fmt.Sprintf(" REQUIRE %s", d.Get("tls_option").(string))
-		}
+// userHasRetainedPassword returns whether the user still has a secondary password
+// retained by RETAIN CURRENT PASSWORD.
+func userHasRetainedPassword(ctx context.Context, db *sql.DB, d *schema.ResourceData) (bool, error) {
+	stmtSQL := "SELECT IFNULL(JSON_CONTAINS_PATH(User_attributes, 'one', '$.additional_password'), 0) FROM mysql.user WHERE User = ? AND Host = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var hasOldPassword bool
+	err := db.QueryRowContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string)).Scan(&hasOldPassword)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
 	}
+	return hasOldPassword, err
+}
 
-	retainPassword := d.Get("retain_old_password").(bool)
+func checkRandomPasswordSupport(ctx context.Context, meta interface{}) error {
+	return checkCapability(ctx, meta, capabilityRandomPassword)
+}
+
+func getSetRandomPasswordStatement(retainPassword bool) string {
 	if retainPassword {
-		err := checkRetainCurrentPasswordSupport(ctx, meta)
-		if err != nil {
-			return diag.Errorf("cannot use retain_current_password: %v", err)
-		}
+		return "ALTER USER ?@? IDENTIFIED BY RANDOM PASSWORD RETAIN CURRENT PASSWORD"
 	}
+	return "ALTER USER ?@? IDENTIFIED BY RANDOM PASSWORD"
+}
 
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
-	_, err = db.ExecContext(ctx, stmtSQL)
+// queryGeneratedPassword runs a statement using IDENTIFIED BY RANDOM PASSWORD
+// and returns the password the server generated.
+func queryGeneratedPassword(ctx context.Context, db *sql.DB, stmtSQL string, args ...interface{}) (string, error) {
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
 	if err != nil {
-		return diag.Errorf("failed executing SQL: %v", err)
+		return "", err
 	}
+	defer rows.Close()
 
-	user := fmt.Sprintf("%s@%s", d.Get("user").(string), d.Get("host").(string))
-	d.SetId(user)
+	// Columns are user, host, generated password and, since 8.0.27, auth_factor.
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if len(columns) < 3 {
+		return "", fmt.Errorf("unexpected result of random password generation: %v", columns)
+	}
 
-	if updateStmtSql != "" {
-		log.Println("[DEBUG] Executing statement:", updateStmtSql)
-		_, err = db.ExecContext(ctx, updateStmtSql)
-		if err != nil {
-			d.Set("tls_option", "")
-			return diag.Errorf("failed executing SQL: %v", err)
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return "", rows.Err()
 		}
+		return "", errors.New("server didn't return generated password")
 	}
 
-	return nil
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", err
+	}
+
+	return values[2].String, nil
 }
 
 func getSetPasswordStatement(ctx context.Context, meta interface{}, retainPassword bool) (string, error) {
@@ -247,8 +1391,7 @@ func getSetPasswordStatement(ctx context.Context, meta interface{}, retainPasswo
 	}
 
 	/* ALTER USER syntax introduced in MySQL 5.7.6 deprecates SET PASSWORD (GH-8230) */
-	ver, _ := version.NewVersion("5.7.6")
-	if getVersionFromMeta(ctx, meta).LessThan(ver) {
+	if !serverSupports(ctx, meta, capabilityAlterUser) {
 		return "SET PASSWORD FOR ?@? = PASSWORD(?)", nil
 	}
 
@@ -256,42 +1399,77 @@ func getSetPasswordStatement(ctx context.Context, meta interface{}, retainPasswo
 }
 
 func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	oldUser, _ := d.GetChange("user")
+	oldHost, _ := d.GetChange("host")
+	account := UserOrRole{Name: oldUser.(string), Host: oldHost.(string)}
+	objectLocks.Lock(accountLockKey(account))
+	defer objectLocks.Unlock(accountLockKey(account))
+
+	return updateUser(ctx, d, meta, false)
+}
+
+// updateUser is UpdateUser with the lock of the account held, e.g. by CreateUser adopting it.
+// An adopted account only gets the attributes set in the configuration, as every attribute with
+// a default differs from the empty state, and the account keeps its other settings.
+func updateUser(ctx context.Context, d *schema.ResourceData, meta interface{}, adopting bool) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	changed := d.HasChanges
+	if adopting {
+		changed = func(keys ...string) bool {
+			return attributesConfigured(d.GetRawConfig(), keys...)
+		}
+	}
+
+	if d.HasChanges("user", "host") && !d.IsNewResource() {
+		oldUser, newUser := d.GetChange("user")
+		oldHost, newHost := d.GetChange("host")
+
+		stmtSQL := "RENAME USER ?@? TO ?@?"
+		log.Println("[DEBUG] Executing query:", stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL, oldUser.(string), oldHost.(string), newUser.(string), newHost.(string))
+		if err != nil {
+			return sqlErrorDiag("failed renaming user", stmtSQL, err)
+		}
+		d.SetId(fmt.Sprintf("%s@%s", newUser.(string), newHost.(string)))
+	}
+
 	var auth string
 	if v, ok := d.GetOk("auth_plugin"); ok {
 		auth = v.(string)
 	}
-	if len(auth) > 0 {
-		if d.HasChange("tls_option") || d.HasChange("auth_plugin") || d.HasChange("auth_string_hashed") {
-			var stmtSQL string
-
-			authString := ""
-			if d.Get("auth_string_hashed").(string) != "" {
-				authString = fmt.Sprintf("IDENTIFIED WITH %s AS '%s'", d.Get("auth_plugin"), d.Get("auth_string_hashed"))
-			}
-			stmtSQL = fmt.Sprintf("ALTER USER '%s'@'%s' %s  REQUIRE %s",
-				d.Get("user").(string),
-				d.Get("host").(string),
-				authString,
-				d.Get("tls_option").(string))
+	if len(auth) > 0 && auth != "aad_auth" {
+		if changed("auth_plugin") || changed("auth_string_hashed") {
+			stmtSQL := fmt.Sprintf("ALTER USER %s%s",
+				UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}.SQLString(),
+				authPluginSQL(auth, d.Get("auth_string_hashed").(string)))
 
 			log.Println("[DEBUG] Executing query:", stmtSQL)
 			_, err := db.ExecContext(ctx, stmtSQL)
 			if err != nil {
-				return diag.Errorf("failed running query: %v", err)
+				return sqlErrorDiag("failed changing authentication of user", stmtSQL, err)
 			}
 		}
 	}
 
 	var newpw interface{}
-	if d.HasChange("plaintext_password") {
+	if changed("plaintext_password") {
 		_, newpw = d.GetChange("plaintext_password")
-	} else if d.HasChange("password") {
+	} else if changed("password") {
 		_, newpw = d.GetChange("password")
+	} else if changed("plaintext_password_wo_version") || d.IsNewResource() {
+		// Write-only passwords are never in state, so they are only sent when
+		// the version is bumped.
+		writeOnlyPassword, diags := getRawConfigString(d, "plaintext_password_wo")
+		if diags.HasError() {
+			return diags
+		}
+		if writeOnlyPassword != "" {
+			newpw = writeOnlyPassword
+		}
 	} else {
 		newpw = nil
 	}
@@ -304,52 +1482,282 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
+	// With an auth plugin, the password was only dropped from the configuration.
+	if newpw != nil && newpw.(string) == "" && auth != "" {
+		newpw = nil
+	}
+
 	if newpw != nil {
 		stmtSQL, err := getSetPasswordStatement(ctx, meta, retainPassword)
 		if err != nil {
 			return diag.Errorf("failed getting change password statement: %v", err)
 		}
 
+		configuredAuth, diags := getRawConfigString(d, "auth_plugin")
+		if diags.HasError() {
+			return diags
+		}
+		if auth == "AWSAuthenticationPlugin" && configuredAuth == "" {
+			// Moving off IAM auth; IDENTIFIED BY alone would hand the password to the IAM plugin.
+			plugin := defaultAuthPlugin(ctx, db, meta)
+			stmtSQL = fmt.Sprintf("ALTER USER ?@? IDENTIFIED WITH %s BY ?", plugin)
+			d.Set("auth_plugin", plugin)
+			d.Set("auth_string_hashed", "")
+		}
+
 		log.Println("[DEBUG] Executing query:", stmtSQL)
 		_, err = db.ExecContext(ctx, stmtSQL,
 			d.Get("user").(string),
 			d.Get("host").(string),
 			newpw.(string))
 		if err != nil {
-			return diag.Errorf("failed changing password: %v", err)
+			return sqlErrorDiag("failed changing password", stmtSQL, err)
 		}
 	}
 
-	requiredVersion, _ := version.NewVersion("5.7.0")
-	if d.HasChange("tls_option") && getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
-		var stmtSQL string
+	// A password retained just now must survive until clients are rolled over.
+	justRetained := retainPassword && (newpw != nil || changed("random_password"))
+	if changed("discard_old_password") && d.Get("discard_old_password").(bool) && !justRetained {
+		err := checkRetainCurrentPasswordSupport(ctx, meta)
+		if err != nil {
+			return diag.Errorf("cannot use discard_old_password: %v", err)
+		}
 
-		stmtSQL = fmt.Sprintf("ALTER USER '%s'@'%s' REQUIRE %s",
+		stmtSQL := "ALTER USER ?@? DISCARD OLD PASSWORD"
+		log.Println("[DEBUG] Executing query:", stmtSQL)
+		_, err = db.ExecContext(ctx, stmtSQL,
 			d.Get("user").(string),
-			d.Get("host").(string),
-			d.Get("tls_option").(string))
+			d.Get("host").(string))
+		if err != nil {
+			return sqlErrorDiag("failed discarding old password", stmtSQL, err)
+		}
+	}
+
+	if changed("random_password") {
+		if d.Get("random_password").(bool) {
+			if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+				return diag.Errorf("cannot use random_password: %v", err)
+			}
+
+			stmtSQL := getSetRandomPasswordStatement(retainPassword)
+			log.Println("[DEBUG] Executing query:", stmtSQL)
+			generatedPassword, err := queryGeneratedPassword(ctx, db, stmtSQL,
+				d.Get("user").(string),
+				d.Get("host").(string))
+			if err != nil {
+				return sqlErrorDiag("failed changing password", stmtSQL, err)
+			}
+			d.Set("generated_password", generatedPassword)
+		} else {
+			d.Set("generated_password", "")
+		}
+	}
+
+	if changed("tls_option", "tls_requirements") && serverSupports(ctx, meta, capabilityAlterUser) {
+		tlsRequire, err := userTLSRequireSQL(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		stmtSQL := fmt.Sprintf("ALTER USER %s REQUIRE %s",
+			UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}.SQLString(),
+			tlsRequire)
+
+		log.Println("[DEBUG] Executing query:", stmtSQL)
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return sqlErrorDiag("failed setting require tls option", stmtSQL, err)
+		}
+	}
+
+	for _, option := range userPasswordOptions {
+		if !changed(option.Attribute) {
+			continue
+		}
+		if err := option.checkSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use %s: %v", option.Attribute, err)
+		}
+
+		stmtSQL := "ALTER USER ?@? " + option.Clause(option.get(d))
+
+		log.Println("[DEBUG] Executing query:", stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL,
+			d.Get("user").(string),
+			d.Get("host").(string))
+		if err != nil {
+			return sqlErrorDiag(fmt.Sprintf("failed changing %s", option.Attribute), stmtSQL, err)
+		}
+	}
+
+	if changed("comment") || changed("attributes") {
+		if err := checkUserAttributesSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use comment or attributes: %v", err)
+		}
+
+		oldAttrs, newAttrs := d.GetChange("attributes")
+		patch := userAttributesPatch(oldAttrs.(map[string]interface{}), newAttrs.(map[string]interface{}))
+		if changed("comment") {
+			if comment := d.Get("comment").(string); comment != "" {
+				patch["comment"] = comment
+			} else {
+				patch["comment"] = nil
+			}
+		}
+		if err := alterUserAttributes(ctx, db, d, patch); err != nil {
+			return sqlErrorDiag("failed changing user attributes", "", err)
+		}
+	}
+
+	if changed("auth_factor") {
+		if err := checkAuthFactorSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use auth_factor: %v", err)
+		}
+
+		for _, stmtSQL := range authFactorChangeSQL(d) {
+			log.Println("[DEBUG] Executing query:", stmtSQL)
+			_, err := db.ExecContext(ctx, stmtSQL,
+				d.Get("user").(string),
+				d.Get("host").(string))
+			if err != nil {
+				return sqlErrorDiag("failed changing auth factors", stmtSQL, err)
+			}
+		}
+	}
+
+	if changed("default_roles") {
+		if err := setUserDefaultRoles(ctx, db, d, meta, d.Get("default_roles").(*schema.Set)); err != nil {
+			return sqlErrorDiag("failed changing default roles", "", err)
+		}
+	}
+
+	if changed("resource_group") {
+		if err := checkUserResourceGroupSupport(ctx, db); err != nil {
+			return diag.Errorf("cannot use resource_group: %v", err)
+		}
+
+		resourceGroup := d.Get("resource_group").(string)
+		if resourceGroup == "" {
+			resourceGroup = "default"
+		}
+		stmtSQL := "ALTER USER ?@? RESOURCE GROUP " + quoteIdentifier(resourceGroup)
+		log.Println("[DEBUG] Executing query:", stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL,
+			d.Get("user").(string),
+			d.Get("host").(string))
+		if err != nil {
+			return sqlErrorDiag("failed changing resource group", stmtSQL, err)
+		}
+	}
+
+	if changed("account_locked") {
+		if err := checkAccountLockSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use account_locked: %v", err)
+		}
+
+		stmtSQL := "ALTER USER ?@? " + accountLockClause(d.Get("account_locked").(bool))
 
 		log.Println("[DEBUG] Executing query:", stmtSQL)
-		_, err := db.ExecContext(ctx, stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL,
+			d.Get("user").(string),
+			d.Get("host").(string))
 		if err != nil {
-			return diag.Errorf("failed setting require tls option: %v", err)
+			return sqlErrorDiag("failed changing account lock", stmtSQL, err)
+		}
+	}
+
+	return nil
+}
+
+// attributesConfigured reports whether any of the attributes is set in the raw configuration.
+func attributesConfigured(rawConfig cty.Value, keys ...string) bool {
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return false
+	}
+	for _, key := range keys {
+		if !rawConfig.GetAttr(key).IsNull() {
+			return true
+		}
+	}
+	return false
+}
+
+var kAccountLockedRegex = regexp.MustCompile(`\bACCOUNT LOCK\b`)
+
+// Examples of MariaDB create user:
+// CREATE USER `jdoe`@`%` IDENTIFIED BY PASSWORD '*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19' REQUIRE SSL
+// CREATE USER `jdoe`@`%` IDENTIFIED VIA ed25519 USING 'ZIgUREUg5PVgQ6LskhXmO+eZLS0nC8be6HPjYWR4YJY' ACCOUNT LOCK
+var kMariaDBCreateUserRegex = regexp.MustCompile("^CREATE USER " + quotedNamePattern + "@" + quotedNamePattern + "(?: IDENTIFIED (?:BY PASSWORD '([^']*)'|VIA (\\w+)(?: USING '([^']*)')?))?")
+
+func setMariaDBUserOnData(d *schema.ResourceData, m []string, userOptions string) {
+	d.Set("user", unquoteName(m[1]))
+	d.Set("host", unquoteName(m[2]))
+
+	switch {
+	case m[4] != "":
+		d.Set("auth_plugin", m[4])
+		d.Set("auth_string_hashed", m[5])
+	case m[3] != "":
+		d.Set("auth_plugin", "mysql_native_password")
+		d.Set("auth_string_hashed", m[3])
+	default:
+		d.Set("auth_plugin", "")
+		d.Set("auth_string_hashed", "")
+	}
+
+	tlsReqs := tlsRequirements{}
+	if strings.HasPrefix(userOptions, " REQUIRE ") {
+		tlsReqs, userOptions = parseMariaDBTLSRequirements(strings.TrimPrefix(userOptions, " REQUIRE "))
+	}
+	d.Set("tls_option", tlsReqs.SQL())
+	if len(d.Get("tls_requirements").([]interface{})) > 0 {
+		d.Set("tls_requirements", tlsReqs.toList())
+	}
+
+	d.Set("account_locked", kAccountLockedRegex.MatchString(userOptions))
+}
+
+// readMariaDBAccountState reads the lock and password expiry of a MariaDB account, which
+// MariaDB keeps in the Priv JSON of mysql.global_priv rather than in columns of mysql.user.
+// Before 10.4 there's no mysql.global_priv, and neither can be set anyway.
+func readMariaDBAccountState(ctx context.Context, db *sql.DB, d *schema.ResourceData) error {
+	stmtSQL := `SELECT IFNULL(JSON_VALUE(Priv, '$.account_locked'), 'false'), IFNULL(JSON_VALUE(Priv, '$.password_lifetime'), '')
+		FROM mysql.global_priv WHERE User = ? AND Host = ?`
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var locked, lifetime string
+	err := db.QueryRowContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string)).Scan(&locked, &lifetime)
+	if err != nil {
+		if mysqlErrorNumber(err) == unknownTableErrCode {
+			return nil
 		}
+		return err
 	}
 
+	d.Set("account_locked", locked == "true")
+	d.Set("password_expire", mariaDBPasswordExpire(lifetime))
 	return nil
 }
 
+// mariaDBPasswordExpire maps the password_lifetime of mysql.global_priv to password_expire:
+// -1 or none follows default_password_lifetime and 0 never expires.
+func mariaDBPasswordExpire(lifetime string) string {
+	switch lifetime {
+	case "", "-1":
+		return "DEFAULT"
+	case "0":
+		return "NEVER"
+	}
+	return lifetime
+}
+
 func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	requiredVersion, _ := version.NewVersion("5.7.0")
-	if getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
+	if serverSupports(ctx, meta, capabilityAlterUser) {
 		stmt := "SHOW CREATE USER ?@?"
 
-		var createUserStmt string
-		err := db.QueryRowContext(ctx, stmt, d.Get("user").(string), d.Get("host").(string)).Scan(&createUserStmt)
+		rows, err := cachedQuery(ctx, db, stmt, d.Get("user").(string), d.Get("host").(string))
 		if err != nil {
 			errorNumber := mysqlErrorNumber(err)
 			if errorNumber == unknownUserErrCode || errorNumber == userNotFoundErrCode {
@@ -358,59 +1766,109 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 			}
 			return diag.Errorf("failed getting user: %v", err)
 		}
+		if len(rows) != 1 || len(rows[0]) != 1 {
+			return diag.Errorf("failed getting user: unexpected output of %s", stmt)
+		}
+		createUserStmt := rows[0][0].String
 
 		// Examples of create user:
 		// CREATE USER 'some_app'@'%' IDENTIFIED WITH 'mysql_native_password' AS '*0something' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK
 		// CREATE USER `jdoe-tf-test-47`@`example.com` IDENTIFIED WITH 'caching_sha2_password' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT
 		// CREATE USER `jdoe`@`example.com` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$i`xay#fG/\' TrbkNA82' REQUIRE NONE PASSWORD
-		re := regexp.MustCompile("^CREATE USER ['`]([^'`]*)['`]@['`]([^'`]*)['`] IDENTIFIED WITH ['`]([^'`]*)['`] (?:AS '((?:.*?[^\\\\])?)' )?REQUIRE ([^ ]*)")
+		// CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$...' AND IDENTIFIED WITH 'authentication_fido' REQUIRE NONE ...
+		createUserStmt, dbFactors := extractAuthFactors(createUserStmt)
+
+		re := regexp.MustCompile("^CREATE USER " + quotedNamePattern + "@" + quotedNamePattern + " IDENTIFIED WITH ['`\"]([^'`\"]*)['`\"] (?:AS '((?:.*?[^\\\\])?)' )?REQUIRE (.*)$")
 		if m := re.FindStringSubmatch(createUserStmt); len(m) == 6 {
-			d.Set("user", m[1])
-			d.Set("host", m[2])
+			d.Set("user", unquoteName(m[1]))
+			d.Set("host", unquoteName(m[2]))
 			d.Set("auth_plugin", m[3])
-			d.Set("tls_option", m[5])
 
-			if m[3] == "aad_auth" {
-				// AADGroup:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:Doe_Family_Group
-				// AADUser:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:little.johny@does.onmicrosoft.com
-				// AADSP:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:mysqlUserName - for MySQL Flexible Server
-				// AADApp:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:mysqlUserName - for MySQL Single Server
-				parts := strings.Split(m[4], ":")
-				if parts[0] == "AADSP" || parts[0] == "AADApp" {
-					// service principals are referenced by UUID only
-					d.Set("aad_identity", []map[string]interface{}{
-						{
-							"type":     "service_principal",
-							"identity": parts[1],
-						},
-					})
-				} else if len(parts) >= 4 {
-					// users and groups should be referenced by UPN / group name
-					if parts[0] == "AADUser" {
-						d.Set("aad_identity", []map[string]interface{}{
-							{
-								"type":     "user",
-								"identity": strings.Join(parts[3:], ":"),
-							},
-						})
-					} else {
-						d.Set("aad_identity", []map[string]interface{}{
-							{
-								"type":     "group",
-								"identity": strings.Join(parts[3:], ":"),
-							},
-						})
+			// Everything after the TLS requirements holds the password and locking options.
+			tlsReqs, userOptions := parseTLSRequirements(m[5])
+			d.Set("tls_option", tlsReqs.SQL())
+			if len(d.Get("tls_requirements").([]interface{})) > 0 {
+				d.Set("tls_requirements", tlsReqs.toList())
+			}
+
+			setAuthFactorsOnData(d, dbFactors)
+
+			d.Set("account_locked", kAccountLockedRegex.MatchString(userOptions))
+			for _, option := range userPasswordOptions {
+				value := option.Default
+				if om := option.Regex.FindStringSubmatch(userOptions); om != nil {
+					value = om[1]
+					if option.FromMatch != nil {
+						value = option.FromMatch(value)
 					}
-				} else {
-					return diag.Errorf("AAD identity couldn't be parsed - it is %s", m[4])
 				}
+				option.set(d, value)
+			}
+
+			if m[3] == "aad_auth" {
+				identityType, identity, err := parseAADIdentity(unquoteLiteral(m[4]))
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				d.Set("aad_identity", []map[string]interface{}{
+					{
+						"type":     identityType,
+						"identity": identity,
+					},
+				})
 			} else {
 				d.Set("auth_string_hashed", m[4])
 			}
+
+			if d.Get("discard_old_password").(bool) && checkRetainCurrentPasswordSupport(ctx, meta) == nil {
+				hasOldPassword, err := userHasRetainedPassword(ctx, db, d)
+				if err != nil {
+					return diag.Errorf("failed reading retained password: %v", err)
+				}
+				// Report drift so that the next apply discards it.
+				d.Set("discard_old_password", !hasOldPassword)
+			}
+
+			if checkUserAttributesSupport(ctx, meta) == nil {
+				if err := readUserAttributes(ctx, db, d); err != nil {
+					return diag.Errorf("failed reading user attributes: %v", err)
+				}
+			}
+
+			// Only tracked when managed here, so it doesn't fight mysql_default_roles.
+			if d.Get("default_roles").(*schema.Set).Len() > 0 {
+				defaultRoles, err := readUserDefaultRoles(ctx, db, d.Get("user").(string), d.Get("host").(string))
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				d.Set("default_roles", defaultRoles)
+			}
+
+			// Only tracked when managed here, so it doesn't fight mysql_ti_resource_group_user_assignment.
+			if d.Get("resource_group").(string) != "" {
+				stmtSQL := `SELECT JSON_UNQUOTE(IFNULL(JSON_EXTRACT(User_attributes, '$.resource_group'), '')) FROM mysql.user WHERE User = ? AND Host = ?`
+				log.Println("[DEBUG] Executing query:", stmtSQL)
+
+				var resourceGroup string
+				err := db.QueryRowContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string)).Scan(&resourceGroup)
+				if err != nil {
+					return diag.Errorf("failed reading resource group: %v", err)
+				}
+				d.Set("resource_group", resourceGroup)
+			}
+			return nil
+		}
+
+		// Try 2 - MariaDB prints IDENTIFIED VIA / BY PASSWORD instead.
+		if m := kMariaDBCreateUserRegex.FindStringSubmatch(createUserStmt); m != nil {
+			setMariaDBUserOnData(d, m, createUserStmt[len(m[0]):])
+			if err := readMariaDBAccountState(ctx, db, d); err != nil {
+				return diag.Errorf("failed reading account state: %v", err)
+			}
 			return nil
 		}
 
-		// Try 2 - just whether the user is there.
+		// Try 3 - just whether the user is there.
 		re2 := regexp.MustCompile("^CREATE USER")
 		if m := re2.FindStringSubmatch(createUserStmt); m != nil {
 			// Ok, we have at least something - it's probably in MariaDB.
@@ -419,34 +1877,33 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 		return diag.Errorf("Create user couldn't be parsed - it is %s", createUserStmt)
 	} else {
 		// Worse user detection, only for compat with MySQL 5.6
-		stmtSQL := fmt.Sprintf("SELECT USER FROM mysql.user WHERE USER='%s'",
-			d.Get("user").(string))
-
-		log.Println("[DEBUG] Executing statement:", stmtSQL)
-
-		rows, err := db.QueryContext(ctx, stmtSQL)
+		exists, err := userExists(ctx, db, d.Get("user").(string), d.Get("host").(string))
 		if err != nil {
 			return diag.Errorf("failed getting user from DB: %v", err)
 		}
-		defer rows.Close()
-
-		if !rows.Next() && rows.Err() == nil {
+		if !exists {
+			log.Printf("[WARN] User (%s) not found; removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
-		if rows.Err() != nil {
-			return diag.Errorf("failed getting rows: %v", rows.Err())
-		}
 	}
 	return nil
 }
 
 func DeleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("deletion_protection").(bool) {
+		return diag.Errorf("user %s has deletion_protection enabled; set it to false and apply before destroying it", d.Id())
+	}
+
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	account := UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)}
+	objectLocks.Lock(accountLockKey(account))
+	defer objectLocks.Unlock(accountLockKey(account))
+
 	stmtSQL := fmt.Sprintf("DROP USER ?@?")
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
@@ -472,6 +1929,10 @@ func ImportUser(ctx context.Context, d *schema.ResourceData, meta interface{}) (
 	host := userHost[1]
 	d.Set("user", user)
 	d.Set("host", host)
+	// Settings which only exist in Terraform start out at their defaults.
+	d.Set("rename_on_change", false)
+	d.Set("deletion_protection", false)
+	d.Set("on_exists", "fail")
 	err := ReadUser(ctx, d, meta)
 	var ferror error
 	if err.HasError() {
@@ -481,6 +1942,10 @@ func ImportUser(ctx context.Context, d *schema.ResourceData, meta interface{}) (
 	return []*schema.ResourceData{d}, ferror
 }
 
+func caseInsensitiveSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
 func NewEmptyStringSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
 	if new == "" {
 		return true
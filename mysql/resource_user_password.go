@@ -2,12 +2,14 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"strings"
 
 	"github.com/gofrs/uuid"
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -17,27 +19,59 @@ func resourceUserPassword() *schema.Resource {
 		UpdateContext: SetUserPassword,
 		ReadContext:   ReadUserPassword,
 		DeleteContext: DeleteUserPassword,
+		Timeouts:      defaultResourceTimeouts(true),
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportUserPassword,
+		},
 		Schema: map[string]*schema.Schema{
 			"user": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateUserName,
 			},
 			"host": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				Default:  "localhost",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "localhost",
+				ValidateFunc: validateUserHost,
 			},
 			"plaintext_password": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
 
+			"plaintext_password_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"plaintext_password", "random_password"},
+			},
+
+			"plaintext_password_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"plaintext_password_wo"},
+			},
+
 			"retain_old_password": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+
+			"random_password": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"plaintext_password"},
+			},
+
+			"generated_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
 		},
 	}
 }
@@ -48,22 +82,51 @@ func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface
 		return diag.FromErr(err)
 	}
 
+	retainPassword := d.Get("retain_old_password").(bool)
+	if retainPassword {
+		err := checkRetainCurrentPasswordSupport(ctx, meta)
+		if err != nil {
+			return diag.Errorf("cannot use retain_current_password: %v", err)
+		}
+	}
+
+	if d.Get("random_password").(bool) {
+		return setUserRandomPassword(ctx, db, d, meta, retainPassword)
+	}
+	d.Set("generated_password", "")
+
+	// Setting the same password again with RETAIN CURRENT PASSWORD would replace
+	// the retained password, so only a new password is sent.
+	if !d.IsNewResource() && !d.HasChanges("plaintext_password", "plaintext_password_wo_version", "random_password") {
+		return nil
+	}
+
 	uuid, err := uuid.NewV4()
 	if err != nil {
 		return diag.Errorf("failed getting UUID: %v", err)
 	}
 
+	writeOnlyPassword, diags := getRawConfigString(d, "plaintext_password_wo")
+	if diags.HasError() {
+		return diags
+	}
+
 	password, passOk := d.GetOk("plaintext_password")
-	if !passOk {
+	if writeOnlyPassword != "" {
+		password = writeOnlyPassword
+	} else if !passOk {
 		password = uuid.String()
 		d.Set("plaintext_password", password)
-	}
-
-	retainPassword := d.Get("retain_old_password").(bool)
-	if retainPassword {
-		err := checkRetainCurrentPasswordSupport(ctx, meta)
+	} else if oldPassword, _ := d.GetChange("plaintext_password"); !d.IsNewResource() && oldPassword.(string) == "" {
+		// The password of an imported user is unknown until it's configured. When the configured
+		// password is already set, it's only recorded, without resetting it and the retained one.
+		matches, _, err := userPasswordMatches(ctx, db, meta, d.Get("user").(string), d.Get("host").(string), password.(string))
 		if err != nil {
-			return diag.Errorf("cannot use retain_current_password: %v", err)
+			return diag.Errorf("failed verifying password of %s: %v", d.Id(), err)
+		}
+		if matches {
+			log.Printf("[DEBUG] Password of %s is already set; recording it", d.Id())
+			return nil
 		}
 	}
 
@@ -85,18 +148,37 @@ func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface
 	return nil
 }
 
-func canReadPassword(ctx context.Context, meta interface{}) (bool, error) {
-	serverVersion := getVersionFromMeta(ctx, meta)
-	ver, _ := version.NewVersion("8.0.0")
-	return serverVersion.LessThan(ver), nil
-}
+func setUserRandomPassword(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}, retainPassword bool) diag.Diagnostics {
+	user := fmt.Sprintf("%s@%s",
+		d.Get("user").(string),
+		d.Get("host").(string))
 
-func ReadUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	canRead, err := canReadPassword(ctx, meta)
+	// Only generate a new password when it's requested, not on every update.
+	if !d.IsNewResource() && !d.HasChange("random_password") {
+		d.SetId(user)
+		return nil
+	}
+
+	if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+		return diag.Errorf("cannot use random_password: %v", err)
+	}
+
+	stmtSQL := getSetRandomPasswordStatement(retainPassword)
+	generatedPassword, err := queryGeneratedPassword(ctx, db, stmtSQL,
+		d.Get("user").(string),
+		d.Get("host").(string))
 	if err != nil {
-		return diag.Errorf("cannot get whether we can read password: %v", err)
+		return diag.Errorf("failed executing change statement: %v", err)
 	}
-	if !canRead {
+
+	d.Set("generated_password", generatedPassword)
+	d.SetId(user)
+	return nil
+}
+
+func ReadUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("plaintext_password").(string) == "" {
+		// Write-only and generated passwords aren't known, so they can't be verified.
 		return nil
 	}
 
@@ -105,45 +187,62 @@ func ReadUserPassword(ctx context.Context, d *schema.ResourceData, meta interfac
 		return diag.FromErr(err)
 	}
 
-	results, err := db.QueryContext(ctx, `SELECT IF(PASSWORD(?) = authentication_string,'OK','FAIL') result, plugin FROM mysql.user WHERE user = ? AND host = ?`,
-		d.Get("plaintext_password").(string),
-		d.Get("user").(string),
-		d.Get("host").(string),
-	)
+	matches, known, err := userPasswordMatches(ctx, db, meta, d.Get("user").(string), d.Get("host").(string), d.Get("plaintext_password").(string))
 	if err != nil {
-		// For now, we expect we are root.
-		return diag.Errorf("querying auth string failed: %v", err)
+		return diag.Errorf("failed verifying password of %s@%s: %v", d.Get("user").(string), d.Get("host").(string), err)
+	}
+	if known && !matches {
+		log.Printf("[DEBUG] Password of %s@%s was changed outside of Terraform, or the user doesn't exist", d.Get("user").(string), d.Get("host").(string))
+		d.SetId("")
 	}
+	return nil
+}
 
-	for results.Next() {
-		var plugin string
-		var correct string
-		err = results.Scan(&plugin, &correct)
+// userPasswordMatches returns whether password is the password of the user, and whether that
+// is known at all, which it isn't for plugins whose hashes can't be verified. A user which
+// doesn't exist doesn't match. With PASSWORD() the server computes the hash, on other servers
+// it's computed client-side.
+func userPasswordMatches(ctx context.Context, db *sql.DB, meta interface{}, user, host, password string) (bool, bool, error) {
+	if serverSupports(ctx, meta, capabilityPasswordFunction) {
+		var result, plugin string
+		err := db.QueryRowContext(ctx, `SELECT IF(PASSWORD(?) = authentication_string,'OK','FAIL') result, plugin FROM mysql.user WHERE user = ? AND host = ?`,
+			password, user, host,
+		).Scan(&result, &plugin)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, true, nil
+		}
 		if err != nil {
-			return diag.Errorf("failed reading results: %v", err)
+			return false, false, fmt.Errorf("querying auth string failed: %w", err)
 		}
-
 		if plugin != "mysql_native_password" {
-			// We don't know whether the password is fine; it probably is.
-			return nil
+			return false, false, nil
 		}
+		return result == "OK", true, nil
+	}
 
-		if correct == "FAIL" {
-			d.SetId("")
-			return nil
-		}
+	var plugin string
+	var authString []byte
+	err := db.QueryRowContext(ctx, `SELECT plugin, authentication_string FROM mysql.user WHERE user = ? AND host = ?`,
+		user, host,
+	).Scan(&plugin, &authString)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, true, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("querying auth string failed: %w", err)
+	}
 
-		if correct == "OK" {
-			return nil
+	switch plugin {
+	case "mysql_native_password":
+		return nativePasswordHash(password) == string(authString), true, nil
+	case "caching_sha2_password":
+		matches, err := cachingSha2PasswordMatches(password, authString)
+		if err != nil {
+			return false, false, err
 		}
-
-		return diag.Errorf("Unexpected result of query: correct: %v; plugin: %v", correct, plugin)
+		return matches, true, nil
 	}
-
-	// User doesn't exist. Password is certainly wrong in mysql, destroy the resource.
-	log.Printf("[DEBUG] User and host doesn't exist %s@%s", d.Get("user").(string), d.Get("host").(string))
-	d.SetId("")
-	return nil
+	return false, false, nil
 }
 
 func DeleteUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -151,3 +250,30 @@ func DeleteUserPassword(ctx context.Context, d *schema.ResourceData, meta interf
 	// to remove from the state file.
 	return nil
 }
+
+func ImportUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	userHost := strings.SplitN(d.Id(), "@", 2)
+
+	if len(userHost) != 2 {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST)", d.Id())
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := userExists(ctx, db, userHost[0], userHost[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed checking whether user exists: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("user %s doesn't exist", d.Id())
+	}
+
+	// The password is left unknown, so it's only set once the configuration
+	// specifies one.
+	d.Set("user", userHost[0])
+	d.Set("host", userHost[1])
+	return []*schema.ResourceData{d}, nil
+}
@@ -1,8 +1,11 @@
 package mysql
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"context"
+	"fmt"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccUserPassword_basic(t *testing.T) {
@@ -19,10 +22,214 @@ func TestAccUserPassword_basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet("mysql_user_password.test", "plaintext_password"),
 				),
 			},
+			{
+				ResourceName:            "mysql_user_password.test",
+				ImportState:             true,
+				ImportStateId:           "jdoe@localhost",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"plaintext_password"},
+			},
+		},
+	})
+}
+
+func TestAccUserPassword_changedOutsideTerraform(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPasswordConfig_basic,
+			},
+			{
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec("ALTER USER 'jdoe'@'localhost' IDENTIFIED BY 'otherpass'"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccUserPasswordConfig_basic,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserPasswordConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user_password.test", "plaintext_password", "somepass"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserPassword_writeOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPasswordConfig_writeOnly,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user_password.test", "plaintext_password", ""),
+					resource.TestCheckNoResourceAttr("mysql_user_password.test", "plaintext_password_wo"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserPassword_retainOldPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.14")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPasswordConfig_retainOldPassword("password1", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password1"),
+				),
+			},
+			{
+				Config: testAccUserPasswordConfig_retainOldPassword("password2", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password1"),
+					testAccUserAuthValid("jdoe", "password2"),
+				),
+			},
+			{
+				// Turning retain_old_password off doesn't touch the retained password.
+				Config: testAccUserPasswordConfig_retainOldPassword("password2", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password1"),
+					testAccUserAuthValid("jdoe", "password2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserPassword_importKeepsRetainedPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.14")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_user" "test" {
+  user = "jdoe"
+  host = "%"
+}
+`,
+			},
+			{
+				PreConfig: func() {
+					testAccSqlExec(t, "ALTER USER 'jdoe'@'%' IDENTIFIED BY 'password1'")
+					testAccSqlExec(t, "ALTER USER 'jdoe'@'%' IDENTIFIED BY 'password2' RETAIN CURRENT PASSWORD")
+				},
+				Config:             testAccUserPasswordConfig_retainOldPassword("password2", true),
+				ResourceName:       "mysql_user_password.test",
+				ImportState:        true,
+				ImportStateId:      "jdoe@%",
+				ImportStatePersist: true,
+			},
+			{
+				// The configured password is already set, so setting it again doesn't replace password1.
+				Config: testAccUserPasswordConfig_retainOldPassword("password2", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user_password.test", "plaintext_password", "password2"),
+					testAccUserAuthValid("jdoe", "password1"),
+					testAccUserAuthValid("jdoe", "password2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserPasswordConfig_retainOldPassword(password string, retain bool) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user = "jdoe"
+  host = "%%"
+}
+
+resource "mysql_user_password" "test" {
+  user                = mysql_user.test.user
+  host                = mysql_user.test.host
+  plaintext_password  = "%s"
+  retain_old_password = %t
+}
+`, password, retain)
+}
+
+func TestAccUserPassword_random(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.18")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPasswordConfig_random,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttrSet("mysql_user.test", "generated_password"),
+					resource.TestCheckResourceAttrSet("mysql_user_password.test", "generated_password"),
+				),
+			},
 		},
 	})
 }
 
+const testAccUserPasswordConfig_random = `
+resource "mysql_user" "test" {
+  user            = "jdoe"
+  host            = "%"
+  random_password = true
+}
+
+resource "mysql_user_password" "test" {
+  user            = mysql_user.test.user
+  host            = mysql_user.test.host
+  random_password = true
+}
+`
+
+const testAccUserPasswordConfig_writeOnly = `
+resource "mysql_user" "test" {
+  user = "jdoe"
+}
+
+resource "mysql_user_password" "test" {
+  user                          = mysql_user.test.user
+  plaintext_password_wo         = "somepass"
+  plaintext_password_wo_version = 1
+}
+`
+
 const testAccUserPasswordConfig_basic = `
 resource "mysql_user" "test" {
   user = "jdoe"
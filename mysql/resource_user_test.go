@@ -6,13 +6,81 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+func TestValidateUserName(t *testing.T) {
+	valid := []string{"jdoe", "app-reader_01", "little.johny@doe.onmicrosoft.com", strings.Repeat("u", 32), "o'brien", "back\\slash", "tick`"}
+	invalid := []string{"", strings.Repeat("u", 33), "new\nline", "tab\t"}
+
+	for _, v := range valid {
+		if _, errs := validateUserName(v, "user"); len(errs) != 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range invalid {
+		if _, errs := validateUserName(v, "user"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestValidateUserHost(t *testing.T) {
+	valid := []string{"localhost", "%", "", "example.com", "%.example.com", "192.168.%", "192.168.1._",
+		"10.0.0.0/255.255.255.0", "10.0.0.0/8", "::1", "fe80::1", "2001:db8::%"}
+	invalid := []string{"exa mple.com", "host'name", "10.0.0.0/33", "10.0.0.0/255.255.x.0", "example.com/8", "::1/64",
+		strings.Repeat("h", 256)}
+
+	for _, v := range valid {
+		if _, errs := validateUserHost(v, "host"); len(errs) != 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range invalid {
+		if _, errs := validateUserHost(v, "host"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestParseAADIdentity(t *testing.T) {
+	cases := []struct {
+		authString   string
+		identityType string
+		identity     string
+	}{
+		{"AADUser:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:little.johny@does.onmicrosoft.com", "user", "little.johny@does.onmicrosoft.com"},
+		{"AADUser:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:o'brien#EXT#@does.onmicrosoft.com", "user", "o'brien#EXT#@does.onmicrosoft.com"},
+		{"AADGroup:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:Doe_Family_Group", "group", "Doe_Family_Group"},
+		{"AADGroup:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:Team: Platform", "group", "Team: Platform"},
+		{"AADSP:4a4e5f27-3b3c-4c52-9a4f-3e8f1f1a0c11:upn:mysqlUserName", "service_principal", "4a4e5f27-3b3c-4c52-9a4f-3e8f1f1a0c11"},
+		{"AADApp:4a4e5f27-3b3c-4c52-9a4f-3e8f1f1a0c11:upn:mysqlUserName", "service_principal", "4a4e5f27-3b3c-4c52-9a4f-3e8f1f1a0c11"},
+	}
+	for _, c := range cases {
+		identityType, identity, err := parseAADIdentity(c.authString)
+		if err != nil {
+			t.Errorf("parseAADIdentity(%q) failed: %v", c.authString, err)
+			continue
+		}
+		if identityType != c.identityType || identity != c.identity {
+			t.Errorf("parseAADIdentity(%q) = %q, %q, expected %q, %q", c.authString, identityType, identity, c.identityType, c.identity)
+		}
+	}
+
+	for _, authString := range []string{"", "AADSP", "AADUser:98e61c8d", "AADUnknown:98e61c8d:upn:name"} {
+		if _, _, err := parseAADIdentity(authString); err == nil {
+			t.Errorf("expected parseAADIdentity(%q) to fail", authString)
+		}
+	}
+}
+
 func TestAccUser_basic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipMariaDB(t) },
@@ -23,114 +91,879 @@ func TestAccUser_basic(t *testing.T) {
 				Config: testAccUserConfig_basic,
 				Check: resource.ComposeTestCheckFunc(
 					testAccUserExists("mysql_user.test"),
-					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
-					resource.TestCheckResourceAttr("mysql_user.test", "host", "%"),
-					resource.TestCheckResourceAttr("mysql_user.test", "plaintext_password", hashSum("password")),
-					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "%"),
+					resource.TestCheckResourceAttr("mysql_user.test", "plaintext_password", hashSum("password")),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+				),
+			},
+			{
+				Config: testAccUserConfig_ssl,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
+					resource.TestCheckResourceAttr("mysql_user.test", "plaintext_password", hashSum("password")),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "SSL"),
+				),
+			},
+			{
+				Config: testAccUserConfig_newPass,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "%"),
+					resource.TestCheckResourceAttr("mysql_user.test", "plaintext_password", hashSum("password2")),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_auth(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheckSkipTiDB(t); testAccPreCheckSkipMariaDB(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_auth_iam_plugin,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "mysql_no_login"),
+				),
+			},
+			{
+				Config: testAccUserConfig_auth_native,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "mysql_native_password"),
+				),
+			},
+			{
+				Config: testAccUserConfig_auth_iam_plugin,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
+					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "mysql_no_login"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_authConnect(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password"),
+				),
+			},
+			{
+				Config: testAccUserConfig_newPass,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "random"),
+				),
+				ExpectError: regexp.MustCompile(`.*Access denied for user 'jdoe'.*`),
+			},
+			{
+				Config: testAccUserConfig_newPass,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password"),
+				),
+				ExpectError: regexp.MustCompile(`.*Access denied for user 'jdoe'.*`),
+			},
+			{
+				Config: testAccUserConfig_newPass,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_authConnectRetainOldPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.14")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_basic_retain_old_password,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password"),
+				),
+			},
+			{
+				Config: testAccUserConfig_newPass_retain_old_password,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password"),
+					testAccUserAuthValid("jdoe", "password2"),
+				),
+			},
+			{
+				Config: testAccUserConfig_newNewPass_retain_old_password,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password"),
+				),
+				ExpectError: regexp.MustCompile(`.*Access denied for user 'jdoe'.*`),
+			},
+		},
+	})
+}
+
+func TestAccUser_accountLocked(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "5.7.6")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_locked,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "account_locked", "true"),
+				),
+			},
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "account_locked", "false"),
+					testAccUserAuthValid("jdoe", "password"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_passwordExpire(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotCapable(t, capabilityPasswordExpire)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_passwordExpire("90", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_expire", "90"),
+					resource.TestCheckResourceAttr("mysql_user.test", "account_locked", "true"),
+				),
+			},
+			{
+				Config: testAccUserConfig_passwordExpire("NEVER", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_expire", "NEVER"),
+					resource.TestCheckResourceAttr("mysql_user.test", "account_locked", "false"),
+				),
+			},
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_expire", "DEFAULT"),
+				),
+			},
+		},
+	})
+}
+
+func TestMariaDBPasswordExpire(t *testing.T) {
+	tests := map[string]string{
+		"":   "DEFAULT",
+		"-1": "DEFAULT",
+		"0":  "NEVER",
+		"90": "90",
+	}
+	for lifetime, expected := range tests {
+		if got := mariaDBPasswordExpire(lifetime); got != expected {
+			t.Errorf("mariaDBPasswordExpire(%q) = %q, want %q", lifetime, got, expected)
+		}
+	}
+}
+
+func TestAccountOptions(t *testing.T) {
+	passwordOptions := []string{"PASSWORD EXPIRE NEVER"}
+	if got := strings.Join(accountOptions(flavorMySQL, "ACCOUNT LOCK", passwordOptions), " "); got != "PASSWORD EXPIRE NEVER ACCOUNT LOCK" {
+		t.Errorf("unexpected MySQL options: %s", got)
+	}
+	if got := strings.Join(accountOptions(flavorMariaDB, "ACCOUNT LOCK", passwordOptions), " "); got != "ACCOUNT LOCK PASSWORD EXPIRE NEVER" {
+		t.Errorf("unexpected MariaDB options: %s", got)
+	}
+}
+
+func TestParseMariaDBTLSRequirements(t *testing.T) {
+	tests := []struct {
+		clause   string
+		expected tlsRequirements
+		rest     string
+	}{
+		{"SSL ACCOUNT LOCK", tlsRequirements{SSL: true}, " ACCOUNT LOCK"},
+		{"X509", tlsRequirements{X509: true}, ""},
+		{
+			"ISSUER '/CN=ca' SUBJECT '/CN=O'Brien' CIPHER 'EDH-RSA-DES-CBC3-SHA' WITH MAX_QUERIES_PER_HOUR 10",
+			tlsRequirements{Issuer: "/CN=ca", Subject: "/CN=O'Brien", Cipher: "EDH-RSA-DES-CBC3-SHA"},
+			" WITH MAX_QUERIES_PER_HOUR 10",
+		},
+		{"SUBJECT '/CN=a\\,b' PASSWORD EXPIRE NEVER", tlsRequirements{Subject: "/CN=a\\,b"}, " PASSWORD EXPIRE NEVER"},
+	}
+	for _, test := range tests {
+		got, rest := parseMariaDBTLSRequirements(test.clause)
+		if got != test.expected || rest != test.rest {
+			t.Errorf("parseMariaDBTLSRequirements(%q) = %+v, %q, want %+v, %q", test.clause, got, rest, test.expected, test.rest)
+		}
+	}
+}
+
+func TestParseTLSOption(t *testing.T) {
+	tests := []struct {
+		option   string
+		expected string
+	}{
+		{"NONE", "NONE"},
+		{"ssl", "SSL"},
+		{"X509", "X509"},
+		{"", "NONE"},
+		{"SUBJECT '/CN=O''Brien' AND issuer '/CN=ca\\\\'", "SUBJECT '/CN=O''Brien' AND ISSUER '/CN=ca\\\\'"},
+		{"CIPHER 'x\\'; DROP USER root; --'", "CIPHER 'x''; DROP USER root; --'"},
+	}
+	for _, test := range tests {
+		tlsReqs, err := parseTLSOption(test.option)
+		if err != nil {
+			t.Errorf("parseTLSOption(%q): %v", test.option, err)
+			continue
+		}
+		if got := tlsReqs.SQL(); got != test.expected {
+			t.Errorf("parseTLSOption(%q).SQL() = %q, want %q", test.option, got, test.expected)
+		}
+	}
+
+	for _, option := range []string{"SUBJECT '/CN=x'; DROP USER root", "SSL; DROP USER root", "SUBJECT /CN=x"} {
+		if _, errs := validateTLSOption(option, "tls_option"); len(errs) == 0 {
+			t.Errorf("expected tls_option %q to be invalid", option)
+		}
+	}
+
+	grant := &TablePrivilegeGrant{Database: "app", Privileges: []string{"SELECT"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "subject '/CN=O''Brien'"}
+	if got, expected := grant.SQLGrantStatement(), "GRANT SELECT ON `app`.* TO 'jdoe'@'%' REQUIRE SUBJECT '/CN=O''Brien'"; got != expected {
+		t.Errorf("SQLGrantStatement = %q, want %q", got, expected)
+	}
+
+	factor := authFactor{Plugin: "authentication_ldap_simple", PlaintextPassword: "it's\\"}
+	if got, expected := factor.identifiedSQL(), "IDENTIFIED WITH authentication_ldap_simple BY 'it''s\\\\'"; got != expected {
+		t.Errorf("identifiedSQL = %q, want %q", got, expected)
+	}
+	if _, errs := validateAuthPlugin("mysql_native_password BY 'x'", "auth_plugin"); len(errs) == 0 {
+		t.Errorf("expected a plugin name with SQL to be invalid")
+	}
+}
+
+func TestAccUser_tlsRequirements(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "5.7.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_tlsRequirementsSpecified,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.subject", "/C=SE/O=O'Reilly/CN=jdoe"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.issuer", "/C=SE/O=Example CA/CN=root"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.cipher", "ECDHE-RSA-AES256-GCM-SHA384"),
+				),
+			},
+			{
+				Config: testAccUserConfig_tlsRequirementsX509,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.x509", "true"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.subject", ""),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "X509"),
+				),
+			},
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.#", "0"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_mariaDBEd25519(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotMariaDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						return
+					}
+					// Fails harmlessly when the plugin is already installed.
+					db.Exec("INSTALL SONAME 'auth_ed25519'")
+				},
+				Config: testAccUserConfig_ed25519,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "ed25519"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_string_hashed", "ZIgUREUg5PVgQ6LskhXmO+eZLS0nC8be6HPjYWR4YJY"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_onExists(t *testing.T) {
+	for _, onExists := range []string{"adopt", "replace"} {
+		t.Run(onExists, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				PreCheck: func() {
+					testAccPreCheckSkipMariaDB(t)
+					testAccPreCheckSkipNotMySQLVersionMin(t, "5.7.0")
+				},
+				ProviderFactories: testAccProviderFactories,
+				CheckDestroy:      testAccUserCheckDestroy,
+				Steps: []resource.TestStep{
+					{
+						PreConfig: func() {
+							ctx := context.Background()
+							db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+							if err != nil {
+								t.Fatal(err)
+							}
+							if _, err := db.Exec("CREATE USER 'jdoe'@'%' IDENTIFIED BY 'handmade'"); err != nil {
+								t.Fatal(err)
+							}
+						},
+						Config: testAccUserConfig_onExists(onExists),
+						Check: resource.ComposeTestCheckFunc(
+							testAccUserExists("mysql_user.test"),
+							resource.TestCheckResourceAttr("mysql_user.test", "on_exists", onExists),
+							testAccUserAuthValid("jdoe", "password"),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccUser_adoptKeepsAccountSettings(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		// Runs on MySQL 5.7 and MariaDB, which lack some of the password options with defaults.
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					testAccSqlExec(t, "CREATE USER 'jdoe'@'%' IDENTIFIED BY 'handmade'")
+					if serverSupports(context.Background(), testAccProvider.Meta(), capabilityPasswordReuse) {
+						testAccSqlExec(t, "ALTER USER 'jdoe'@'%' PASSWORD HISTORY 3")
+					}
+				},
+				Config: testAccUserConfig_adopt,
+				// The kept password history differs from the default of the configuration.
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccUserAuthValid("jdoe", "handmade"),
+					testAccUserPasswordHistoryKept("jdoe", "%", 3),
+				),
+			},
+		},
+	})
+}
+
+// testAccUserPasswordHistoryKept checks the account kept its PASSWORD HISTORY on servers that
+// have it.
+func testAccUserPasswordHistoryKept(user, host string, history int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		if !serverSupports(ctx, testAccProvider.Meta(), capabilityPasswordReuse) {
+			return nil
+		}
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+		var got sql.NullInt64
+		if err := db.QueryRow("SELECT Password_reuse_history FROM mysql.user WHERE User = ? AND Host = ?", user, host).Scan(&got); err != nil {
+			return err
+		}
+		if !got.Valid || got.Int64 != int64(history) {
+			return fmt.Errorf("expected password history %d to be kept, got %v", history, got)
+		}
+		return nil
+	}
+}
+
+func TestAttributesConfigured(t *testing.T) {
+	ty := resourceUser().CoreConfigSchema().ImpliedType()
+	attrs := map[string]cty.Value{}
+	for name, attrType := range ty.AttributeTypes() {
+		attrs[name] = cty.NullVal(attrType)
+	}
+	attrs["user"] = cty.StringVal("jdoe")
+	attrs["password_history"] = cty.NumberIntVal(5)
+	rawConfig := cty.ObjectVal(attrs)
+
+	if !attributesConfigured(rawConfig, "password_history") {
+		t.Error("expected password_history to be configured")
+	}
+	for _, key := range []string{"password_expire", "password_require_current", "tls_option", "account_locked"} {
+		if attributesConfigured(rawConfig, key) {
+			t.Errorf("expected %s not to be configured", key)
+		}
+	}
+	if attributesConfigured(cty.NullVal(ty), "user") {
+		t.Error("expected nothing to be configured without a configuration")
+	}
+}
+
+func TestAccUser_renameOnChange(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "5.7.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_rename("jdoe"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccUserAuthValid("jdoe", "password"),
+				),
+			},
+			{
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					// A recreated user would lose this grant.
+					if _, err := db.Exec("GRANT PROCESS ON *.* TO 'jdoe'@'%'"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccUserConfig_rename("jdoe2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "id", "jdoe2@%"),
+					testAccUserAuthValid("jdoe2", "password"),
+					func(s *terraform.State) error {
+						ctx := context.Background()
+						db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+						if err != nil {
+							return err
+						}
+						var grant string
+						if err := db.QueryRow("SHOW GRANTS FOR 'jdoe2'@'%'").Scan(&grant); err != nil {
+							return err
+						}
+						if !regexp.MustCompile(`\bPROCESS\b`).MatchString(grant) {
+							return fmt.Errorf("grant was lost on rename: %s", grant)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_writeOnlyPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "5.7.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_writeOnlyPassword("password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckNoResourceAttr("mysql_user.test", "plaintext_password_wo"),
+					testAccUserAuthValid("jdoe", "password"),
+				),
+			},
+			{
+				// The password only changes together with the version.
+				Config: testAccUserConfig_writeOnlyPassword("password2", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password"),
+				),
+			},
+			{
+				Config: testAccUserConfig_writeOnlyPassword("password2", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "plaintext_password_wo_version", "2"),
+					testAccUserAuthValid("jdoe", "password2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_deletionProtection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_deletionProtection(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+				),
+			},
+			{
+				Config:      testAccUserConfig_deletionProtection(true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("deletion_protection enabled"),
+			},
+			{
+				Config: testAccUserConfig_deletionProtection(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_resourceGroup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipNotTiDB(t)
+			testAccPreCheckSkipNotTiDBVersionMin(t, ResourceGroupTiDBMinVersion)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_resourceGroup(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "resource_group", "tf-test-rg-jdoe"),
+					testAccResourceGroupUserAssignmentExists("jdoe", "tf-test-rg-jdoe"),
+				),
+			},
+			{
+				Config: testAccUserConfig_resourceGroup(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "resource_group", ""),
+					testAccResourceGroupUserAssignmentExists("jdoe", "default"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_defaultRoles(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_defaultRoles(`["tf-test-role1"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccDefaultRoles("mysql_user.test", "tf-test-role1"),
+					resource.TestCheckResourceAttr("mysql_user.test", "default_roles.#", "1"),
+				),
+			},
+			{
+				Config: testAccUserConfig_defaultRoles(`["tf-test-role1", "tf-test-role2"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDefaultRoles("mysql_user.test", "tf-test-role1", "tf-test-role2"),
+					resource.TestCheckResourceAttr("mysql_user.test", "default_roles.#", "2"),
+				),
+			},
+			{
+				Config: testAccUserConfig_defaultRoles(`[]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDefaultRoles("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "default_roles.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_aadImport(t *testing.T) {
+	upn := os.Getenv("MYSQL_AAD_USER_UPN")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if upn == "" {
+				t.Skip("MYSQL_AAD_USER_UPN must be set to an Azure AD user to run this test against Azure Database for MySQL")
+			}
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_aad(upn),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "aad_auth"),
+				),
+			},
+			{
+				ResourceName:            "mysql_user.test",
+				ImportState:             true,
+				ImportStateId:           "jdoe-aad@%",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"discard_old_password", "retain_old_password"},
+			},
+		},
+	})
+}
+
+func TestAccUser_switchToIAMAuth(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheckSkipNotRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccUserAuthValid("jdoe", "password"),
 				),
 			},
 			{
-				Config: testAccUserConfig_ssl,
+				Config: testAccUserConfig_iam,
 				Check: resource.ComposeTestCheckFunc(
 					testAccUserExists("mysql_user.test"),
-					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
-					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
-					resource.TestCheckResourceAttr("mysql_user.test", "plaintext_password", hashSum("password")),
-					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "SSL"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "AWSAuthenticationPlugin"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_string_hashed", "RDS"),
 				),
 			},
 			{
-				Config: testAccUserConfig_newPass,
+				Config: testAccUserConfig_basic,
 				Check: resource.ComposeTestCheckFunc(
 					testAccUserExists("mysql_user.test"),
-					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
-					resource.TestCheckResourceAttr("mysql_user.test", "host", "%"),
-					resource.TestCheckResourceAttr("mysql_user.test", "plaintext_password", hashSum("password2")),
-					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "NONE"),
+					testAccUserAuthValid("jdoe", "password"),
 				),
 			},
 		},
 	})
 }
 
-func TestAccUser_auth(t *testing.T) {
+func TestAccUser_passwordReuse(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccPreCheckSkipTiDB(t); testAccPreCheckSkipMariaDB(t); testAccPreCheckSkipRds(t) },
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.3")
+		},
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy:      testAccUserCheckDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccUserConfig_auth_iam_plugin,
+				Config: testAccUserConfig_passwordReuse,
 				Check: resource.ComposeTestCheckFunc(
-					testAccUserAuthExists("mysql_user.test"),
-					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
-					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
-					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "mysql_no_login"),
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_history", "5"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_reuse_interval", "365"),
 				),
 			},
 			{
-				Config: testAccUserConfig_auth_native,
+				Config: testAccUserConfig_basic,
 				Check: resource.ComposeTestCheckFunc(
-					testAccUserAuthExists("mysql_user.test"),
-					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
-					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
-					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "mysql_native_password"),
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_history", "DEFAULT"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_reuse_interval", "DEFAULT"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_passwordRequireCurrent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.13")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_passwordRequireCurrent("REQUIRED"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_require_current", "REQUIRED"),
 				),
 			},
 			{
-				Config: testAccUserConfig_auth_iam_plugin,
+				Config: testAccUserConfig_passwordRequireCurrent("optional"),
 				Check: resource.ComposeTestCheckFunc(
-					testAccUserAuthExists("mysql_user.test"),
-					resource.TestCheckResourceAttr("mysql_user.test", "user", "jdoe"),
-					resource.TestCheckResourceAttr("mysql_user.test", "host", "example.com"),
-					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "mysql_no_login"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_require_current", "OPTIONAL"),
+				),
+			},
+			{
+				Config: testAccUserConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "password_require_current", "DEFAULT"),
 				),
 			},
 		},
 	})
 }
 
-func TestAccUser_authConnect(t *testing.T) {
+func TestAccUser_failedLoginTracking(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
-			testAccPreCheckSkipTiDB(t)
 			testAccPreCheckSkipMariaDB(t)
-			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.19")
 		},
 		ProviderFactories: testAccProviderFactories,
 		CheckDestroy:      testAccUserCheckDestroy,
 		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_failedLoginTracking,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "failed_login_attempts", "3"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_lock_time", "UNBOUNDED"),
+				),
+			},
 			{
 				Config: testAccUserConfig_basic,
 				Check: resource.ComposeTestCheckFunc(
-					testAccUserAuthValid("jdoe", "password"),
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "failed_login_attempts", "0"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_lock_time", "0"),
 				),
 			},
+		},
+	})
+}
+
+func TestAccUser_commentAndAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.21")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
 			{
-				Config: testAccUserConfig_newPass,
+				Config: testAccUserConfig_commentAndAttributes,
 				Check: resource.ComposeTestCheckFunc(
-					testAccUserAuthValid("jdoe", "random"),
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "comment", "owned by team-a"),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.%", "2"),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.team", "a"),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.ticket", "OPS-1"),
 				),
-				ExpectError: regexp.MustCompile(`.*Access denied for user 'jdoe'.*`),
 			},
 			{
-				Config: testAccUserConfig_newPass,
+				Config: testAccUserConfig_basic,
 				Check: resource.ComposeTestCheckFunc(
-					testAccUserAuthValid("jdoe", "password"),
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "comment", ""),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.%", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_authFactor(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.27")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_authFactor,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_factor.#", "1"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_factor.0.plugin", "caching_sha2_password"),
 				),
-				ExpectError: regexp.MustCompile(`.*Access denied for user 'jdoe'.*`),
 			},
 			{
-				Config: testAccUserConfig_newPass,
+				Config: testAccUserConfig_basic,
 				Check: resource.ComposeTestCheckFunc(
-					testAccUserAuthValid("jdoe", "password2"),
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_factor.#", "0"),
 				),
 			},
 		},
 	})
 }
 
-func TestAccUser_authConnectRetainOldPassword(t *testing.T) {
+func TestAccUser_discardOldPassword(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheckSkipMariaDB(t)
@@ -154,7 +987,14 @@ func TestAccUser_authConnectRetainOldPassword(t *testing.T) {
 				),
 			},
 			{
-				Config: testAccUserConfig_newNewPass_retain_old_password,
+				Config: testAccUserConfig_newPass_discard_old_password,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserAuthValid("jdoe", "password2"),
+					resource.TestCheckResourceAttr("mysql_user.test", "discard_old_password", "true"),
+				),
+			},
+			{
+				Config: testAccUserConfig_newPass_discard_old_password,
 				Check: resource.ComposeTestCheckFunc(
 					testAccUserAuthValid("jdoe", "password"),
 				),
@@ -309,6 +1149,232 @@ resource "mysql_user" "test" {
 }
 `
 
+const testAccUserConfig_locked = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password"
+    account_locked = true
+}
+`
+
+const testAccUserConfig_tlsRequirementsSpecified = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password"
+    tls_requirements {
+        subject = "/C=SE/O=O'Reilly/CN=jdoe"
+        issuer  = "/C=SE/O=Example CA/CN=root"
+        cipher  = "ECDHE-RSA-AES256-GCM-SHA384"
+    }
+}
+`
+
+const testAccUserConfig_tlsRequirementsX509 = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password"
+    tls_requirements {
+        x509 = true
+    }
+}
+`
+
+const testAccUserConfig_ed25519 = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    auth_plugin = "ed25519"
+    auth_string_hashed = "ZIgUREUg5PVgQ6LskhXmO+eZLS0nC8be6HPjYWR4YJY"
+}
+`
+
+func testAccUserConfig_onExists(onExists string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%%"
+    plaintext_password = "password"
+    on_exists = "%s"
+}
+`, onExists)
+}
+
+const testAccUserConfig_adopt = `
+resource "mysql_user" "test" {
+    user      = "jdoe"
+    host      = "%"
+    on_exists = "adopt"
+}
+`
+
+func testAccUserConfig_rename(user string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user = "%s"
+    host = "%%"
+    plaintext_password = "password"
+    rename_on_change = true
+}
+`, user)
+}
+
+func testAccUserConfig_writeOnlyPassword(password string, version int) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%%"
+    plaintext_password_wo = "%s"
+    plaintext_password_wo_version = %d
+}
+`, password, version)
+}
+
+func testAccUserConfig_deletionProtection(deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "example.com"
+    plaintext_password = "password"
+    deletion_protection = %t
+}
+`, deletionProtection)
+}
+
+func testAccUserConfig_resourceGroup(assigned bool) string {
+	resourceGroup := ""
+	if assigned {
+		resourceGroup = "resource_group = mysql_ti_resource_group.test.name"
+	}
+	return fmt.Sprintf(`
+resource "mysql_ti_resource_group" "test" {
+    name = "tf-test-rg-jdoe"
+    resource_units = 100
+}
+
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%%"
+    plaintext_password = "password"
+    %s
+}
+`, resourceGroup)
+}
+
+func testAccUserConfig_defaultRoles(roles string) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "role1" {
+    name = "tf-test-role1"
+}
+
+resource "mysql_role" "role2" {
+    name = "tf-test-role2"
+}
+
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%%"
+    plaintext_password = "password"
+    default_roles = %s
+
+    depends_on = [mysql_role.role1, mysql_role.role2]
+}
+`, roles)
+}
+
+func testAccUserConfig_aad(upn string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user = "jdoe-aad"
+    host = "%%"
+    auth_plugin = "aad_auth"
+    aad_identity {
+        type = "user"
+        identity = "%s"
+    }
+}
+`, upn)
+}
+
+const testAccUserConfig_iam = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    auth_plugin = "AWSAuthenticationPlugin"
+}
+`
+
+const testAccUserConfig_passwordReuse = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password"
+    password_history = "5"
+    password_reuse_interval = "365"
+}
+`
+
+func testAccUserConfig_passwordExpire(passwordExpire string, locked bool) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%%"
+    plaintext_password = "password"
+    password_expire = "%s"
+    account_locked = %t
+}
+`, passwordExpire, locked)
+}
+
+func testAccUserConfig_passwordRequireCurrent(value string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%%"
+    plaintext_password = "password"
+    password_require_current = "%s"
+}
+`, value)
+}
+
+const testAccUserConfig_failedLoginTracking = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password"
+    failed_login_attempts = 3
+    password_lock_time = "UNBOUNDED"
+}
+`
+
+const testAccUserConfig_commentAndAttributes = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password"
+    comment = "owned by team-a"
+    attributes = {
+        team   = "a"
+        ticket = "OPS-1"
+    }
+}
+`
+
+const testAccUserConfig_authFactor = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password"
+
+    auth_factor {
+        plugin             = "caching_sha2_password"
+        plaintext_password = "second-password"
+    }
+}
+`
+
 const testAccUserConfig_ssl = `
 resource "mysql_user" "test" {
 	user = "jdoe"
@@ -379,6 +1445,15 @@ resource "mysql_user" "test" {
 }
 `
 
+const testAccUserConfig_newPass_discard_old_password = `
+resource "mysql_user" "test" {
+    user = "jdoe"
+    host = "%"
+    plaintext_password = "password2"
+    discard_old_password = true
+}
+`
+
 const testAccUserConfig_newNewPass_retain_old_password = `
 resource "mysql_user" "test" {
     user = "jdoe"
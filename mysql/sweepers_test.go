@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// The sweepers drop the objects of interrupted acceptance test runs, which would otherwise break
+// the next run against the same server. They use the MYSQL_* variables of the acceptance tests:
+//
+//	TF_ACC=1 go test ./mysql -v -sweep=local
+//
+// The region is ignored, as there's only the one server.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// The names the acceptance tests give to the objects they create, as LIKE patterns.
+var (
+	sweepDatabasePatterns      = []string{"tf-test-%", "tf\\_sql\\_test", "terraform\\_acceptance\\_test"}
+	sweepUserPatterns          = []string{"jdoe-%"}
+	sweepRolePatterns          = []string{"tf-test-%"}
+	sweepResourceGroupPatterns = []string{"tf-test-rg-%"}
+)
+
+func init() {
+	resource.AddTestSweepers("mysql_database", &resource.Sweeper{
+		Name: "mysql_database",
+		F:    sweepDatabases,
+	})
+	resource.AddTestSweepers("mysql_user", &resource.Sweeper{
+		Name: "mysql_user",
+		F:    sweepUsers,
+	})
+	resource.AddTestSweepers("mysql_role", &resource.Sweeper{
+		Name: "mysql_role",
+		F:    sweepRoles,
+		// Roles still granted to test users are dropped along with the users first.
+		Dependencies: []string{"mysql_user"},
+	})
+	resource.AddTestSweepers("mysql_ti_resource_group", &resource.Sweeper{
+		Name:         "mysql_ti_resource_group",
+		F:            sweepResourceGroups,
+		Dependencies: []string{"mysql_user"},
+	})
+}
+
+// sweeperDB connects with the same environment variables as the acceptance tests.
+func sweeperDB(ctx context.Context) (*sql.DB, error) {
+	provider := Provider()
+	raw := map[string]interface{}{
+		"conn_params": map[string]interface{}{},
+	}
+	if diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(raw)); diags.HasError() {
+		return nil, fmt.Errorf("failed configuring the provider: %v", diags)
+	}
+	return connectToMySQL(ctx, provider.Meta().(*MySQLConfiguration))
+}
+
+// sweepNames returns the rows of query for each of the LIKE patterns.
+func sweepNames(ctx context.Context, db *sql.DB, query string, patterns []string) ([][]string, error) {
+	var names [][]string
+	for _, pattern := range patterns {
+		rows, err := cachedQuery(ctx, db, query, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			values := make([]string, len(row))
+			for i, value := range row {
+				values[i] = value.String
+			}
+			names = append(names, values)
+		}
+	}
+	return names, nil
+}
+
+// sweepExec runs the statements dropping leaked objects, carrying on past failures.
+func sweepExec(ctx context.Context, db *sql.DB, statements []string) error {
+	var errs []error
+	for _, stmt := range statements {
+		log.Printf("[INFO] Sweeping: %s", stmt)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stmt, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sweepDatabases(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	names, err := sweepNames(ctx, db, "SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME LIKE ?", sweepDatabasePatterns)
+	if err != nil {
+		return fmt.Errorf("failed listing databases: %w", err)
+	}
+	var statements []string
+	for _, name := range names {
+		statements = append(statements, fmt.Sprintf("DROP DATABASE IF EXISTS %s", quoteIdentifier(name[0])))
+	}
+	return sweepExec(ctx, db, statements)
+}
+
+func sweepUsers(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := sweepNames(ctx, db, "SELECT User, Host FROM mysql.user WHERE User LIKE ?", sweepUserPatterns)
+	if err != nil {
+		return fmt.Errorf("failed listing users: %w", err)
+	}
+	var statements []string
+	for _, account := range accounts {
+		statements = append(statements, fmt.Sprintf("DROP USER IF EXISTS %s", UserOrRole{Name: account[0], Host: account[1]}.SQLString()))
+	}
+	return sweepExec(ctx, db, statements)
+}
+
+func sweepRoles(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB(ctx)
+	if err != nil {
+		return err
+	}
+	flavor, flavorVersion, err := detectFlavor(ctx, db)
+	if err != nil {
+		return err
+	}
+	if err := flavorSupports(flavor, flavorVersion, capabilityRoles); err != nil {
+		log.Printf("[INFO] Skipping the role sweeper: %v", err)
+		return nil
+	}
+
+	accounts, err := sweepNames(ctx, db, "SELECT User, Host FROM mysql.user WHERE User LIKE ?", sweepRolePatterns)
+	if err != nil {
+		return fmt.Errorf("failed listing roles: %w", err)
+	}
+	var statements []string
+	for _, account := range accounts {
+		// Users with the same names, e.g. of other tests, are left alone.
+		if exists, err := roleExists(ctx, db, account[0], account[1]); err != nil || !exists {
+			continue
+		}
+		statements = append(statements, fmt.Sprintf("DROP ROLE %s", UserOrRole{Name: account[0], Host: account[1]}.SQLString()))
+	}
+	return sweepExec(ctx, db, statements)
+}
+
+func sweepResourceGroups(_ string) error {
+	ctx := context.Background()
+	db, err := sweeperDB(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkTiDBFeatureSupport(ctx, db, "resource groups", ResourceGroupTiDBMinVersion); err != nil {
+		log.Printf("[INFO] Skipping the resource group sweeper: %v", err)
+		return nil
+	}
+
+	names, err := sweepNames(ctx, db, "SELECT NAME FROM information_schema.resource_groups WHERE NAME LIKE ?", sweepResourceGroupPatterns)
+	if err != nil {
+		return fmt.Errorf("failed listing resource groups: %w", err)
+	}
+	var statements []string
+	for _, name := range names {
+		statements = append(statements, fmt.Sprintf("DROP RESOURCE GROUP IF EXISTS %s", quoteIdentifier(name[0])))
+	}
+	return sweepExec(ctx, db, statements)
+}
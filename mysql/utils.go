@@ -9,9 +9,14 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"google.golang.org/api/googleapi"
 	"log"
+	"regexp"
+	"strings"
 	"sync"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 type KeyedMutex struct {
@@ -63,6 +68,41 @@ func getDatabaseFromMeta(ctx context.Context, meta interface{}) (*sql.DB, error)
 	return oneConnection.Db, nil
 }
 
+// getInstanceDatabaseFromMeta connects to a single server of a cluster, e.g. a TiDB instance,
+// with the provider's settings and credentials.
+func getInstanceDatabaseFromMeta(ctx context.Context, meta interface{}, instance string) (*sql.DB, error) {
+	mysqlConf := *meta.(*MySQLConfiguration)
+	mysqlConf.Config = mysqlConf.Config.Clone()
+	mysqlConf.Config.Net = "tcp"
+	mysqlConf.Config.Addr = instance
+
+	oneConnection, err := connectToMySQLInternal(ctx, &mysqlConf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to instance %s: %v", instance, err)
+	}
+
+	return oneConnection.Db, nil
+}
+
+// scanRowMap scans the current row into a map of the lowercased column names to the values,
+// for result sets whose columns differ between server versions. NULL is scanned as "".
+func scanRowMap(rows *sql.Rows, columns []string) (map[string]string, error) {
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]string, len(columns))
+	for i, column := range columns {
+		row[strings.ToLower(column)] = values[i].String
+	}
+	return row, nil
+}
+
 func getVersionFromMeta(ctx context.Context, meta interface{}) *version.Version {
 	mysqlConf := meta.(*MySQLConfiguration)
 	oneConnection, err := connectToMySQLInternal(ctx, mysqlConf)
@@ -73,6 +113,16 @@ func getVersionFromMeta(ctx context.Context, meta interface{}) *version.Version
 	return oneConnection.Version
 }
 
+// getRdsFromMeta returns whether the server is Amazon RDS and whether it's Aurora, as detected
+// when connecting.
+func getRdsFromMeta(ctx context.Context, meta interface{}) (bool, bool) {
+	oneConnection, err := connectToMySQLInternal(ctx, meta.(*MySQLConfiguration))
+	if err != nil {
+		return false, false
+	}
+	return oneConnection.Rds, oneConnection.Aurora
+}
+
 // 0 == not mysql error or not error at all.
 func mysqlErrorNumber(err error) uint16 {
 	if err == nil {
@@ -86,6 +136,72 @@ func mysqlErrorNumber(err error) uint16 {
 	return mysqlError.Number
 }
 
+// mysqlErrorHints are actionable hints for common server errors by error number.
+var mysqlErrorHints = map[uint16]string{
+	1044: "The provider user lacks privileges on the database; grant them, e.g. with GRANT OPTION for managing grants.",
+	1045: "Check the username and password of the provider.",
+	1049: "The database doesn't exist; create it first, e.g. with mysql_database.",
+	1064: "The statement isn't supported by the syntax of this server version or flavor.",
+	1142: "The provider user lacks privileges on the table.",
+	1146: "The table doesn't exist.",
+	1193: "The system variable doesn't exist on this server version or flavor.",
+	1205: "A lock wait timed out because of concurrent statements; retry the apply.",
+	1213: "A deadlock was detected with concurrent statements; retry the apply.",
+	1227: "The statement requires a privilege such as SUPER or SYSTEM_VARIABLES_ADMIN. RDS doesn't grant SUPER; use mysql_rds_config for RDS settings.",
+	1396: "The user or role already exists when creating it, or doesn't exist when changing it.",
+	1410: "GRANT doesn't create users on MySQL 8; create the user first, e.g. with mysql_user.",
+	1419: "Creating routines with binary logging requires SUPER; on RDS set log_bin_trust_function_creators in the parameter group.",
+	3530: "Only roles granted to the user can be default roles; grant them first.",
+}
+
+// sqlSecretRe matches string literals of passwords, authentication strings and backup storage
+// URIs, which may hold credentials.
+var sqlSecretRe = regexp.MustCompile(`(?i)(IDENTIFIED(?:\s+WITH\s+\S+)?\s+(?:BY|AS)\s+(?:PASSWORD\s*)?|PASSWORD\s*(?:=\s*)?(?:\(\s*)?|BACKUP\s+LOGS\s+TO\s+)'(?:[^'\\]|\\.|'')*'`)
+
+// redactSQL replaces passwords and authentication strings in stmtSQL, so it can be shown in
+// diagnostics and logs.
+func redactSQL(stmtSQL string) string {
+	return sqlSecretRe.ReplaceAllString(stmtSQL, "${1}'***'")
+}
+
+// sqlErrorDiag returns a diagnostic for err of executing stmtSQL, whose detail has the redacted
+// statement, the server error number and a hint for common errors.
+func sqlErrorDiag(summary string, stmtSQL string, err error) diag.Diagnostics {
+	var detail strings.Builder
+	if stmtSQL != "" {
+		fmt.Fprintf(&detail, "Statement: %s\n", redactSQL(stmtSQL))
+	}
+	if errNum := mysqlErrorNumber(err); errNum != 0 {
+		fmt.Fprintf(&detail, "MySQL error number: %d\n", errNum)
+		if hint, ok := mysqlErrorHints[errNum]; ok {
+			fmt.Fprintf(&detail, "Hint: %s\n", hint)
+		}
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("%s: %v", summary, err),
+		Detail:   strings.TrimSuffix(detail.String(), "\n"),
+	}}
+}
+
+// rdsAccessDeniedErrCodes are the errors of statements that need privileges RDS doesn't grant,
+// like SUPER.
+var rdsAccessDeniedErrCodes = map[uint16]bool{1044: true, 1045: true, 1227: true}
+
+// rdsErrorDiag is sqlErrorDiag with the RDS equivalent of the statement as a hint, when the
+// server is RDS and the statement failed for lack of privileges RDS doesn't grant.
+func rdsErrorDiag(ctx context.Context, meta interface{}, summary string, stmtSQL string, err error, hint func(aurora bool) string) diag.Diagnostics {
+	diags := sqlErrorDiag(summary, stmtSQL, err)
+	if !rdsAccessDeniedErrCodes[mysqlErrorNumber(err)] {
+		return diags
+	}
+	if rds, aurora := getRdsFromMeta(ctx, meta); rds {
+		diags[0].Detail += "\nOn RDS: " + hint(aurora)
+	}
+	return diags
+}
+
 func cloudsqlErrorNumber(err error) int {
 	if err == nil {
 		return 0
@@ -99,3 +215,139 @@ func cloudsqlErrorNumber(err error) int {
 	}
 	return 0
 }
+
+// quoteIdentifier quotes a database, table, column or other identifier with backticks, doubling
+// the backticks in it, so any name including spaces, dashes and unicode can be used.
+func quoteIdentifier(in string) string {
+	return fmt.Sprintf("`%s`", identQuoteReplacer.Replace(in))
+}
+
+var identQuoteReplacer = strings.NewReplacer("`", "``")
+
+// quotedNamePattern matches a name quoted with backticks, single quotes or, with ANSI_QUOTES,
+// double quotes as printed by the server, e.g. the user and host of SHOW CREATE USER, including
+// escaped quotes. unquoteName returns the name.
+const quotedNamePattern = "(`(?:[^`]|``)*`|'(?:[^'\\\\]|\\\\.|'')*'|\"(?:[^\"\\\\]|\\\\.|\"\")*\")"
+
+// cutQuotedName returns the first name of s, either quoted with backticks, single or double
+// quotes or bare, unquoted, and the rest of s after it. A bare name ends before any of
+// " @.,", so bare names like * and localhost can be parsed too.
+func cutQuotedName(s string) (string, string, error) {
+	if s == "" {
+		return "", "", fmt.Errorf("expected a name, got nothing")
+	}
+	quote := s[0]
+	if quote != '`' && quote != '\'' && quote != '"' {
+		end := strings.IndexAny(s, " @.,")
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			return "", "", fmt.Errorf("expected a name at %q", s)
+		}
+		return s[:end], s[end:], nil
+	}
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote != '`':
+			// Skip the escaped character.
+			i++
+		case s[i] == quote && i+1 < len(s) && s[i+1] == quote:
+			i++
+		case s[i] == quote:
+			return unquoteName(s[:i+1]), s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted name %q", s)
+}
+
+// unquoteName reverses quoteIdentifier, quoteLiteral or double quoting of a complete name, and
+// returns names which aren't quoted as they are.
+func unquoteName(s string) string {
+	if len(s) < 2 || s[0] != s[len(s)-1] {
+		return s
+	}
+	switch s[0] {
+	case '`':
+		return strings.ReplaceAll(s[1:len(s)-1], "``", "`")
+	case '\'':
+		return unquoteLiteral(s[1 : len(s)-1])
+	case '"':
+		return unquoteLiteral(strings.ReplaceAll(s[1:len(s)-1], `""`, `"`))
+	}
+	return s
+}
+
+// isQuotedIdentifier returns whether s is a single identifier quoted with backticks.
+func isQuotedIdentifier(s string) bool {
+	if !strings.HasPrefix(s, "`") {
+		return false
+	}
+	name, rest, err := cutQuotedName(s)
+	return err == nil && rest == "" && quoteIdentifier(name) == s
+}
+
+// splitQuotedList splits a list separated by commas, except for commas in quoted names.
+func splitQuotedList(list string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(list); i++ {
+		switch list[i] {
+		case '`', '\'', '"':
+			_, rest, err := cutQuotedName(list[i:])
+			if err != nil {
+				return append(items, list[start:])
+			}
+			i = len(list) - len(rest) - 1
+		case ',':
+			items = append(items, list[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, list[start:])
+}
+
+// quoteLiteral quotes a string literal, escaping backslashes, single quotes, NUL and Ctrl+Z like
+// the driver does when interpolating parameters.
+func quoteLiteral(in string) string {
+	return "'" + literalEscaper.Replace(in) + "'"
+}
+
+var literalEscaper = strings.NewReplacer(`\`, `\\`, "'", "''", "\x00", `\0`, "\x1a", `\Z`)
+
+// literalUnescapes are the escape sequences of the server other than an escaped character itself.
+var literalUnescapes = map[byte]byte{'0': 0, 'Z': 0x1a, 'n': '\n', 'r': '\r', 't': '\t', 'b': '\b'}
+
+// unquoteLiteral reverses the escaping of a single quoted literal as printed by the server.
+func unquoteLiteral(in string) string {
+	var sb strings.Builder
+	for i := 0; i < len(in); i++ {
+		switch {
+		case in[i] == '\\' && i+1 < len(in):
+			i++
+			if c, ok := literalUnescapes[in[i]]; ok {
+				sb.WriteByte(c)
+				continue
+			}
+		case in[i] == '\'' && i+1 < len(in) && in[i+1] == '\'':
+			i++
+		}
+		sb.WriteByte(in[i])
+	}
+	return sb.String()
+}
+
+// getRawConfigString returns the configured value of a string attribute, ignoring state
+// and diff suppression. Write-only values are never persisted, so this is the only way
+// to read them.
+func getRawConfigString(d *schema.ResourceData, key string) (string, diag.Diagnostics) {
+	value, diags := d.GetRawConfigAt(cty.GetAttrPath(key))
+	if diags.HasError() {
+		return "", diags
+	}
+	if !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+		return "", nil
+	}
+	return value.AsString(), nil
+}
@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestRedactSQL(t *testing.T) {
+	cases := map[string]string{
+		"CREATE USER 'jdoe'@'%' IDENTIFIED BY 'secret'":                                       "CREATE USER 'jdoe'@'%' IDENTIFIED BY '***'",
+		"ALTER USER 'jdoe'@'%' IDENTIFIED WITH mysql_native_password AS '*ABC''D'":            "ALTER USER 'jdoe'@'%' IDENTIFIED WITH mysql_native_password AS '***'",
+		"SET PASSWORD FOR 'jdoe'@'%' = PASSWORD('it\\'s')":                                    "SET PASSWORD FOR 'jdoe'@'%' = PASSWORD('***')",
+		"GRANT SELECT ON `db`.* TO 'jdoe'@'%'":                                                "GRANT SELECT ON `db`.* TO 'jdoe'@'%'",
+		"CREATE USER 'jdoe'@'%' IDENTIFIED WITH caching_sha2_password BY 'x' PASSWORD EXPIRE": "CREATE USER 'jdoe'@'%' IDENTIFIED WITH caching_sha2_password BY '***' PASSWORD EXPIRE",
+		"BACKUP LOGS TO 's3://bucket/logs?access-key=AK&secret-access-key=SK' START_TS = 1":   "BACKUP LOGS TO '***' START_TS = 1",
+	}
+
+	for stmt, expected := range cases {
+		if got := redactSQL(stmt); got != expected {
+			t.Errorf("redactSQL(%q) = %q, expected %q", stmt, got, expected)
+		}
+	}
+}
+
+func TestSqlErrorDiag(t *testing.T) {
+	err := &mysql.MySQLError{Number: 1227, Message: "Access denied; you need (at least one of) the SUPER privilege(s) for this operation"}
+	diags := sqlErrorDiag("error setting value", "SET GLOBAL `max_connections` = 10", err)
+
+	if len(diags) != 1 || diags[0].Severity != diag.Error {
+		t.Fatalf("expected a single error, got %v", diags)
+	}
+	if !strings.HasPrefix(diags[0].Summary, "error setting value: Error 1227") {
+		t.Errorf("unexpected summary %q", diags[0].Summary)
+	}
+	for _, expected := range []string{"Statement: SET GLOBAL `max_connections` = 10", "MySQL error number: 1227", "mysql_rds_config"} {
+		if !strings.Contains(diags[0].Detail, expected) {
+			t.Errorf("expected detail to contain %q, got %q", expected, diags[0].Detail)
+		}
+	}
+
+	diags = sqlErrorDiag("failed", "", errors.New("connection refused"))
+	if diags[0].Detail != "" {
+		t.Errorf("expected no detail for a non-server error, got %q", diags[0].Detail)
+	}
+}
+
+func TestQuoting(t *testing.T) {
+	hostile := "x'`; DROP USER root; -- \\"
+
+	if got, expected := quoteIdentifier(hostile), "`x'``; DROP USER root; -- \\`"; got != expected {
+		t.Errorf("quoteIdentifier = %q, expected %q", got, expected)
+	}
+	if got, expected := quoteLiteral(hostile), "'x''`; DROP USER root; -- \\\\'"; got != expected {
+		t.Errorf("quoteLiteral = %q, expected %q", got, expected)
+	}
+	if got, expected := quoteLiteral("a\x00b\x1a"), `'a\0b\Z'`; got != expected {
+		t.Errorf("quoteLiteral = %q, expected %q", got, expected)
+	}
+
+	for _, in := range []string{hostile, "a\x00b\x1ac\nd", "o'brien"} {
+		quoted := quoteLiteral(in)
+		if got := unquoteLiteral(quoted[1 : len(quoted)-1]); got != in {
+			t.Errorf("unquoteLiteral(quoteLiteral(%q)) = %q", in, got)
+		}
+	}
+
+	user := UserOrRole{Name: hostile, Host: "%'"}
+	if got, expected := user.SQLString(), "'x''`; DROP USER root; -- \\\\'@'%'''"; got != expected {
+		t.Errorf("SQLString = %q, expected %q", got, expected)
+	}
+	grant := &TablePrivilegeGrant{Database: "db`x", Table: "t`y", Privileges: []string{"SELECT"}, UserOrRole: user}
+	if got, expected := grant.SQLGrantStatement(), "GRANT SELECT ON `db``x`.`t``y` TO "+user.SQLString(); got != expected {
+		t.Errorf("SQLGrantStatement = %q, expected %q", got, expected)
+	}
+}
+
+func TestQuotedNamesRoundTrip(t *testing.T) {
+	hostile := []string{"app", "my-app", "my app", "my.app", "o'brien", "a`b", "a@b", "a,b", "ünïcødé", "日本語", `back\slash`, "x'`; DROP USER root; -- \\"}
+
+	for _, name := range hostile {
+		for _, quoted := range []string{quoteIdentifier(name), quoteLiteral(name)} {
+			got, rest, err := cutQuotedName(quoted + "@`%`")
+			if err != nil || got != name || rest != "@`%`" {
+				t.Errorf("cutQuotedName(%q) = %q, %q, %v, expected %q", quoted, got, rest, err, name)
+			}
+		}
+
+		// As printed by SHOW GRANTS.
+		row := fmt.Sprintf("GRANT SELECT ON %s.%s TO %s@%s WITH GRANT OPTION", quoteIdentifier(name), quoteIdentifier(name), quoteIdentifier(name), quoteIdentifier("10.0.%"))
+		grant, err := parseGrantFromRow(row)
+		if err != nil {
+			t.Errorf("parseGrantFromRow(%q): %v", row, err)
+			continue
+		}
+		tableGrant := grant.(*TablePrivilegeGrant)
+		if tableGrant.Database != name || tableGrant.Table != name || tableGrant.UserOrRole.Name != name || tableGrant.UserOrRole.Host != "10.0.%" {
+			t.Errorf("parseGrantFromRow(%q) = %#v", row, tableGrant)
+		}
+
+		roles := splitQuotedList(quoteIdentifier(name) + "@`%`," + quoteLiteral(name))
+		if len(roles) != 2 {
+			t.Errorf("splitQuotedList split %q into %q", name, roles)
+		}
+
+		if !isQuotedIdentifier(quoteIdentifier(name)) || isQuotedIdentifier(name) {
+			t.Errorf("isQuotedIdentifier(%q) mismatch", name)
+		}
+	}
+
+	createUser := "CREATE USER `o'b``x`@'10.0.%' IDENTIFIED VIA ed25519 USING 'abc'"
+	if m := kMariaDBCreateUserRegex.FindStringSubmatch(createUser); m == nil || unquoteName(m[1]) != "o'b`x" || unquoteName(m[2]) != "10.0.%" {
+		t.Errorf("kMariaDBCreateUserRegex didn't parse the account of %q: %q", createUser, m)
+	}
+
+	if _, _, err := cutQuotedName("`unterminated"); err == nil {
+		t.Errorf("expected an error for an unterminated name")
+	}
+	if got := unquoteName(`"say ""hi"""`); got != `say "hi"` {
+		t.Errorf("unquoteName = %q", got)
+	}
+}